@@ -0,0 +1,83 @@
+package octopus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Kamva/octopus/base"
+)
+
+// Sum returns aggregation summing values of `field`, which is returned in
+// `sum_<field>` column of aggregate results.
+func Sum(field string) base.Aggregation {
+	return newAggregation(base.SumFunc, field)
+}
+
+// Avg returns aggregation averaging values of `field`, which is returned in
+// `avg_<field>` column of aggregate results.
+func Avg(field string) base.Aggregation {
+	return newAggregation(base.AvgFunc, field)
+}
+
+// Min returns aggregation finding the minimum value of `field`, which is
+// returned in `min_<field>` column of aggregate results.
+func Min(field string) base.Aggregation {
+	return newAggregation(base.MinFunc, field)
+}
+
+// Max returns aggregation finding the maximum value of `field`, which is
+// returned in `max_<field>` column of aggregate results.
+func Max(field string) base.Aggregation {
+	return newAggregation(base.MaxFunc, field)
+}
+
+// Count returns aggregation counting records with a non-null value of
+// `field`, or all records if field is `*`. It's returned in `count_<field>`
+// column of aggregate results, or `count` column for counting records.
+func Count(field string) base.Aggregation {
+	return newAggregation(base.CountFunc, field)
+}
+
+// As returns `aggregation` with `alias` as name of its result column
+func As(aggregation base.Aggregation, alias string) base.Aggregation {
+	aggregation.Alias = alias
+
+	return aggregation
+}
+
+// newAggregation returns aggregation of `field` by `function` with the
+// default alias.
+func newAggregation(function base.AggregateFunc, field string) base.Aggregation {
+	alias := strings.ToLower(string(function))
+	if field != "*" {
+		alias += "_" + field
+	}
+
+	return base.Aggregation{Func: function, Field: field, Alias: alias}
+}
+
+// toFloat converts numeric aggregation result `value` to float. Databases
+// return decimal results, e.g. sums in PostgreSQL, as strings or bytes.
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case []byte:
+		return strconv.ParseFloat(string(v), 64)
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return 0, fmt.Errorf("%w: cannot convert %T result to float", base.ErrUnsupportedType, value)
+}
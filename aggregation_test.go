@@ -0,0 +1,95 @@
+package octopus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregations(t *testing.T) {
+	assert.Equal(t, base.Aggregation{Func: base.SumFunc, Field: "amount", Alias: "sum_amount"}, Sum("amount"))
+	assert.Equal(t, base.Aggregation{Func: base.AvgFunc, Field: "amount", Alias: "avg_amount"}, Avg("amount"))
+	assert.Equal(t, base.Aggregation{Func: base.MinFunc, Field: "amount", Alias: "min_amount"}, Min("amount"))
+	assert.Equal(t, base.Aggregation{Func: base.MaxFunc, Field: "amount", Alias: "max_amount"}, Max("amount"))
+	assert.Equal(t, base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"}, Count("*"))
+	assert.Equal(t, base.Aggregation{Func: base.SumFunc, Field: "amount", Alias: "total"}, As(Sum("amount"), "total"))
+	assert.Equal(t, "COUNT(*)", Count("*").Expression())
+}
+
+func TestBuilder_aggregate(t *testing.T) {
+	config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+	defer clients.DropMemoryDatabase(t.Name())
+
+	model := makeModel(&invoice{}, config)
+	for _, total := range []int64{1000, 2500, 500} {
+		assert.Nil(t, model.Create(&invoice{Total: money{total, "USD"}}))
+	}
+	assert.Nil(t, model.Create(&invoice{Total: money{700, "EUR"}}))
+
+	t.Run("aggregate", func(t *testing.T) {
+		rows, err := model.Where().
+			GroupBy("total").
+			Having(term.Equal{Field: "count", Value: 1}).
+			OrderBy(base.Sort{Column: "max_id", Descending: true}).
+			Limit(1).
+			Aggregate(Max("id"), Count("*"))
+
+		assert.Nil(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, "700 EUR", rows[0].Get("total"))
+	})
+
+	t.Run("scalars", func(t *testing.T) {
+		sum, err := model.Where().Sum("id")
+		assert.Nil(t, err)
+		assert.Equal(t, float64(10), sum)
+
+		avg, err := model.Where(term.LessThan{Field: "id", Value: 3}).Avg("id")
+		assert.Nil(t, err)
+		assert.Equal(t, 1.5, avg)
+
+		min, err := model.Where().Min("id")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), min)
+
+		max, err := model.Where().Max("id")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), max)
+	})
+
+	t.Run("noRecord", func(t *testing.T) {
+		sum, err := model.Where(term.GreaterThan{Field: "id", Value: 10}).Sum("id")
+		assert.Nil(t, err)
+		assert.Equal(t, float64(0), sum)
+
+		max, err := model.Where(term.GreaterThan{Field: "id", Value: 10}).Max("id")
+		assert.Nil(t, err)
+		assert.Nil(t, max)
+	})
+
+	t.Run("invalidResult", func(t *testing.T) {
+		_, err := model.Where().Sum("total")
+
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	})
+}
+
+func TestToFloat(t *testing.T) {
+	for _, value := range []interface{}{2.5, float32(2.5), "2.5", []byte("2.5")} {
+		f, err := toFloat(value)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2.5, f)
+	}
+
+	f, err := toFloat(int64(2))
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), f)
+
+	_, err = toFloat(true)
+	assert.True(t, errors.Is(err, ErrUnsupportedType))
+}
@@ -0,0 +1,80 @@
+package base
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Codec converts values of a scheme field type to the values stored in
+// database and back, and determines type of columns storing the field.
+type Codec interface {
+	// ColumnType returns type of the column storing fields of type `t`
+	// in tables of `driver` database, regarding field sql `tags`.
+	ColumnType(driver DriverName, t reflect.Type, tags SQLTag) string
+
+	// Encode converts the field `value` to the value stored in database
+	Encode(value interface{}) (interface{}, error)
+
+	// Decode converts `value` returned by database to the field value
+	// and sets it on `field`.
+	Decode(value interface{}, field reflect.Value) error
+}
+
+// codecs is the registry of codecs by the type of values they convert, and
+// by the kind of types which have no codec registered for them.
+var codecs = struct {
+	sync.RWMutex
+	types map[reflect.Type]Codec
+	kinds map[reflect.Kind]Codec
+}{types: make(map[reflect.Type]Codec), kinds: make(map[reflect.Kind]Codec)}
+
+// RegisterCodec registers `codec` for converting values of type `t`, which
+// replaces the codec registered for the type before, if any.
+func RegisterCodec(t reflect.Type, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.types[t] = codec
+}
+
+// LookupCodec returns the codec registered for type `t`, or nil if there
+// is no codec registered for it.
+func LookupCodec(t reflect.Type) Codec {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	return codecs.types[t]
+}
+
+// RegisterKindCodec registers `codec` for converting values of types of kind
+// `k` which have no codec registered for them, and replaces the codec
+// registered for the kind before, if any.
+func RegisterKindCodec(k reflect.Kind, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+
+	codecs.kinds[k] = codec
+}
+
+// LookupKindCodec returns the codec registered for kind `k`, or nil if there
+// is no codec registered for it.
+func LookupKindCodec(k reflect.Kind) Codec {
+	codecs.RLock()
+	defer codecs.RUnlock()
+
+	return codecs.kinds[k]
+}
+
+// EncodeValue encodes `value` by the codec registered for its type, and
+// returns value as is if no codec is registered for it.
+func EncodeValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if codec := LookupCodec(reflect.TypeOf(value)); codec != nil {
+		return codec.Encode(value)
+	}
+
+	return value, nil
+}
@@ -1,24 +1,36 @@
 package base
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
-// driverName is string alias for determining driver name
-type driverName string
+// DriverName is string alias for determining driver name
+type DriverName string
 
 const (
 	// Mongo represent driver name for mongodb driver
-	Mongo driverName = "mongo"
+	Mongo DriverName = "mongo"
 
 	// PG represent driver name for PostgreSQL
-	PG driverName = "pg"
+	PG DriverName = "pg"
 
 	// MSSQL represent driver name for Microsoft SQL Server
-	MSSQL driverName = "mssql"
+	MSSQL DriverName = "mssql"
+
+	// MySQL represent driver name for MySQL and MariaDB
+	MySQL DriverName = "mysql"
+
+	// SQLite represent driver name for SQLite3
+	SQLite DriverName = "sqlite3"
+
+	// Memory represent driver name for in-memory database, used for testing
+	Memory DriverName = "memory"
 )
 
 // DBConfig is the connection settings and options
 type DBConfig struct {
-	Driver   driverName
+	Driver   DriverName
 	Host     string
 	Port     string
 	Database string
@@ -26,6 +38,23 @@ type DBConfig struct {
 	Password string
 	Prefix   string
 	Options  map[string]string
+	Pool     PoolConfig
+}
+
+// PoolConfig is the settings of connection pool shared between models
+// connecting to the same database. Zero values leave driver defaults.
+type PoolConfig struct {
+	// Maximum number of open connections of SQL databases
+	MaxOpenConns int
+
+	// Maximum number of idle connections kept in pool of SQL databases
+	MaxIdleConns int
+
+	// Maximum amount of time a connection of SQL databases may be reused
+	ConnMaxLifetime time.Duration
+
+	// Maximum number of sockets in use of MongoDB servers
+	PoolLimit int
 }
 
 // HasPrefix Check if any table/collection prefix is set
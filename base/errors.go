@@ -0,0 +1,64 @@
+package base
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when no record/document matches the query
+	ErrNotFound = errors.New("no result found")
+
+	// ErrDuplicateKey is returned when a record/document violates a
+	// unique index or primary key.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrConstraintViolation is returned when a record violates a
+	// constraint of table other than unique indices, e.g. foreign
+	// keys, not null columns or check constraints.
+	ErrConstraintViolation = errors.New("constraint violation")
+
+	// ErrUnsupportedType is returned when a value or field type is not
+	// supported by octopus or the database.
+	ErrUnsupportedType = errors.New("unsupported type")
+
+	// ErrInvalidID is returned when an ID is not valid for the database,
+	// e.g. a string which is not an ObjectId hex for MongoDB.
+	ErrInvalidID = errors.New("invalid id")
+
+	// ErrInvalidDriver is returned when database driver of config is
+	// not supported.
+	ErrInvalidDriver = errors.New("invalid database driver")
+
+	// ErrInvalidTx is returned by operations of a model bound to a nil
+	// transaction or a transaction on another database, and operations
+	// which could not run in transactions.
+	ErrInvalidTx = errors.New("invalid transaction")
+
+	// ErrValidation is returned when a scheme fails validation rules
+	// before it is created or updated.
+	ErrValidation = errors.New("validation failed")
+)
+
+// Error is an error of database driver which is mapped to one of octopus
+// errors. It can be checked against the octopus error with errors.Is, and
+// the original driver error is accessible with errors.As.
+type Error struct {
+	// Kind is the octopus error that the driver error is mapped to
+	Kind error
+
+	// Cause is the original error of database driver
+	Cause error
+}
+
+// Error returns message of the octopus error and the original driver error
+func (e *Error) Error() string {
+	return e.Kind.Error() + ": " + e.Cause.Error()
+}
+
+// Is reports whether the error is mapped to `target` octopus error
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// Unwrap returns the original driver error
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
@@ -33,6 +33,31 @@ type SQLDatabase interface {
 	Stats() sql.DBStats
 }
 
+// SQLExecutor is an interface for executing statements which is satisfied
+// by both sql.DB and sql.Tx, so queries run in or out of a transaction.
+type SQLExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQLTx is an interface for sql.Tx and used for testing and mocking
+type SQLTx interface {
+	Commit() error
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	Prepare(query string) (*sql.Stmt, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	Rollback() error
+	Stmt(stmt *sql.Stmt) *sql.Stmt
+	StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt
+}
+
 // SQLRows is an interface for sql.Rows and used for testing and mocking
 type SQLRows interface {
 	Close() error
@@ -136,3 +161,28 @@ type MongoQuery interface {
 	Sort(fields ...string) *mgo.Query
 	Tail(timeout time.Duration) *mgo.Iter
 }
+
+// MongoFindOptions are options of finding documents in a MongoTxSession
+type MongoFindOptions struct {
+	Sort       bson.D
+	Skip       int
+	Limit      int
+	Projection bson.M
+}
+
+// MongoTxSession is an interface for a session of the official MongoDB
+// driver which runs a transaction, as mgo does not support transactions.
+// Its filters, documents and results are bson values of mgo, and used for
+// testing and mocking.
+type MongoTxSession interface {
+	Find(collection string, filter bson.M, opts MongoFindOptions) ([]bson.M, error)
+	Count(collection string, filter bson.M) (int, error)
+	Distinct(collection string, field string, filter bson.M) ([]interface{}, error)
+	Aggregate(collection string, pipeline []bson.M) ([]bson.M, error)
+	Insert(collection string, document interface{}) error
+	Update(collection string, filter bson.M, update bson.M) (int, error)
+	Delete(collection string, filter bson.M) (int, error)
+	Run(command interface{}) (bson.M, error)
+	Commit() error
+	Abort() error
+}
@@ -1,5 +1,7 @@
 package base
 
+import "context"
+
 // Client is an interface for database clients. Database clients are
 // responsible with connecting and interacting with database instance.
 type Client interface {
@@ -16,19 +18,24 @@ type Client interface {
 	// Insert tries to insert `data` into `tableName` and returns error if
 	// anything went wrong. `data` should pass by reference to have exact
 	// data on `tableName`, otherwise updated record data isn't accessible.
-	Insert(tableName string, data *RecordData) error
+	// `key` is the primary key of table, which is generated if it is a
+	// single column and not set in `data`.
+	Insert(tableName string, key Key, data *RecordData) error
 
 	// FindByID searches through `tableName` records to find a row that its
-	// ID match with `id` and returns it alongside any possible error.
-	FindByID(tableName string, id interface{}) (RecordData, error)
+	// `key` primary key match with `id` and returns it alongside any
+	// possible error.
+	FindByID(tableName string, key Key, id interface{}) (RecordData, error)
 
-	// UpdateByID finds a record in `tableName` that its ID match with `id`,
-	// and updates it with data. It will return error if anything went wrong.
-	UpdateByID(tableName string, id interface{}, data RecordData) error
+	// UpdateByID finds a record in `tableName` that its `key` primary key
+	// match with `id`, and updates it with data. It will return error if
+	// anything went wrong.
+	UpdateByID(tableName string, key Key, id interface{}, data RecordData) error
 
-	// DeleteByID finds a record in `tableName` that its ID match with `id`,
-	// and remove it entirely. It will return error if anything went wrong.
-	DeleteByID(tableName string, id interface{}) error
+	// DeleteByID finds a record in `tableName` that its `key` primary key
+	// match with `id`, and remove it entirely. It will return error if
+	// anything went wrong.
+	DeleteByID(tableName string, key Key, id interface{}) error
 
 	// Query generates and returns query object for further operations
 	Query(tableName string, conditions ...Condition) QueryBuilder
@@ -37,6 +44,83 @@ type Client interface {
 	Close()
 }
 
+// ConnectionPool is an interface for clients holding a pool of connections
+// which is shared between the clients acquired from it. Closing an acquired
+// client releases its connections back to the pool, and closing the pool
+// client closes all of its connections.
+type ConnectionPool interface {
+	Client
+
+	// ConfigurePool applies connection pool settings of `config`
+	ConfigurePool(config PoolConfig)
+
+	// Acquire returns a client using connections of the pool
+	Acquire() Client
+}
+
+// ContextBinder is an interface for clients supporting context, which
+// propagate cancellation and deadline of context to the database.
+type ContextBinder interface {
+
+	// WithContext returns a copy of client which runs all of its
+	// operations with `ctx`.
+	WithContext(ctx context.Context) Client
+}
+
+// ColumnsBinder is an interface for query builders which need columns of the
+// queried table, as SQL queries select all columns except omitted ones by
+// listing them.
+type ColumnsBinder interface {
+
+	// BindColumns sets columns of the queried table
+	BindColumns(columns []string)
+}
+
+// Transactional is an interface for clients supporting transactions
+type Transactional interface {
+
+	// Begin starts a new transaction and returns a client bound to it,
+	// which runs all of its operations in the transaction.
+	Begin() (TxClient, error)
+}
+
+// TxClient is a client bound to a database transaction. Its operations
+// are visible to others only after the transaction is committed.
+type TxClient interface {
+	Client
+
+	// Commit commits the transaction
+	Commit() error
+
+	// Rollback aborts the transaction and discards its changes
+	Rollback() error
+}
+
+// RawQuerier is an interface for clients supporting raw queries, which are
+// written in the query language of database.
+type RawQuerier interface {
+
+	// Raw returns a query fetching records of raw `query` with `args`.
+	// SQL queries are strings, and MongoDB queries are filters of
+	// documents in `tableName` collection.
+	Raw(tableName string, query interface{}, args ...interface{}) RawQuery
+
+	// Exec executes raw `command` with `args` and returns number of
+	// affected records. SQL commands are strings, and MongoDB commands
+	// are database commands.
+	Exec(command interface{}, args ...interface{}) (int, error)
+}
+
+// RawQuery is a raw query which fetches records from database
+type RawQuery interface {
+
+	// First fetch data of the first record resulted from the query
+	First() (RecordData, error)
+
+	// All returns all records resulted from the query in RecordDataSet format
+	All() (RecordDataSet, error)
+}
+
 // QueryBuilder is an object that contains information about query. With QueryBuilder
 // you can fetch, update and delete records from database.
 type QueryBuilder interface {
@@ -51,11 +135,41 @@ type QueryBuilder interface {
 	// Skip set the starting offset of the following fetch command
 	Skip(n int) QueryBuilder
 
+	// Select limits fields fetched by the following fetch command to `fields`
+	Select(fields ...string) QueryBuilder
+
+	// Omit excludes `fields` from fields fetched by the following fetch command
+	Omit(fields ...string) QueryBuilder
+
+	// GroupBy groups records by values of `fields` in the following
+	// aggregate command.
+	GroupBy(fields ...string) QueryBuilder
+
+	// Having filters groups of the following aggregate command by
+	// `conditions`, which fields could be aliases of aggregations.
+	Having(conditions ...Condition) QueryBuilder
+
 	// Count execute a count command that will return the number records in
 	// specified destination table. If the query conditions was empty, it
 	// returns number of all records un destination table.
 	Count() (int, error)
 
+	// CountDistinct execute a count command that will return the number of
+	// distinct values of `field` in records matching the query conditions.
+	CountDistinct(field string) (int, error)
+
+	// Distinct returns distinct values of `field` in records matching the
+	// query conditions.
+	Distinct(field string) ([]interface{}, error)
+
+	// Aggregate returns a row of grouping fields and `aggregations` for each
+	// group of records matching the query conditions. All records matching
+	// the conditions are aggregated in one row if no grouping field is set.
+	Aggregate(aggregations ...Aggregation) (RecordDataSet, error)
+
+	// Exists checks whether any record matches with the query conditions
+	Exists() (bool, error)
+
 	// First fetch data of the first record that match with query conditions.
 	First() (RecordData, error)
 
@@ -127,11 +241,59 @@ type Builder interface {
 	// Skip set the starting offset of the following fetch command
 	Skip(n int) Builder
 
+	// Select limits fields fetched by the following fetch command to `fields`,
+	// and other fields of fetched schemes are left at their zero value.
+	Select(fields ...string) Builder
+
+	// Omit excludes `fields` from fields fetched by the following fetch
+	// command, which are left at their zero value on fetched schemes.
+	Omit(fields ...string) Builder
+
+	// GroupBy groups records by values of `fields` in the following
+	// aggregate command.
+	GroupBy(fields ...string) Builder
+
+	// Having filters groups of the following aggregate command by
+	// `conditions`, which fields could be aliases of aggregations.
+	Having(conditions ...Condition) Builder
+
 	// Count execute a count command that will return the number records in
 	// specified destination table. If the query conditions was empty, it
 	// returns number of all records un destination table.
 	Count() (int, error)
 
+	// CountDistinct execute a count command that will return the number of
+	// distinct values of `field` in records matching the query conditions.
+	CountDistinct(field string) (int, error)
+
+	// Distinct returns distinct values of `field` in records matching the
+	// query conditions.
+	Distinct(field string) ([]interface{}, error)
+
+	// Aggregate returns a row of grouping fields and `aggregations` for each
+	// group of records matching the query conditions. All records matching
+	// the conditions are aggregated in one row if no grouping field is set.
+	Aggregate(aggregations ...Aggregation) (RecordDataSet, error)
+
+	// Sum returns sum of `field` values in records matching the query
+	// conditions.
+	Sum(field string) (float64, error)
+
+	// Avg returns average of `field` values in records matching the query
+	// conditions.
+	Avg(field string) (float64, error)
+
+	// Min returns the minimum `field` value in records matching the query
+	// conditions, or nil if there is no record.
+	Min(field string) (interface{}, error)
+
+	// Max returns the maximum `field` value in records matching the query
+	// conditions, or nil if there is no record.
+	Max(field string) (interface{}, error)
+
+	// Exists checks whether any record matches with the query conditions
+	Exists() (bool, error)
+
 	// First fetch data of the first record that match with query conditions.
 	First() (Scheme, error)
 
@@ -149,6 +311,16 @@ type Builder interface {
 	// Delete removes every records in destination table that match with condition
 	// query and returns number of affected rows and error if anything went wrong.
 	// It will removes all records inside destination table if no condition query
-	// was set.
+	// was set. Records of soft deleted schemes are not removed, and their deletion
+	// time is set instead.
 	Delete() (int, error)
+
+	// ForceDelete removes every records in destination table that match with
+	// condition query, even if the scheme is soft deleted, and returns number
+	// of affected rows and error if anything went wrong.
+	ForceDelete() (int, error)
+
+	// Restore clears deletion time of soft deleted records that match with
+	// condition query and returns number of affected rows.
+	Restore() (int, error)
 }
@@ -42,11 +42,11 @@ func (d *RecordData) GetColumns() []string {
 	return d.keys
 }
 
-// GetValues returns list of values enquoted by `enquoter`
-func (d *RecordData) GetValues(enquoter Enquoter) []string {
-	values := make([]string, 0, d.Length())
+// GetValues returns list of values in order of columns
+func (d *RecordData) GetValues() []interface{} {
+	values := make([]interface{}, 0, d.Length())
 	for _, col := range d.keys {
-		values = append(values, enquoter(d.data[col]))
+		values = append(values, d.data[col])
 	}
 
 	return values
@@ -68,6 +68,21 @@ func (d *RecordData) Set(key string, value interface{}) {
 	d.data[key] = value
 }
 
+// Unset removes `key` from record data map if it exists
+func (d *RecordData) Unset(key string) {
+	if _, ok := d.data[key]; !ok {
+		return
+	}
+
+	delete(d.data, key)
+	for i, k := range d.keys {
+		if k == key {
+			d.keys = append(d.keys[:i], d.keys[i+1:]...)
+			break
+		}
+	}
+}
+
 // Zero will empty all fields of record data
 func (d *RecordData) Zero() {
 	d.data = make(RecordMap)
@@ -7,8 +7,43 @@ import (
 	"github.com/globalsign/mgo"
 )
 
-// Enquoter is function alias for clients enquoting operation
-type Enquoter func(i interface{}) string
+// Placeholder is function alias for clients generating the bind parameter
+// placeholder of nth argument of a query (e.g. `$1` in PostgreSQL).
+type Placeholder func(n int) string
+
+// Converter is function alias for clients converting a value to a
+// type that is acceptable by the driver as a query argument.
+type Converter func(i interface{}) interface{}
+
+// MatchMode determines how a field is matched with a pattern or values
+type MatchMode int
+
+const (
+	// LikeMatch matches field with a LIKE pattern
+	LikeMatch MatchMode = iota
+
+	// ILikeMatch matches field with a LIKE pattern case insensitively
+	ILikeMatch
+
+	// RegexMatch matches field with a regular expression
+	RegexMatch
+
+	// ArrayContainsMatch matches array field containing all of values
+	ArrayContainsMatch
+
+	// ArrayOverlapsMatch matches array field containing any of values
+	ArrayOverlapsMatch
+)
+
+// Matcher is function alias for clients generating the expression that
+// matches `field` with the bound `pattern` placeholder in given `mode`.
+// It panics if the database does not support the match mode.
+type Matcher func(field string, pattern string, mode MatchMode) string
+
+// Paginator is function alias for clients generating the clause that limits
+// fetched records to `limit` records after skipping `offset` records. Zero
+// `limit` or `offset` means no limit or no skip.
+type Paginator func(limit int, offset int) string
 
 // Index is a struct for declaring columns to be indexed.
 // Indexes can have multiple columns (composite index)
@@ -23,6 +58,34 @@ type Index struct {
 	Unique bool
 }
 
+// Key is the primary key of a table, which has a single column or multiple
+// columns for composite keys. Records of a table with composite key are
+// identified by a tuple (`[]interface{}`) of values in order of columns.
+type Key []string
+
+// IsComposite checks whether the key has multiple columns
+func (k Key) IsComposite() bool {
+	return len(k) > 1
+}
+
+// Values returns values of key columns from record `id`, which should be a
+// tuple of values for composite keys.
+func (k Key) Values(id interface{}) ([]interface{}, error) {
+	if !k.IsComposite() {
+		return []interface{}{id}, nil
+	}
+
+	values, ok := id.([]interface{})
+	if !ok || len(values) != len(k) {
+		return nil, fmt.Errorf(
+			"%w: key (%s) needs a tuple of %d values",
+			ErrInvalidID, strings.Join(k, ", "), len(k),
+		)
+	}
+
+	return values, nil
+}
+
 // FieldStructure is representing a field structure in a table
 type FieldStructure struct {
 	Name     string
@@ -39,6 +102,15 @@ func (s FieldStructure) String() string {
 	return strings.TrimRight(fmt.Sprintf("%s %s %s", s.Name, s.Type, s.Options), " ")
 }
 
+// NewConstraint returns a FieldStructure of a table constraint, e.g.
+// `PRIMARY KEY (a, b)`, which is placed in table structure as is.
+func NewConstraint(constraint string) FieldStructure {
+	return FieldStructure{
+		Name:     constraint,
+		stringer: func(s FieldStructure) string { return s.Name },
+	}
+}
+
 // TableStructure is representing structure of a table fields
 type TableStructure []FieldStructure
 
@@ -63,6 +135,44 @@ type Sort struct {
 	Descending bool
 }
 
+// AggregateFunc is the function of an aggregation on grouped records
+type AggregateFunc string
+
+const (
+	// SumFunc sums values of field
+	SumFunc AggregateFunc = "SUM"
+
+	// AvgFunc averages values of field
+	AvgFunc AggregateFunc = "AVG"
+
+	// MinFunc finds the minimum value of field
+	MinFunc AggregateFunc = "MIN"
+
+	// MaxFunc finds the maximum value of field
+	MaxFunc AggregateFunc = "MAX"
+
+	// CountFunc counts records, or non-null values of field
+	CountFunc AggregateFunc = "COUNT"
+)
+
+// Aggregation is a struct for declaring an aggregated value of grouped
+// records, which is returned in the result column named Alias.
+type Aggregation struct {
+	// Func is the aggregate function
+	Func AggregateFunc
+
+	// Field is the name of aggregated field, or `*` for counting records
+	Field string
+
+	// Alias is the name of result column
+	Alias string
+}
+
+// Expression returns SQL expression of the aggregation, e.g. `SUM(amount)`
+func (a Aggregation) Expression() string {
+	return fmt.Sprintf("%s(%s)", a.Func, a.Field)
+}
+
 // CollectionInfo is a wrapper for mgo.CollectionInfo that make it
 // compatible with TableInfo interface for using in clients.
 type CollectionInfo struct {
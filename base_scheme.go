@@ -1,5 +1,7 @@
 package octopus
 
+import "time"
+
 // Scheme is the base scheme that other schemes can embed to have default methods
 type Scheme struct{}
 
@@ -15,3 +17,18 @@ type MongoScheme struct{}
 func (s MongoScheme) GetKeyName() string {
 	return "_id"
 }
+
+// Timestamps is the base scheme for tracking record creation and update
+// times, that schemes can embed next to Scheme. Its fields are set on
+// create and update operations.
+type Timestamps struct {
+	CreatedAt time.Time `sql:"created_at"`
+	UpdatedAt time.Time `sql:"updated_at"`
+}
+
+// SoftDelete is the base scheme for soft deleting records, that schemes can
+// embed next to Scheme. Deleting these records sets their deletion time
+// instead of removing them, and deleted records are excluded from queries.
+type SoftDelete struct {
+	DeletedAt *time.Time `sql:"deleted_at;null"`
+}
@@ -1,6 +1,7 @@
 package octopus
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/Kamva/octopus/base"
@@ -11,6 +12,10 @@ import (
 type Builder struct {
 	builder base.QueryBuilder
 	model   *Model
+
+	// err is the error of preparing the query, which is returned by
+	// the following fetch, update or delete command.
+	err error
 }
 
 // NewBuilder instantiate Builder with given QueryBuilder
@@ -18,9 +23,16 @@ func NewBuilder(builder base.QueryBuilder, model *Model) *Builder {
 	return &Builder{builder: builder, model: model}
 }
 
+// newErrorBuilder instantiate Builder which returns `err` on all commands
+func newErrorBuilder(err error, model *Model) *Builder {
+	return &Builder{model: model, err: err}
+}
+
 // OrderBy set the order of returning result in following command
 func (b *Builder) OrderBy(sorts ...base.Sort) base.Builder {
-	b.builder = b.builder.OrderBy(sorts...)
+	if b.err == nil {
+		b.builder = b.builder.OrderBy(sorts...)
+	}
 
 	return b
 }
@@ -28,14 +40,63 @@ func (b *Builder) OrderBy(sorts ...base.Sort) base.Builder {
 // Limit set the limit that determines how many results should be
 // returned in the following fetch command.
 func (b *Builder) Limit(n int) base.Builder {
-	b.builder = b.builder.Limit(n)
+	if b.err == nil {
+		b.builder = b.builder.Limit(n)
+	}
 
 	return b
 }
 
 // Skip set the starting offset of the following fetch command
 func (b *Builder) Skip(n int) base.Builder {
-	b.builder = b.builder.Skip(n)
+	if b.err == nil {
+		b.builder = b.builder.Skip(n)
+	}
+
+	return b
+}
+
+// Select limits fields fetched by the following fetch command to `fields`,
+// and other fields of fetched schemes are left at their zero value.
+func (b *Builder) Select(fields ...string) base.Builder {
+	if b.err == nil {
+		b.builder = b.builder.Select(fields...)
+	}
+
+	return b
+}
+
+// Omit excludes `fields` from fields fetched by the following fetch command,
+// which are left at their zero value on fetched schemes. Columns of scheme
+// are bound to query builders needing them, so omitted columns are never
+// fetched from database.
+func (b *Builder) Omit(fields ...string) base.Builder {
+	if b.err == nil {
+		if binder, ok := b.builder.(base.ColumnsBinder); ok {
+			binder.BindColumns(getColumns(b.model.scheme))
+		}
+		b.builder = b.builder.Omit(fields...)
+	}
+
+	return b
+}
+
+// GroupBy groups records by values of `fields` in the following aggregate
+// command.
+func (b *Builder) GroupBy(fields ...string) base.Builder {
+	if b.err == nil {
+		b.builder = b.builder.GroupBy(fields...)
+	}
+
+	return b
+}
+
+// Having filters groups of the following aggregate command by `conditions`,
+// which fields could be aliases of aggregations.
+func (b *Builder) Having(conditions ...base.Condition) base.Builder {
+	if b.err == nil {
+		b.builder = b.builder.Having(conditions...)
+	}
 
 	return b
 }
@@ -43,22 +104,135 @@ func (b *Builder) Skip(n int) base.Builder {
 // Count execute a count command that will return the number records in
 // specified destination table. If the query conditions was empty, it
 // returns number of all records un destination table.
-func (b *Builder) Count() (int, error) {
+func (b *Builder) Count() (n int, err error) {
+	defer handleError(&err)
 	defer b.model.CloseClient()
 
+	if b.err != nil {
+		return 0, b.err
+	}
+
 	return b.builder.Count()
 }
 
+// CountDistinct execute a count command that will return the number of
+// distinct values of `field` in records matching the query conditions.
+func (b *Builder) CountDistinct(field string) (n int, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	return b.builder.CountDistinct(field)
+}
+
+// Distinct returns distinct values of `field` in records matching the query
+// conditions.
+func (b *Builder) Distinct(field string) (values []interface{}, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.builder.Distinct(field)
+}
+
+// Aggregate returns a row of grouping fields and `aggregations` for each
+// group of records matching the query conditions. All records matching the
+// conditions are aggregated in one row if no grouping field is set.
+func (b *Builder) Aggregate(aggregations ...base.Aggregation) (rows base.RecordDataSet, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	return b.builder.Aggregate(aggregations...)
+}
+
+// Sum returns sum of `field` values in records matching the query conditions
+func (b *Builder) Sum(field string) (sum float64, err error) {
+	value, err := b.aggregateValue(Sum(field))
+	if err != nil {
+		return 0, err
+	}
+
+	return toFloat(value)
+}
+
+// Avg returns average of `field` values in records matching the query
+// conditions.
+func (b *Builder) Avg(field string) (avg float64, err error) {
+	value, err := b.aggregateValue(Avg(field))
+	if err != nil {
+		return 0, err
+	}
+
+	return toFloat(value)
+}
+
+// Min returns the minimum `field` value in records matching the query
+// conditions, or nil if there is no record.
+func (b *Builder) Min(field string) (interface{}, error) {
+	return b.aggregateValue(Min(field))
+}
+
+// Max returns the maximum `field` value in records matching the query
+// conditions, or nil if there is no record.
+func (b *Builder) Max(field string) (interface{}, error) {
+	return b.aggregateValue(Max(field))
+}
+
+// aggregateValue returns value of `aggregation` on all records matching the
+// query conditions.
+func (b *Builder) aggregateValue(aggregation base.Aggregation) (interface{}, error) {
+	rows, err := b.Aggregate(aggregation)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	return rows[0].Get(aggregation.Alias), nil
+}
+
+// Exists checks whether any record matches with the query conditions
+func (b *Builder) Exists() (exists bool, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return false, b.err
+	}
+
+	return b.builder.Exists()
+}
+
 // First fetch data of the first record that match with query conditions.
-func (b *Builder) First() (base.Scheme, error) {
+func (b *Builder) First() (scheme base.Scheme, err error) {
+	defer handleError(&err)
 	defer b.model.CloseClient()
 
+	if b.err != nil {
+		return nil, b.err
+	}
+
 	data, err := b.builder.First()
 	if err != nil {
 		return nil, err
 	}
 
-	fillScheme(b.model.scheme, *data.GetMap())
+	resetScheme(b.model.scheme)
+	if err = fillScheme(b.model.scheme, *data.GetMap()); err != nil {
+		return nil, err
+	}
+
+	if err = runHook(b.model.scheme, afterFind); err != nil {
+		return nil, err
+	}
 
 	return b.model.scheme, nil
 }
@@ -66,18 +240,27 @@ func (b *Builder) First() (base.Scheme, error) {
 // All returns results that match with query conditions in RecordDataSet
 // format. If the query conditions was empty it will return all records
 // in specified destination table or error if anything went wrong.
-func (b *Builder) All() ([]base.Scheme, error) {
+func (b *Builder) All() (schemeSet []base.Scheme, err error) {
+	defer handleError(&err)
 	defer b.model.CloseClient()
 
+	if b.err != nil {
+		return nil, b.err
+	}
+
 	dataSet, err := b.builder.All()
 	if err != nil {
 		return nil, err
 	}
 
-	var schemeSet []base.Scheme
 	for _, data := range dataSet {
 		scheme := reflect.New(reflect.ValueOf(b.model.scheme).Elem().Type()).Interface().(base.Scheme)
-		fillScheme(scheme, *data.GetMap())
+		if err = fillScheme(scheme, *data.GetMap()); err != nil {
+			return nil, err
+		}
+		if err = runHook(scheme, afterFind); err != nil {
+			return nil, err
+		}
 		schemeSet = append(schemeSet, scheme)
 	}
 
@@ -88,20 +271,128 @@ func (b *Builder) All() ([]base.Scheme, error) {
 // returns number of affected rows and error if anything went wring. If
 // the query condition was empty it'll update all records in destination
 // table.
-func (b *Builder) Update(data base.Scheme) (int, error) {
+func (b *Builder) Update(data base.Scheme) (n int, err error) {
+	defer handleError(&err)
 	defer b.model.CloseClient()
 
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	if err = runHook(data, beforeUpdate); err != nil {
+		return 0, err
+	}
+
+	if err = validate(data); err != nil {
+		return 0, err
+	}
+
+	setTimestamps(data, false)
 	recordData := generateRecordData(data, false)
 
-	return b.builder.Update(*recordData)
+	if n, err = b.builder.Update(*recordData); err != nil {
+		return n, err
+	}
+
+	return n, runHook(data, afterUpdate)
 }
 
 // Delete removes every records in destination table that match with condition
 // query and returns number of affected rows and error if anything went wrong.
 // It will removes all records inside destination table if no condition query
-// was set.
-func (b *Builder) Delete() (int, error) {
+// was set. Records of soft deleted schemes are not removed, and their deletion
+// time is set instead. Matching records are fetched for running delete hooks
+// if scheme implements them.
+func (b *Builder) Delete() (n int, err error) {
+	defer handleError(&err)
 	defer b.model.CloseClient()
 
-	return b.builder.Delete()
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	return b.runDeleteHooks(func() (int, error) {
+		if _, column := getDeletedAtField(b.model.scheme); column != "" {
+			now := currentTime()
+			return b.builder.Update(deletedAtData(column, &now))
+		}
+
+		return b.builder.Delete()
+	})
+}
+
+// ForceDelete removes every records in destination table that match with
+// condition query, even if the scheme is soft deleted, and returns number
+// of affected rows and error if anything went wrong. Delete hooks are run
+// the same as Delete.
+func (b *Builder) ForceDelete() (n int, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	return b.runDeleteHooks(b.builder.Delete)
+}
+
+// runDeleteHooks runs `remove` between BeforeDelete and AfterDelete hooks of
+// records matching the query. Records are fetched only if scheme implements
+// a delete hook, and nothing is removed if a BeforeDelete hook fails.
+func (b *Builder) runDeleteHooks(remove func() (int, error)) (int, error) {
+	_, before := b.model.scheme.(BeforeDeleteHook)
+	_, after := b.model.scheme.(AfterDeleteHook)
+	if !before && !after {
+		return remove()
+	}
+
+	dataSet, err := b.builder.All()
+	if err != nil {
+		return 0, err
+	}
+
+	schemes := make([]base.Scheme, 0, len(dataSet))
+	for _, data := range dataSet {
+		scheme := reflect.New(reflect.ValueOf(b.model.scheme).Elem().Type()).Interface().(base.Scheme)
+		if err = fillScheme(scheme, *data.GetMap()); err != nil {
+			return 0, err
+		}
+		if err = runHook(scheme, beforeDelete); err != nil {
+			return 0, err
+		}
+		schemes = append(schemes, scheme)
+	}
+
+	n, err := remove()
+	if err != nil {
+		return n, err
+	}
+
+	for _, scheme := range schemes {
+		if err = runHook(scheme, afterDelete); err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// Restore clears deletion time of soft deleted records that match with
+// condition query and returns number of affected rows. Query should be
+// built by a model scoped with OnlyTrashed or WithTrashed, as deleted
+// records are excluded from queries otherwise.
+func (b *Builder) Restore() (n int, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	_, column := getDeletedAtField(b.model.scheme)
+	if column == "" {
+		return 0, fmt.Errorf("%w: %T is not soft deleted", base.ErrUnsupportedType, b.model.scheme)
+	}
+
+	return b.builder.Update(deletedAtData(column, nil))
 }
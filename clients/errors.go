@@ -0,0 +1,103 @@
+package clients
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Kamva/octopus/base"
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/globalsign/mgo"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Error codes of PostgreSQL for unique violation and the class of
+// integrity constraint violations.
+const (
+	pqUniqueViolation        = "23505"
+	pqIntegrityConstraintErr = "23"
+)
+
+// TranslateError maps errors of database drivers to octopus errors, so they
+// could be checked with errors.Is regardless of the database driver. Driver
+// errors are found in the chain of wrapped errors, and errors which are not
+// mapped to an octopus error are returned as is.
+func TranslateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, mgo.ErrNotFound):
+		return &base.Error{Kind: base.ErrNotFound, Cause: err}
+	}
+
+	if kind := errorKind(err); kind != nil {
+		return &base.Error{Kind: kind, Cause: err}
+	}
+
+	return err
+}
+
+// errorKind returns the octopus error that driver error in the chain of
+// `err` is mapped to, or nil if it is not mapped to any of them.
+func errorKind(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if pqErr.Code == pqUniqueViolation {
+			return base.ErrDuplicateKey
+		}
+
+		if pqErr.Code.Class() == pqIntegrityConstraintErr {
+			return base.ErrConstraintViolation
+		}
+	}
+
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		switch mssqlErr.Number {
+		case 2601, 2627:
+			return base.ErrDuplicateKey
+		case 515, 547:
+			return base.ErrConstraintViolation
+		}
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1062, 1586:
+			return base.ErrDuplicateKey
+		case 1048, 1216, 1217, 1451, 1452, 3819:
+			return base.ErrConstraintViolation
+		}
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code == sqlite3.ErrConstraint {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			return base.ErrDuplicateKey
+		}
+
+		return base.ErrConstraintViolation
+	}
+
+	// mgo.IsDup checks the concrete types of MongoDB errors
+	var lastErr *mgo.LastError
+	var queryErr *mgo.QueryError
+	var bulkErr *mgo.BulkError
+	switch {
+	case errors.As(err, &lastErr) && mgo.IsDup(lastErr),
+		errors.As(err, &queryErr) && mgo.IsDup(queryErr),
+		errors.As(err, &bulkErr) && mgo.IsDup(bulkErr):
+		return base.ErrDuplicateKey
+	}
+
+	// Errors of the official MongoDB driver are returned by transactions
+	if mongo.IsDuplicateKeyError(err) {
+		return base.ErrDuplicateKey
+	}
+
+	return nil
+}
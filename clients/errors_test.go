@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/globalsign/mgo"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestTranslateError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"noRows", sql.ErrNoRows, base.ErrNotFound},
+		{"mongoNotFound", mgo.ErrNotFound, base.ErrNotFound},
+		{"postgresUnique", &pq.Error{Code: "23505"}, base.ErrDuplicateKey},
+		{"postgresForeignKey", &pq.Error{Code: "23503"}, base.ErrConstraintViolation},
+		{"sqlServerUnique", mssql.Error{Number: 2627}, base.ErrDuplicateKey},
+		{"sqlServerUniqueIndex", mssql.Error{Number: 2601}, base.ErrDuplicateKey},
+		{"sqlServerForeignKey", mssql.Error{Number: 547}, base.ErrConstraintViolation},
+		{"mysqlUnique", &mysql.MySQLError{Number: 1062}, base.ErrDuplicateKey},
+		{"mysqlNotNull", &mysql.MySQLError{Number: 1048}, base.ErrConstraintViolation},
+		{
+			"sqliteUnique",
+			sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique},
+			base.ErrDuplicateKey,
+		},
+		{
+			"sqliteCheck",
+			sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintCheck},
+			base.ErrConstraintViolation,
+		},
+		{"mongoDuplicate", &mgo.LastError{Code: 11000}, base.ErrDuplicateKey},
+		{"mongoQueryDuplicate", &mgo.QueryError{Code: 11000}, base.ErrDuplicateKey},
+		{
+			"mongoTxDuplicate",
+			&mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}},
+			base.ErrDuplicateKey,
+		},
+		{"wrappedNoRows", fmt.Errorf("find user: %w", sql.ErrNoRows), base.ErrNotFound},
+		{"wrappedPostgres", fmt.Errorf("insert user: %w", &pq.Error{Code: "23505"}), base.ErrDuplicateKey},
+		{"wrappedSQLServer", fmt.Errorf("insert user: %w", mssql.Error{Number: 547}), base.ErrConstraintViolation},
+		{"wrappedMySQL", fmt.Errorf("insert user: %w", &mysql.MySQLError{Number: 1062}), base.ErrDuplicateKey},
+		{
+			"wrappedSQLite",
+			fmt.Errorf("insert user: %w", sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique}),
+			base.ErrDuplicateKey,
+		},
+		{"wrappedMongo", fmt.Errorf("insert user: %w", &mgo.LastError{Code: 11000}), base.ErrDuplicateKey},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := TranslateError(c.err)
+
+			assert.True(t, errors.Is(err, c.kind))
+			assert.True(t, errors.Is(err, c.err))
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, TranslateError(nil))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		assert.Equal(t, errTest, TranslateError(errTest))
+		assert.Equal(t, &pq.Error{Code: "42P01"}, TranslateError(&pq.Error{Code: "42P01"}))
+	})
+}
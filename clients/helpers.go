@@ -1,15 +1,26 @@
 package clients
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/term"
+	"github.com/Kamva/shark"
 )
 
 // fetchSingleRecord Fetch a single result from rows and set into record data
 func fetchSingleRecord(rows base.SQLRows, data *base.RecordData) error {
+	defer rows.Close()
+
 	if rows.Next() {
 		// Get list of result columns
 		cols, _ := rows.Columns()
@@ -34,10 +45,25 @@ func fetchSingleRecord(rows base.SQLRows, data *base.RecordData) error {
 
 	data.Zero()
 
-	return errors.New("no result found")
+	// Errors of statement (e.g. constraint violations of an insert with
+	// RETURNING clause) could be reported on fetching the first row.
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return base.ErrNotFound
+}
+
+// omitColumns removes `columns` from fetched record `data`
+func omitColumns(data *base.RecordData, columns []string) {
+	for _, column := range columns {
+		data.Unset(column)
+	}
 }
 
 func fetchResults(rows base.SQLRows) (base.RecordDataSet, error) {
+	defer rows.Close()
+
 	// Get list of result columns
 	cols, _ := rows.Columns()
 
@@ -67,20 +93,305 @@ func fetchResults(rows base.SQLRows) (base.RecordDataSet, error) {
 		data.Zero()
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return resultSet, nil
 }
 
-func prepareUpdate(data base.RecordData, enquoter base.Enquoter) string {
+// fetchCount fetches the `count` column of a single result row as a number.
+// Drivers return integers as different types, or even as text.
+func fetchCount(rows base.SQLRows) (int, error) {
+	data := base.ZeroRecordData()
+	if err := fetchSingleRecord(rows, data); err != nil {
+		return 0, err
+	}
+
+	switch count := data.Get("count").(type) {
+	case []byte:
+		return strconv.Atoi(string(count))
+	case string:
+		return strconv.Atoi(count)
+	default:
+		if n, ok := toInt64(count); ok {
+			return int(n), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unexpected count value %v", data.Get("count"))
+}
+
+// pruneBytes converts byte slice values of a record to string. Some
+// drivers return text, json and array columns as raw bytes.
+func pruneBytes(recordMap *base.RecordMap) {
+	for key, value := range *recordMap {
+		if v, ok := value.([]uint8); ok {
+			(*recordMap)[key] = string(v)
+		}
+	}
+}
+
+func prepareUpdate(data base.RecordData, args *sqlArgs) string {
 	updateParts := make([]string, 0, data.Length())
 	for _, column := range data.GetColumns() {
-		updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, enquoter(data.Get(column))))
+		updateParts = append(updateParts, fmt.Sprintf("%s = %s", column, args.bind(data.Get(column))))
 	}
 
 	return strings.Join(updateParts, ", ")
 }
 
-// queryDB executes given sqlQuery string and returns result rows and error
-// This is separated as a variable to mocked easily
-var queryDB = func(db base.SQLDatabase, query string) (base.SQLRows, error) {
-	return db.Query(query)
+// keyCondition returns condition of query matching the record that its
+// `key` primary key match with `id`, and binds key values to `args`.
+func keyCondition(key base.Key, id interface{}, args *sqlArgs) (string, error) {
+	values, err := key.Values(id)
+	if err != nil {
+		return "", err
+	}
+
+	conditions := make([]string, 0, len(key))
+	for i, column := range key {
+		conditions = append(conditions, fmt.Sprintf("%s = %s", column, args.bind(values[i])))
+	}
+
+	return strings.Join(conditions, " AND "), nil
+}
+
+// encodeValue encodes `value` by the codec registered for its type, and
+// each element of value lists of conditions (e.g. values of term.In).
+func encodeValue(value interface{}) interface{} {
+	if values, ok := value.([]interface{}); ok {
+		encoded := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			encoded = append(encoded, encodeValue(v))
+		}
+
+		return encoded
+	}
+
+	encoded, err := base.EncodeValue(value)
+	shark.PanicIfError(err)
+
+	return encoded
+}
+
+// isDriverValue checks whether `i` is passed to database driver as is,
+// which are time values and values implementing driver.Valuer.
+func isDriverValue(i interface{}) bool {
+	switch i.(type) {
+	case time.Time, driver.Valuer:
+		return true
+	}
+
+	return false
+}
+
+// sqlArgs collects the values of a query as driver arguments, and
+// generates bind parameter placeholders for them in order.
+type sqlArgs struct {
+	values      []interface{}
+	placeholder base.Placeholder
+	converter   base.Converter
+}
+
+func newSQLArgs(placeholder base.Placeholder, converter base.Converter) *sqlArgs {
+	return &sqlArgs{
+		values:      make([]interface{}, 0),
+		placeholder: placeholder,
+		converter:   converter,
+	}
+}
+
+// bind appends the converted value to the argument list
+// and returns the placeholder that refers to it.
+func (a *sqlArgs) bind(value interface{}) string {
+	a.values = append(a.values, a.converter(value))
+
+	return a.placeholder(len(a.values))
+}
+
+// bindAll binds all given values and returns their placeholders
+func (a *sqlArgs) bindAll(values []interface{}) []string {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, a.bind(value))
+	}
+
+	return placeholders
+}
+
+// queryDB executes given sqlQuery string with its arguments and returns
+// result rows and error. This is separated as a variable to mocked easily
+var queryDB = func(db base.SQLExecutor, query string, args ...interface{}) (base.SQLRows, error) {
+	return db.Query(query, args...)
+}
+
+// beginTx starts a transaction on database session with `ctx`. This is
+// separated as a variable to mocked easily
+var beginTx = func(db base.SQLDatabase, ctx context.Context) (base.SQLTx, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// contextExecutor is an executor which runs statements with a context,
+// so cancellation and deadline of the context stops running statements.
+type contextExecutor struct {
+	base.SQLExecutor
+	ctx context.Context
+}
+
+// Exec executes a statement with executor context
+func (e contextExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return e.ExecContext(e.ctx, query, args...)
+}
+
+// Query runs a query with executor context
+func (e contextExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return e.QueryContext(e.ctx, query, args...)
+}
+
+// withContext binds `executor` to `ctx`, or the background context if
+// `ctx` is nil.
+func withContext(executor base.SQLExecutor, ctx context.Context) base.SQLExecutor {
+	return contextExecutor{SQLExecutor: executor, ctx: orBackground(ctx)}
+}
+
+// orBackground returns `ctx`, or the background context if it is nil
+func orBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return ctx
+}
+
+// configureSQLPool applies connection pool settings on database session
+func configureSQLPool(session base.SQLDatabase, config base.PoolConfig) {
+	if config.MaxOpenConns > 0 {
+		session.SetMaxOpenConns(config.MaxOpenConns)
+	}
+
+	if config.MaxIdleConns > 0 {
+		session.SetMaxIdleConns(config.MaxIdleConns)
+	}
+
+	if config.ConnMaxLifetime > 0 {
+		session.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+}
+
+// errEmptyUpdate is returned when update is called with no change data
+var errEmptyUpdate = errors.New("change data could not be empty")
+
+// errNoTransaction is returned on committing or rolling back a client
+// which is not bound to a transaction
+var errNoTransaction = errors.New("client is not bound to a transaction")
+
+// errNestedTransaction is returned on beginning a transaction on a client
+// which is already bound to a transaction
+var errNestedTransaction = errors.New("nested transactions are not supported")
+
+// toInt64 converts signed integers, and unsigned integers that fit in
+// int64, to int64. It returns false if `i` is not such an integer.
+func toInt64(i interface{}) (int64, bool) {
+	v := reflect.ValueOf(i)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := v.Uint(); u <= 1<<63-1 {
+			return int64(u), true
+		}
+	}
+
+	return 0, false
+}
+
+// toUint64 converts unsigned integers, and non-negative signed integers,
+// to uint64. It returns false if `i` is not such an integer.
+func toUint64(i interface{}) (uint64, bool) {
+	v := reflect.ValueOf(i)
+
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n := v.Int(); n >= 0 {
+			return uint64(n), true
+		}
+	}
+
+	return 0, false
+}
+
+// toFloat64 converts any number to float64. It returns
+// false if `i` is not a number.
+func toFloat64(i interface{}) (float64, bool) {
+	v := reflect.ValueOf(i)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+// patternRegex converts a pattern matching condition to a regular expression
+// and its options, for clients that match patterns by regular expressions.
+// Options is "i" if the condition should be matched case insensitively.
+func patternRegex(condition base.Condition) (pattern string, options string) {
+	switch c := condition.(type) {
+	case term.Like:
+		return likeRegex(c.Pattern), ""
+	case term.ILike:
+		return likeRegex(c.Pattern), "i"
+	case term.Regex:
+		return c.Pattern, ""
+	case term.StartsWith:
+		return "^" + regexp.QuoteMeta(c.Value), caseOption(c.IgnoreCase)
+	case term.EndsWith:
+		return regexp.QuoteMeta(c.Value) + "$", caseOption(c.IgnoreCase)
+	case term.Contains:
+		return regexp.QuoteMeta(c.Value), caseOption(c.IgnoreCase)
+	}
+
+	panic(fmt.Errorf("%w: condition with type of %T is not a pattern condition", base.ErrUnsupportedType, condition))
+}
+
+// likeRegex converts a LIKE pattern to an anchored regular expression
+func likeRegex(pattern string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			builder.WriteString(".*")
+		case '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	builder.WriteString("$")
+
+	return builder.String()
+}
+
+// caseOption returns the regular expression option of case insensitivity
+func caseOption(ignoreCase bool) string {
+	if ignoreCase {
+		return "i"
+	}
+
+	return ""
 }
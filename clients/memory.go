@@ -0,0 +1,404 @@
+package clients
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Kamva/octopus/base"
+)
+
+// memoryDatabases holds in-memory databases by their names, so all Memory
+// clients opened with the same database name share the same records.
+var memoryDatabases = struct {
+	sync.Mutex
+	databases map[string]*memoryDatabase
+}{databases: make(map[string]*memoryDatabase)}
+
+// memoryDatabase is a set of in-memory tables
+type memoryDatabase struct {
+	sync.Mutex
+	tables map[string]*memoryTable
+}
+
+// memoryTable holds records of a table in order of insertion
+type memoryTable struct {
+	records []base.RecordData
+	indices []base.Index
+	lastID  int64
+
+	// key is the primary key of table, which is set on insertion
+	key base.Key
+}
+
+// Memory is an in-memory client that stores records in maps and evaluates
+// queries in Go. It is meant to be used as a fake database in unit tests.
+type Memory struct {
+	database *memoryDatabase
+
+	// origin is the database that changes are committed to, if the
+	// client is bound to a transaction working on a snapshot of it.
+	origin *memoryDatabase
+}
+
+// CreateTable creates `tableName` table with field and structure
+// defined in `structure` parameter for each table fields
+func (c *Memory) CreateTable(tableName string, info base.TableInfo) error {
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	c.database.table(tableName)
+
+	return nil
+}
+
+// EnsureIndex ensures that `index` is exists on `tableName` table,
+// if not, it tries to create index with specified condition in
+// `index` on `tableName`. Only unique indices have effect on
+// in-memory tables, which are checked on insert and update.
+func (c *Memory) EnsureIndex(tableName string, index base.Index) error {
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	table := c.database.table(tableName)
+	for _, existing := range table.indices {
+		if reflect.DeepEqual(existing, index) {
+			return nil
+		}
+	}
+
+	if index.Unique {
+		for i := range table.records {
+			if err := table.checkIndex(index, table.records[i], i); err != nil {
+				return err
+			}
+		}
+	}
+
+	table.indices = append(table.indices, index)
+
+	return nil
+}
+
+// Insert tries to insert `data` into `tableName` and returns error if
+// anything went wrong. `data` should pass by reference to have exact
+// data on `tableName`, otherwise updated record data isn't accessible.
+func (c *Memory) Insert(tableName string, key base.Key, data *base.RecordData) error {
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	table := c.database.table(tableName)
+	record := copyRecordData(*data)
+
+	// Only single column keys are generated, and composite keys should
+	// be set in `data`.
+	table.key = key
+	if !key.IsComposite() {
+		keyName := key[0]
+		if id := record.Get(keyName); id == nil || reflect.ValueOf(id).IsZero() {
+			table.lastID++
+			record.Set(keyName, table.lastID)
+		} else if i, ok := toInt64(id); ok && i > table.lastID {
+			table.lastID = i
+		}
+	}
+
+	if err := table.checkUnique(record, -1); err != nil {
+		return err
+	}
+
+	table.records = append(table.records, record)
+	for _, column := range key {
+		data.Set(column, record.Get(column))
+	}
+
+	return nil
+}
+
+// FindByID searches through `tableName` records to find a row that its
+// `key` primary key match with `id` and returns it alongside any
+// possible error.
+func (c *Memory) FindByID(tableName string, key base.Key, id interface{}) (base.RecordData, error) {
+	values, err := key.Values(id)
+	if err != nil {
+		return *base.ZeroRecordData(), err
+	}
+
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	table := c.database.table(tableName)
+	if i := table.indexOfKey(key, values); i >= 0 {
+		return copyRecordData(table.records[i]), nil
+	}
+
+	return *base.ZeroRecordData(), base.ErrNotFound
+}
+
+// UpdateByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and updates it with data. It will return error if
+// anything went wrong.
+func (c *Memory) UpdateByID(tableName string, key base.Key, id interface{}, data base.RecordData) error {
+	values, err := key.Values(id)
+	if err != nil {
+		return err
+	}
+
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	table := c.database.table(tableName)
+	if i := table.indexOfKey(key, values); i >= 0 {
+		return table.update(i, data)
+	}
+
+	return nil
+}
+
+// DeleteByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and remove it entirely. It will return error if
+// anything went wrong.
+func (c *Memory) DeleteByID(tableName string, key base.Key, id interface{}) error {
+	values, err := key.Values(id)
+	if err != nil {
+		return err
+	}
+
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	table := c.database.table(tableName)
+	if i := table.indexOfKey(key, values); i >= 0 {
+		table.records = append(table.records[:i], table.records[i+1:]...)
+	}
+
+	return nil
+}
+
+// Query generates and returns memoryQuery object for further operations
+func (c *Memory) Query(tableName string, conditions ...base.Condition) base.QueryBuilder {
+	return &memoryQuery{database: c.database, table: tableName, conditions: conditions}
+}
+
+// Begin starts a new transaction and returns a client bound to it. The
+// transaction works on a snapshot of the database which replaces database
+// tables on commit, so the last committed one wins on concurrent changes.
+func (c *Memory) Begin() (base.TxClient, error) {
+	if c.origin != nil {
+		return nil, errNestedTransaction
+	}
+
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	return &Memory{database: c.database.snapshot(), origin: c.database}, nil
+}
+
+// Commit commits the transaction that client is bound to
+func (c *Memory) Commit() error {
+	if c.origin == nil {
+		return errNoTransaction
+	}
+
+	c.origin.Lock()
+	defer c.origin.Unlock()
+
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	c.origin.tables = c.database.tables
+	c.database.tables = make(map[string]*memoryTable)
+	c.origin = nil
+
+	return nil
+}
+
+// Rollback aborts the transaction that client is bound to
+func (c *Memory) Rollback() error {
+	if c.origin == nil {
+		return errNoTransaction
+	}
+
+	c.database.Lock()
+	defer c.database.Unlock()
+
+	c.database.tables = make(map[string]*memoryTable)
+	c.origin = nil
+
+	return nil
+}
+
+// Close detach client from in-memory database. The database itself
+// is kept alive until it is dropped by DropMemoryDatabase.
+func (c *Memory) Close() {
+	c.database = nil
+}
+
+// table returns table with `name`, and creates it if it is not exists.
+// The caller should hold the database lock.
+func (d *memoryDatabase) table(name string) *memoryTable {
+	table, ok := d.tables[name]
+	if !ok {
+		table = &memoryTable{records: make([]base.RecordData, 0)}
+		d.tables[name] = table
+	}
+
+	return table
+}
+
+// snapshot returns a copy of database which does not share its tables
+// and records with it. The caller should hold the database lock.
+func (d *memoryDatabase) snapshot() *memoryDatabase {
+	tables := make(map[string]*memoryTable, len(d.tables))
+	for name, table := range d.tables {
+		records := make([]base.RecordData, 0, len(table.records))
+		for _, record := range table.records {
+			records = append(records, copyRecordData(record))
+		}
+
+		tables[name] = &memoryTable{
+			records: records,
+			indices: append([]base.Index(nil), table.indices...),
+			lastID:  table.lastID,
+			key:     table.key,
+		}
+	}
+
+	return &memoryDatabase{tables: tables}
+}
+
+// primaryKey returns the primary key of table, which is `id` by default
+func (t *memoryTable) primaryKey() base.Key {
+	if len(t.key) == 0 {
+		return base.Key{"id"}
+	}
+
+	return t.key
+}
+
+// indexOfKey returns position of the record that its `key` primary key
+// columns match with `values` or -1 if there is no such record.
+func (t *memoryTable) indexOfKey(key base.Key, values []interface{}) int {
+	for i, record := range t.records {
+		if matchKey(record, key, values) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// matchKey checks whether `key` columns of `record` match with `values`
+func matchKey(record base.RecordData, key base.Key, values []interface{}) bool {
+	for i, column := range key {
+		if !equalValues(record.Get(column), values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// update sets `data` columns on the record at position `i`
+func (t *memoryTable) update(i int, data base.RecordData) error {
+	record := copyRecordData(t.records[i])
+	for _, column := range data.GetColumns() {
+		record.Set(column, data.Get(column))
+	}
+
+	if err := t.checkUnique(record, i); err != nil {
+		return err
+	}
+
+	t.records[i] = record
+
+	return nil
+}
+
+// checkUnique checks that `record` does not violate the primary key or any
+// unique index of table. `position` is the record position in table which
+// is skipped in the check, or -1 for a new record.
+func (t *memoryTable) checkUnique(record base.RecordData, position int) error {
+	primary := base.Index{Columns: t.primaryKey(), Unique: true}
+	if err := t.checkIndex(primary, record, position); err != nil {
+		return err
+	}
+
+	for _, index := range t.indices {
+		if !index.Unique {
+			continue
+		}
+
+		if err := t.checkIndex(index, record, position); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkIndex checks that no record other than the one in `position`
+// has the same values as `record` on unique `index` columns.
+func (t *memoryTable) checkIndex(index base.Index, record base.RecordData, position int) error {
+	for i, existing := range t.records {
+		if i == position {
+			continue
+		}
+
+		// Like SQL databases, null values are not considered
+		// equal to each other on unique indices.
+		duplicate := true
+		for _, column := range index.Columns {
+			value := record.Get(column)
+			if value == nil || !equalValues(existing.Get(column), value) {
+				duplicate = false
+				break
+			}
+		}
+
+		if duplicate {
+			return fmt.Errorf(
+				"%w: duplicate value for unique key (%s)",
+				base.ErrDuplicateKey, strings.Join(index.Columns, ", "),
+			)
+		}
+	}
+
+	return nil
+}
+
+// copyRecordData returns a copy of `data` which does not share its map,
+// so modifying a stored record does not affect the returned one.
+func copyRecordData(data base.RecordData) base.RecordData {
+	record := base.ZeroRecordData()
+	for _, column := range data.GetColumns() {
+		record.Set(column, data.Get(column))
+	}
+
+	return *record
+}
+
+// NewMemory instantiate and return a new Memory client object connected to
+// in-memory `database`. The database is created if it is not exists.
+func NewMemory(database string) base.Client {
+	memoryDatabases.Lock()
+	defer memoryDatabases.Unlock()
+
+	db, ok := memoryDatabases.databases[database]
+	if !ok {
+		db = &memoryDatabase{tables: make(map[string]*memoryTable)}
+		memoryDatabases.databases[database] = db
+	}
+
+	return &Memory{database: db}
+}
+
+// DropMemoryDatabase removes in-memory `database` and all its records.
+// Clients opened after dropping the database get an empty database.
+func DropMemoryDatabase(database string) {
+	memoryDatabases.Lock()
+	defer memoryDatabases.Unlock()
+
+	delete(memoryDatabases.databases, database)
+}
@@ -0,0 +1,669 @@
+package clients
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/term"
+)
+
+// memoryQuery is a struct containing information about query on
+// an in-memory table, which is evaluated on records in Go.
+type memoryQuery struct {
+	database   *memoryDatabase
+	table      string
+	conditions []base.Condition
+	sorts      []base.Sort
+	limit      int
+	offset     int
+	columns    []string
+	omitted    []string
+	groups     []string
+	having     []base.Condition
+}
+
+// OrderBy set the order of returning result in following command
+func (q *memoryQuery) OrderBy(sorts ...base.Sort) base.QueryBuilder {
+	q.sorts = sorts
+
+	return q
+}
+
+// Limit set the limit that determines how many results should be
+// returned in the following fetch command.
+func (q *memoryQuery) Limit(n int) base.QueryBuilder {
+	q.limit = n
+
+	return q
+}
+
+// Skip set the starting offset of the following fetch command
+func (q *memoryQuery) Skip(n int) base.QueryBuilder {
+	q.offset = n
+
+	return q
+}
+
+// Select limits columns fetched by the following fetch command to `fields`
+func (q *memoryQuery) Select(fields ...string) base.QueryBuilder {
+	q.columns = fields
+
+	return q
+}
+
+// Omit excludes `fields` from columns fetched by the following fetch command
+func (q *memoryQuery) Omit(fields ...string) base.QueryBuilder {
+	q.omitted = fields
+
+	return q
+}
+
+// GroupBy groups records by values of `fields` in the following aggregate
+// command.
+func (q *memoryQuery) GroupBy(fields ...string) base.QueryBuilder {
+	q.groups = fields
+
+	return q
+}
+
+// Having filters groups of the following aggregate command by `conditions`,
+// which fields could be aliases of aggregations.
+func (q *memoryQuery) Having(conditions ...base.Condition) base.QueryBuilder {
+	q.having = conditions
+
+	return q
+}
+
+// Count execute a count command that will return the number records in
+// specified destination table. If the query conditions was empty, it
+// returns number of all records un destination table.
+func (q *memoryQuery) Count() (int, error) {
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	return len(q.match()), nil
+}
+
+// CountDistinct execute a count command that will return the number of
+// distinct non-null values of `field` in records matching the query
+// conditions.
+func (q *memoryQuery) CountDistinct(field string) (int, error) {
+	values, _ := q.Distinct(field)
+
+	n := 0
+	for _, value := range values {
+		if value != nil {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// Distinct returns distinct values of `field` in records matching the query
+// conditions, in order of query sorts.
+func (q *memoryQuery) Distinct(field string) ([]interface{}, error) {
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	table := q.database.table(q.table)
+	positions := q.match()
+	sort.SliceStable(positions, func(i, j int) bool {
+		return q.less(table.records[positions[i]], table.records[positions[j]])
+	})
+
+	values := make([]interface{}, 0)
+	for _, i := range positions {
+		value := table.records[i].Get(field)
+		if !containsValue(values, value) {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// Aggregate returns a row of grouping fields and `aggregations` for each
+// group of records matching the query conditions. All records matching the
+// conditions are aggregated in one row if no grouping field is set.
+func (q *memoryQuery) Aggregate(aggregations ...base.Aggregation) (base.RecordDataSet, error) {
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	table := q.database.table(q.table)
+
+	// Records are grouped by values of grouping fields in order of
+	// their first appearance.
+	keys := make([][]interface{}, 0)
+	groups := make([][]base.RecordData, 0)
+	for _, i := range q.match() {
+		record := table.records[i]
+		key := make([]interface{}, 0, len(q.groups))
+		for _, field := range q.groups {
+			key = append(key, record.Get(field))
+		}
+
+		found := false
+		for j := range keys {
+			if equalValueLists(keys[j], key) {
+				groups[j] = append(groups[j], record)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			keys = append(keys, key)
+			groups = append(groups, []base.RecordData{record})
+		}
+	}
+
+	// Records without grouping fields are aggregated in one row, even if
+	// there is no record.
+	if len(q.groups) == 0 && len(groups) == 0 {
+		keys = append(keys, nil)
+		groups = append(groups, nil)
+	}
+
+	resultSet := make(base.RecordDataSet, 0, len(groups))
+	for j, records := range groups {
+		data := base.ZeroRecordData()
+		for k, field := range q.groups {
+			data.Set(field, keys[j][k])
+		}
+		for _, aggregation := range aggregations {
+			data.Set(aggregation.Alias, aggregate(aggregation, records))
+		}
+
+		if matchConditions(*data, q.having) {
+			resultSet = append(resultSet, *data)
+		}
+	}
+
+	sort.SliceStable(resultSet, func(i, j int) bool {
+		return q.less(resultSet[i], resultSet[j])
+	})
+	from, to := q.bounds(len(resultSet))
+
+	return resultSet[from:to], nil
+}
+
+// Exists checks whether any record matches with the query conditions
+func (q *memoryQuery) Exists() (bool, error) {
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	return len(q.match()) > 0, nil
+}
+
+// First fetch data of the first record that match with query conditions.
+func (q *memoryQuery) First() (base.RecordData, error) {
+	q.limit = 1
+	results, err := q.All()
+	if err != nil || len(results) == 0 {
+		return *base.ZeroRecordData(), base.ErrNotFound
+	}
+
+	return results[0], nil
+}
+
+// All returns results that match with query conditions in RecordDataSet
+// format. If the query conditions was empty it will return all records
+// in specified destination table or error if anything went wrong.
+func (q *memoryQuery) All() (base.RecordDataSet, error) {
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	table := q.database.table(q.table)
+	positions := q.match()
+
+	sort.SliceStable(positions, func(i, j int) bool {
+		return q.less(table.records[positions[i]], table.records[positions[j]])
+	})
+
+	from, to := q.bounds(len(positions))
+	positions = positions[from:to]
+
+	resultSet := make(base.RecordDataSet, 0, len(positions))
+	for _, i := range positions {
+		resultSet = append(resultSet, q.project(copyRecordData(table.records[i])))
+	}
+
+	return resultSet, nil
+}
+
+// Update updates records that math with query conditions with `data` and
+// returns number of affected rows and error if anything went wring. If
+// the query condition was empty it'll update all records in destination
+// table.
+func (q *memoryQuery) Update(data base.RecordData) (int, error) {
+	if data.Length() == 0 {
+		return 0, errEmptyUpdate
+	}
+
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	table := q.database.table(q.table)
+	positions := q.match()
+	for n, i := range positions {
+		if err := table.update(i, data); err != nil {
+			return n, err
+		}
+	}
+
+	return len(positions), nil
+}
+
+// Delete removes every records in destination table that match with condition
+// query and returns number of affected rows and error if anything went wrong.
+// It will removes all records inside destination table if no condition query
+// was set.
+func (q *memoryQuery) Delete() (int, error) {
+	q.database.Lock()
+	defer q.database.Unlock()
+
+	table := q.database.table(q.table)
+	positions := q.match()
+
+	deleted := make(map[int]bool, len(positions))
+	for _, i := range positions {
+		deleted[i] = true
+	}
+
+	records := make([]base.RecordData, 0, len(table.records)-len(positions))
+	for i, record := range table.records {
+		if !deleted[i] {
+			records = append(records, record)
+		}
+	}
+	table.records = records
+
+	return len(positions), nil
+}
+
+// bounds returns range of `n` sorted results which should be returned
+// regarding query offset and limit.
+func (q *memoryQuery) bounds(n int) (from int, to int) {
+	from, to = q.offset, n
+	if from > n {
+		from = n
+	}
+
+	if q.limit > 0 && from+q.limit < to {
+		to = from + q.limit
+	}
+
+	return from, to
+}
+
+// project limits columns of fetched record `data` to the selected columns,
+// and removes the omitted columns from it.
+func (q *memoryQuery) project(data base.RecordData) base.RecordData {
+	if len(q.columns) > 0 {
+		selected := base.ZeroRecordData()
+		for _, column := range q.columns {
+			if value, ok := (*data.GetMap())[column]; ok {
+				selected.Set(column, value)
+			}
+		}
+		data = *selected
+	}
+
+	omitColumns(&data, q.omitted)
+
+	return data
+}
+
+// match returns positions of records in table that match with all query
+// conditions. The caller should hold the database lock.
+func (q *memoryQuery) match() []int {
+	table := q.database.table(q.table)
+
+	positions := make([]int, 0)
+	for i, record := range table.records {
+		if matchConditions(record, q.conditions) {
+			positions = append(positions, i)
+		}
+	}
+
+	return positions
+}
+
+// less reports whether record `a` should be ordered before record `b`
+// according to query sorts. Null values are ordered before others.
+func (q *memoryQuery) less(a base.RecordData, b base.RecordData) bool {
+	for _, s := range q.sorts {
+		x, y := a.Get(s.Column), b.Get(s.Column)
+
+		var cmp int
+		switch {
+		case x == nil && y == nil:
+			continue
+		case x == nil:
+			cmp = -1
+		case y == nil:
+			cmp = 1
+		default:
+			var ok bool
+			if cmp, ok = compareValues(x, y); !ok {
+				continue
+			}
+		}
+
+		if cmp == 0 {
+			continue
+		}
+
+		if s.Descending {
+			return cmp > 0
+		}
+
+		return cmp < 0
+	}
+
+	return false
+}
+
+// matchConditions checks whether `record` matches with all `conditions`
+func matchConditions(record base.RecordData, conditions []base.Condition) bool {
+	for _, condition := range conditions {
+		if !matchCondition(record, condition) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchCondition checks whether `record` matches with `condition`. It
+// panics if the condition type is not supported by in-memory queries.
+func matchCondition(record base.RecordData, condition base.Condition) bool {
+	value := record.Get(condition.GetField())
+
+	switch condition.(type) {
+	case term.Equal:
+		return equalValues(value, condition.GetValue())
+	case term.NotEqual:
+		// Null values are not equal nor not equal to any value in SQL
+		return value != nil && !equalValues(value, condition.GetValue())
+	case term.GreaterThan:
+		cmp, ok := compareValues(value, condition.GetValue())
+		return ok && cmp > 0
+	case term.GreaterThanEqual:
+		cmp, ok := compareValues(value, condition.GetValue())
+		return ok && cmp >= 0
+	case term.LessThan:
+		cmp, ok := compareValues(value, condition.GetValue())
+		return ok && cmp < 0
+	case term.LessThanEqual:
+		cmp, ok := compareValues(value, condition.GetValue())
+		return ok && cmp <= 0
+	case term.IsNull:
+		return value == nil
+	case term.NotNull:
+		return value != nil
+	case term.In:
+		return containsValue(condition.GetValue().([]interface{}), value)
+	case term.NotIn:
+		return value != nil && !containsValue(condition.GetValue().([]interface{}), value)
+	case term.Between:
+		values := condition.GetValue().([]interface{})
+		from, ok := compareValues(value, values[0])
+		if !ok || from < 0 {
+			return false
+		}
+		to, ok := compareValues(value, values[1])
+		return ok && to <= 0
+	case term.Exists:
+		return value != nil
+	case term.ArrayContains:
+		elements, ok := sliceValues(value)
+		if !ok {
+			return false
+		}
+		for _, v := range condition.GetValue().([]interface{}) {
+			if !containsValue(elements, v) {
+				return false
+			}
+		}
+
+		return true
+	case term.ArrayOverlaps:
+		elements, _ := sliceValues(value)
+		for _, v := range condition.GetValue().([]interface{}) {
+			if containsValue(elements, v) {
+				return true
+			}
+		}
+
+		return false
+	case term.ElemMatch:
+		elements, _ := sliceValues(value)
+		for _, element := range elements {
+			if record, ok := elementRecord(element); ok &&
+				matchConditions(record, condition.GetValue().([]base.Condition)) {
+				return true
+			}
+		}
+
+		return false
+	case term.Like, term.ILike, term.Regex, term.StartsWith, term.EndsWith, term.Contains:
+		s, ok := value.(string)
+		return ok && matchPattern(s, condition)
+	case term.And:
+		return matchConditions(record, condition.GetValue().([]base.Condition))
+	case term.Or:
+		for _, c := range condition.GetValue().([]base.Condition) {
+			if matchCondition(record, c) {
+				return true
+			}
+		}
+
+		return false
+	case term.Not:
+		return !matchCondition(record, condition.GetValue().(base.Condition))
+	}
+
+	panic(fmt.Errorf("%w: condition with type of %T", base.ErrUnsupportedType, condition))
+}
+
+// aggregate computes value of `aggregation` on `records`. Sums and averages
+// are float numbers, and they are nil like minimum and maximum values if
+// no record has a value for the field.
+func aggregate(aggregation base.Aggregation, records []base.RecordData) interface{} {
+	if aggregation.Func == base.CountFunc {
+		var n int64
+		for _, record := range records {
+			if aggregation.Field == "*" || record.Get(aggregation.Field) != nil {
+				n++
+			}
+		}
+
+		return n
+	}
+
+	var result interface{}
+	var sum float64
+	var n int
+	for _, record := range records {
+		value := record.Get(aggregation.Field)
+		if value == nil {
+			continue
+		}
+
+		switch aggregation.Func {
+		case base.SumFunc, base.AvgFunc:
+			f, ok := toFloat64(encodeValue(value))
+			if !ok {
+				panic(fmt.Errorf("%w: cannot aggregate %T values", base.ErrUnsupportedType, value))
+			}
+			sum += f
+		case base.MinFunc:
+			if cmp, ok := compareValues(value, result); result == nil || (ok && cmp < 0) {
+				result = value
+			}
+		case base.MaxFunc:
+			if cmp, ok := compareValues(value, result); result == nil || (ok && cmp > 0) {
+				result = value
+			}
+		default:
+			panic(fmt.Errorf("%w: aggregate function %s", base.ErrUnsupportedType, aggregation.Func))
+		}
+		n++
+	}
+
+	if n > 0 {
+		switch aggregation.Func {
+		case base.SumFunc:
+			result = sum
+		case base.AvgFunc:
+			result = sum / float64(n)
+		}
+	}
+
+	return result
+}
+
+// equalValueLists checks whether values of `a` and `b` are equal in order
+func equalValueLists(a []interface{}, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !equalValues(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsValue checks whether `values` contains `value`
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if equalValues(value, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sliceValues returns elements of `value` if it is a slice or an array
+func sliceValues(value interface{}) ([]interface{}, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	elements := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		elements = append(elements, v.Index(i).Interface())
+	}
+
+	return elements, true
+}
+
+// elementRecord converts a sub-document element of an array, which is a map
+// with string keys, to record data so conditions can be matched against it.
+func elementRecord(element interface{}) (base.RecordData, bool) {
+	v := reflect.ValueOf(element)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return *base.ZeroRecordData(), false
+	}
+
+	record := base.ZeroRecordData()
+	for _, key := range v.MapKeys() {
+		record.Set(key.String(), v.MapIndex(key).Interface())
+	}
+
+	return *record, true
+}
+
+// matchPattern checks whether `value` matches with pattern `condition`
+func matchPattern(value string, condition base.Condition) bool {
+	pattern, options := patternRegex(condition)
+	if options == "i" {
+		pattern = "(?i)" + pattern
+	}
+
+	return regexp.MustCompile(pattern).MatchString(value)
+}
+
+// equalValues checks whether `a` and `b` are equal regardless of their
+// numeric types, e.g. int(1) is equal to int64(1) and float64(1).
+func equalValues(a interface{}, b interface{}) bool {
+	a, b = encodeValue(a), encodeValue(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if cmp, ok := compareValues(a, b); ok {
+		return cmp == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compareValues compares `a` and `b` and returns -1, 0 or 1 if `a` is less
+// than, equal to or greater than `b`. The second return value is false if
+// the values are nil or not comparable with each other.
+func compareValues(a interface{}, b interface{}) (int, bool) {
+	a, b = encodeValue(a), encodeValue(b)
+	if a == nil || b == nil {
+		return 0, false
+	}
+
+	if x, ok := toInt64(a); ok {
+		if y, ok := toInt64(b); ok {
+			return compareOrdered(x < y, x > y), true
+		}
+	}
+
+	if x, ok := toUint64(a); ok {
+		if y, ok := toUint64(b); ok {
+			return compareOrdered(x < y, x > y), true
+		}
+	}
+
+	if x, ok := toFloat64(a); ok {
+		if y, ok := toFloat64(b); ok {
+			return compareOrdered(x < y, x > y), true
+		}
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareOrdered(!x && y, x && !y), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return compareOrdered(x.Before(y), x.After(y)), true
+		}
+	}
+
+	return 0, false
+}
+
+// compareOrdered converts result of less and greater comparisons to -1, 0 or 1
+func compareOrdered(less bool, greater bool) int {
+	if less {
+		return -1
+	} else if greater {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,420 @@
+package clients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+type unsupportedCondition struct{}
+
+func (unsupportedCondition) GetField() string { return "name" }
+
+func (unsupportedCondition) GetValue() interface{} { return nil }
+
+func queryNames(t *testing.T, q base.QueryBuilder) []string {
+	t.Helper()
+
+	results, err := q.All()
+	assert.Nil(t, err)
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Get("name").(string))
+	}
+
+	return names
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestMemoryQuery_All(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	t.Run("noCondition", func(t *testing.T) {
+		names := queryNames(t, client.Query("players"))
+
+		assert.Equal(t, []string{"Sergio Aguero", "Mohamed Salah", "Raheem Sterling", "Jamie Vardy"}, names)
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		testCases := []struct {
+			name      string
+			condition base.Condition
+			expected  []string
+		}{
+			{"equal", term.Equal{Field: "team", Value: "Liverpool"}, []string{"Mohamed Salah"}},
+			{"equalNumber", term.Equal{Field: "age", Value: int64(31)}, []string{"Sergio Aguero"}},
+			{"notEqual", term.NotEqual{Field: "team", Value: "Manchester City"}, []string{"Mohamed Salah", "Jamie Vardy"}},
+			{"notEqualNull", term.NotEqual{Field: "banned_date", Value: "2019-01-01"}, []string{"Raheem Sterling"}},
+			{"greaterThan", term.GreaterThan{Field: "age", Value: 31}, []string{"Jamie Vardy"}},
+			{"greaterThanEqual", term.GreaterThanEqual{Field: "rate", Value: 8.2}, []string{"Sergio Aguero", "Mohamed Salah"}},
+			{"lessThan", term.LessThan{Field: "age", Value: 27}, []string{"Raheem Sterling"}},
+			{"lessThanEqual", term.LessThanEqual{Field: "age", Value: uint(27)}, []string{"Mohamed Salah", "Raheem Sterling"}},
+			{"isNull", term.IsNull{Field: "banned_date"}, []string{"Sergio Aguero", "Mohamed Salah", "Jamie Vardy"}},
+			{"isNullMissingField", term.IsNull{Field: "unknown"}, []string{"Sergio Aguero", "Mohamed Salah", "Raheem Sterling", "Jamie Vardy"}},
+			{"notNull", term.NotNull{Field: "banned_date"}, []string{"Raheem Sterling"}},
+			{"in", term.In{Field: "team", Values: []interface{}{"Liverpool", "Leicester City"}}, []string{"Mohamed Salah", "Jamie Vardy"}},
+			{"like", term.Like{Field: "team", Pattern: "Man%"}, []string{"Sergio Aguero", "Raheem Sterling"}},
+			{"likeCaseSensitive", term.Like{Field: "team", Pattern: "man%"}, []string{}},
+			{"iLike", term.ILike{Field: "name", Pattern: "%S_RGIO%"}, []string{"Sergio Aguero"}},
+			{"regex", term.Regex{Field: "name", Pattern: "^[JM]"}, []string{"Mohamed Salah", "Jamie Vardy"}},
+			{"startsWith", term.StartsWith{Field: "team", Value: "Li"}, []string{"Mohamed Salah"}},
+			{"endsWith", term.EndsWith{Field: "team", Value: "CITY", IgnoreCase: true}, []string{"Sergio Aguero", "Raheem Sterling", "Jamie Vardy"}},
+			{"containsLiteral", term.Contains{Field: "name", Value: "."}, []string{}},
+			{"patternNonString", term.Contains{Field: "age", Value: "3"}, []string{}},
+			{"notIn", term.NotIn{Field: "team", Values: []interface{}{"Liverpool", "Leicester City"}}, []string{"Sergio Aguero", "Raheem Sterling"}},
+			{"notInNull", term.NotIn{Field: "banned_date", Values: []interface{}{"2019-01-01"}}, []string{"Raheem Sterling"}},
+			{"between", term.Between{Field: "age", From: 25, To: 27}, []string{"Mohamed Salah", "Raheem Sterling"}},
+			{"exists", term.Exists{Field: "banned_date"}, []string{"Raheem Sterling"}},
+			{"incomparable", term.GreaterThan{Field: "name", Value: 10}, []string{}},
+			{"or", term.Or{
+				term.Equal{Field: "team", Value: "Liverpool"},
+				term.GreaterThan{Field: "age", Value: 31},
+			}, []string{"Mohamed Salah", "Jamie Vardy"}},
+			{"emptyOr", term.Or{}, []string{}},
+			{"nested", term.Or{
+				term.And{
+					term.Equal{Field: "team", Value: "Manchester City"},
+					term.Not{Condition: term.IsNull{Field: "banned_date"}},
+				},
+				term.LessThan{Field: "rate", Value: 8},
+			}, []string{"Raheem Sterling", "Jamie Vardy"}},
+			{"not", term.Not{Condition: term.And{
+				term.Equal{Field: "team", Value: "Manchester City"},
+				term.LessThan{Field: "age", Value: 30},
+			}}, []string{"Sergio Aguero", "Mohamed Salah", "Jamie Vardy"}},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				names := queryNames(t, client.Query("players", testCase.condition))
+
+				assert.Equal(t, testCase.expected, names)
+			})
+		}
+	})
+
+	t.Run("multipleConditions", func(t *testing.T) {
+		names := queryNames(t, client.Query(
+			"players",
+			term.Equal{Field: "team", Value: "Manchester City"},
+			term.IsNull{Field: "banned_date"},
+		))
+
+		assert.Equal(t, []string{"Sergio Aguero"}, names)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		names := queryNames(t, client.Query("players").
+			OrderBy(
+				base.Sort{Column: "rate", Descending: true},
+				base.Sort{Column: "age"},
+			).
+			Skip(1).
+			Limit(2))
+
+		assert.Equal(t, []string{"Mohamed Salah", "Raheem Sterling"}, names)
+	})
+
+	t.Run("sortNullsFirst", func(t *testing.T) {
+		names := queryNames(t, client.Query("players").
+			OrderBy(base.Sort{Column: "banned_date"}, base.Sort{Column: "name"}))
+
+		assert.Equal(t, []string{"Jamie Vardy", "Mohamed Salah", "Sergio Aguero", "Raheem Sterling"}, names)
+	})
+
+	t.Run("skipOutOfRange", func(t *testing.T) {
+		names := queryNames(t, client.Query("players").Skip(10))
+
+		assert.Empty(t, names)
+	})
+
+	t.Run("unsupportedCondition", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = client.Query("players", unsupportedCondition{}).All()
+		})
+	})
+}
+
+func TestMemoryQuery_arrays(t *testing.T) {
+	client := initMemory(t)
+
+	players := []base.RecordMap{
+		{"name": "Sergio Aguero", "positions": []string{"ST", "CF"}, "trophies": []map[string]interface{}{
+			{"title": "Premier League", "count": 4},
+		}},
+		{"name": "Mohamed Salah", "positions": []interface{}{"RW", "ST"}, "trophies": []interface{}{
+			base.RecordMap{"title": "Champions League", "count": 1},
+			base.RecordMap{"title": "Premier League", "count": 1},
+		}},
+		{"name": "Jamie Vardy", "positions": nil, "trophies": nil},
+	}
+	for _, player := range players {
+		data := base.NewRecordData([]string{"name", "positions", "trophies"}, player)
+		assert.Nil(t, client.Insert("players", base.Key{"id"}, data))
+	}
+
+	testCases := []struct {
+		name      string
+		condition base.Condition
+		expected  []string
+	}{
+		{"contains", term.ArrayContains{Field: "positions", Values: []interface{}{"ST", "CF"}}, []string{"Sergio Aguero"}},
+		{"overlaps", term.ArrayOverlaps{Field: "positions", Values: []interface{}{"CF", "RW"}}, []string{"Sergio Aguero", "Mohamed Salah"}},
+		{"elemMatch", term.ElemMatch{Field: "trophies", Conditions: []base.Condition{
+			term.Equal{Field: "title", Value: "Premier League"},
+			term.GreaterThan{Field: "count", Value: 2},
+		}}, []string{"Sergio Aguero"}},
+		{"elemMatchAny", term.ElemMatch{Field: "trophies", Conditions: []base.Condition{
+			term.Equal{Field: "title", Value: "Champions League"},
+		}}, []string{"Mohamed Salah"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			names := queryNames(t, client.Query("players", testCase.condition))
+
+			assert.Equal(t, testCase.expected, names)
+		})
+	}
+}
+
+func TestMemoryQuery_First(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	t.Run("found", func(t *testing.T) {
+		data, err := client.Query("players", term.Equal{Field: "team", Value: "Manchester City"}).
+			OrderBy(base.Sort{Column: "age"}).
+			First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Raheem Sterling", data.Get("name"))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		data, err := client.Query("players", term.Equal{Field: "team", Value: "Arsenal"}).First()
+
+		assert.NotNil(t, err)
+		assert.Equal(t, *base.ZeroRecordData(), data)
+	})
+}
+
+func TestMemoryQuery_Count(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	count, err := client.Query("players", term.Equal{Field: "team", Value: "Manchester City"}).Count()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestMemoryQuery_CountDistinct(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	t.Run("values", func(t *testing.T) {
+		count, err := client.Query("players", term.GreaterThan{Field: "age", Value: 25}).CountDistinct("team")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("ignoreNull", func(t *testing.T) {
+		count, err := client.Query("players").CountDistinct("banned_date")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+func TestMemoryQuery_Distinct(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	t.Run("values", func(t *testing.T) {
+		values, err := client.Query("players").OrderBy(base.Sort{Column: "team"}).Distinct("team")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{"Leicester City", "Liverpool", "Manchester City"}, values)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		values, err := client.Query("players").Distinct("banned_date")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{nil, "2019-11-10"}, values)
+	})
+}
+
+func TestMemoryQuery_Aggregate(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	t.Run("groups", func(t *testing.T) {
+		results, err := client.Query("players").
+			GroupBy("team").
+			Having(term.GreaterThan{Field: "count", Value: 1}).
+			Aggregate(
+				base.Aggregation{Func: base.SumFunc, Field: "age", Alias: "sum_age"},
+				base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"},
+			)
+
+		assert.Nil(t, err)
+		assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+			[]string{"team", "sum_age", "count"},
+			base.RecordMap{"team": "Manchester City", "sum_age": float64(56), "count": int64(2)},
+		)}, results)
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		results, err := client.Query("players").
+			GroupBy("team").
+			OrderBy(base.Sort{Column: "max_rate", Descending: true}).
+			Skip(1).
+			Limit(1).
+			Aggregate(base.Aggregation{Func: base.MaxFunc, Field: "rate", Alias: "max_rate"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+			[]string{"team", "max_rate"},
+			base.RecordMap{"team": "Liverpool", "max_rate": 8.2},
+		)}, results)
+	})
+
+	t.Run("noGroups", func(t *testing.T) {
+		results, err := client.Query("players", term.Equal{Field: "team", Value: "Manchester City"}).Aggregate(
+			base.Aggregation{Func: base.AvgFunc, Field: "age", Alias: "avg_age"},
+			base.Aggregation{Func: base.MinFunc, Field: "name", Alias: "min_name"},
+			base.Aggregation{Func: base.CountFunc, Field: "banned_date", Alias: "count_banned_date"},
+		)
+
+		assert.Nil(t, err)
+		assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+			[]string{"avg_age", "min_name", "count_banned_date"},
+			base.RecordMap{"avg_age": float64(28), "min_name": "Raheem Sterling", "count_banned_date": int64(1)},
+		)}, results)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		results, err := client.Query("players", term.Equal{Field: "team", Value: "Arsenal"}).
+			Aggregate(base.Aggregation{Func: base.SumFunc, Field: "age", Alias: "sum_age"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+			[]string{"sum_age"}, base.RecordMap{"sum_age": nil},
+		)}, results)
+	})
+}
+
+func TestMemoryQuery_projection(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	t.Run("select", func(t *testing.T) {
+		data, err := client.Query("players").Select("name", "age").First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, *base.NewRecordData([]string{"name", "age"}, base.RecordMap{"name": "Sergio Aguero", "age": 31}), data)
+	})
+
+	t.Run("omit", func(t *testing.T) {
+		results, err := client.Query("players").Omit("rate", "banned_date").All()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"name", "team", "age", "id"}, results[0].GetColumns())
+	})
+}
+
+func TestMemoryQuery_Exists(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	exists, err := client.Query("players", term.Equal{Field: "team", Value: "Liverpool"}).Exists()
+
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.Query("players", term.Equal{Field: "team", Value: "Arsenal"}).Exists()
+
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
+
+func TestMemoryQuery_Update(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		data := base.NewRecordData([]string{"team"}, base.RecordMap{"team": "Arsenal"})
+		count, err := client.Query("players", term.GreaterThan{Field: "age", Value: 30}).Update(*data)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, count)
+
+		names := queryNames(t, client.Query("players", term.Equal{Field: "team", Value: "Arsenal"}))
+		assert.Equal(t, []string{"Sergio Aguero", "Jamie Vardy"}, names)
+	})
+
+	t.Run("emptyData", func(t *testing.T) {
+		client := initMemory(t)
+
+		_, err := client.Query("players").Update(*base.ZeroRecordData())
+
+		assert.Equal(t, errEmptyUpdate, err)
+	})
+}
+
+func TestMemoryQuery_Delete(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	count, err := client.Query("players", term.Equal{Field: "team", Value: "Manchester City"}).Delete()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"Mohamed Salah", "Jamie Vardy"}, queryNames(t, client.Query("players")))
+}
+
+func TestCompareValues(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		a          interface{}
+		b          interface{}
+		expected   int
+		comparable bool
+	}{
+		{"intInt64", 1, int64(1), 0, true},
+		{"intFloat", 1, 1.5, -1, true},
+		{"negativeUint", -1, uint64(1), -1, true},
+		{"largeUint", uint64(1 << 63), int64(1), 1, true},
+		{"strings", "b", "a", 1, true},
+		{"bools", false, true, -1, true},
+		{"times", now, now.Add(time.Second), -1, true},
+		{"nil", nil, 1, 0, false},
+		{"mixed", "1", 1, 0, false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cmp, ok := compareValues(testCase.a, testCase.b)
+
+			assert.Equal(t, testCase.expected, cmp)
+			assert.Equal(t, testCase.comparable, ok)
+		})
+	}
+}
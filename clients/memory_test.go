@@ -0,0 +1,356 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+func initMemory(t *testing.T) *Memory {
+	t.Helper()
+
+	DropMemoryDatabase(t.Name())
+	client := NewMemory(t.Name()).(*Memory)
+
+	return client
+}
+
+func insertPlayers(t *testing.T, client *Memory) {
+	t.Helper()
+
+	players := []base.RecordMap{
+		{"name": "Sergio Aguero", "team": "Manchester City", "age": 31, "rate": 8.5, "banned_date": nil},
+		{"name": "Mohamed Salah", "team": "Liverpool", "age": 27, "rate": 8.2, "banned_date": nil},
+		{"name": "Raheem Sterling", "team": "Manchester City", "age": 25, "rate": 7.9, "banned_date": "2019-11-10"},
+		{"name": "Jamie Vardy", "team": "Leicester City", "age": 32, "rate": 7.9, "banned_date": nil},
+	}
+
+	for _, player := range players {
+		data := base.NewRecordData([]string{"name", "team", "age", "rate", "banned_date"}, player)
+		assert.Nil(t, client.Insert("players", base.Key{"id"}, data))
+	}
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestNewMemory(t *testing.T) {
+	defer DropMemoryDatabase("test")
+
+	first := NewMemory("test").(*Memory)
+	second := NewMemory("test").(*Memory)
+	other := NewMemory("other").(*Memory)
+	defer DropMemoryDatabase("other")
+
+	assert.Same(t, first.database, second.database)
+	assert.NotSame(t, first.database, other.database)
+
+	DropMemoryDatabase("test")
+	third := NewMemory("test").(*Memory)
+
+	assert.NotSame(t, first.database, third.database)
+}
+
+func TestMemory_CreateTable(t *testing.T) {
+	client := initMemory(t)
+	err := client.CreateTable("users", base.TableStructure{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, client.database.tables, "users")
+}
+
+func TestMemory_EnsureIndex(t *testing.T) {
+	t.Run("unique", func(t *testing.T) {
+		client := initMemory(t)
+		index := base.Index{Columns: []string{"name"}, Unique: true}
+
+		assert.Nil(t, client.EnsureIndex("users", index))
+		assert.Nil(t, client.EnsureIndex("users", index))
+		assert.Equal(t, []base.Index{index}, client.database.tables["users"].indices)
+	})
+
+	t.Run("duplicateData", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		err := client.EnsureIndex("players", base.Index{Columns: []string{"team"}, Unique: true})
+
+		assert.NotNil(t, err)
+		assert.Empty(t, client.database.tables["players"].indices)
+	})
+}
+
+func TestMemory_Insert(t *testing.T) {
+	t.Run("autoIncrement", func(t *testing.T) {
+		client := initMemory(t)
+		data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), data.Get("id"))
+		assert.Equal(t, "Test", data.Get("name"))
+	})
+
+	t.Run("explicitID", func(t *testing.T) {
+		client := initMemory(t)
+		data := base.NewRecordData([]string{"id", "name"}, base.RecordMap{"id": 10, "name": "Test"})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+
+		data = base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Next"})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+
+		assert.Equal(t, int64(11), data.Get("id"))
+	})
+
+	t.Run("duplicateID", func(t *testing.T) {
+		client := initMemory(t)
+		data := base.NewRecordData([]string{"id"}, base.RecordMap{"id": 1})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+
+		data = base.NewRecordData([]string{"id"}, base.RecordMap{"id": int64(1)})
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.NotNil(t, err)
+		assert.Len(t, client.database.tables["users"].records, 1)
+	})
+
+	t.Run("uniqueIndex", func(t *testing.T) {
+		client := initMemory(t)
+		assert.Nil(t, client.EnsureIndex("users", base.Index{Columns: []string{"email"}, Unique: true}))
+
+		data := base.NewRecordData([]string{"email"}, base.RecordMap{"email": nil})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+		data = base.NewRecordData([]string{"email"}, base.RecordMap{"email": nil})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+		data = base.NewRecordData([]string{"email"}, base.RecordMap{"email": "test@example.com"})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+
+		data = base.NewRecordData([]string{"email"}, base.RecordMap{"email": "test@example.com"})
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("customKey", func(t *testing.T) {
+		client := initMemory(t)
+		data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+		assert.Nil(t, client.Insert("users", base.Key{"user_id"}, data))
+
+		assert.Equal(t, int64(1), data.Get("user_id"))
+		assert.Nil(t, data.Get("id"))
+
+		data = base.NewRecordData([]string{"user_id"}, base.RecordMap{"user_id": 1})
+		err := client.Insert("users", base.Key{"user_id"}, data)
+
+		assert.True(t, errors.Is(err, base.ErrDuplicateKey))
+	})
+
+	t.Run("compositeKey", func(t *testing.T) {
+		client := initMemory(t)
+		key := base.Key{"user_id", "group_id"}
+		data := base.NewRecordData([]string{"user_id", "group_id"}, base.RecordMap{"user_id": 1, "group_id": 1})
+		assert.Nil(t, client.Insert("members", key, data))
+
+		data = base.NewRecordData([]string{"user_id", "group_id"}, base.RecordMap{"user_id": 1, "group_id": 2})
+		assert.Nil(t, client.Insert("members", key, data))
+		assert.Nil(t, data.Get("id"))
+
+		data = base.NewRecordData([]string{"user_id", "group_id"}, base.RecordMap{"user_id": 1, "group_id": 2})
+		err := client.Insert("members", key, data)
+
+		assert.True(t, errors.Is(err, base.ErrDuplicateKey))
+	})
+
+	t.Run("copyData", func(t *testing.T) {
+		client := initMemory(t)
+		data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+		assert.Nil(t, client.Insert("users", base.Key{"id"}, data))
+
+		data.Set("name", "Changed")
+		record, err := client.FindByID("users", base.Key{"id"}, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Test", record.Get("name"))
+	})
+}
+
+func TestMemory_FindByID(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		data, err := client.FindByID("players", base.Key{"id"}, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), data.Get("id"))
+		assert.Equal(t, "Mohamed Salah", data.Get("name"))
+		assert.Equal(t, 27, data.Get("age"))
+	})
+
+	t.Run("customKey", func(t *testing.T) {
+		client := initMemory(t)
+		data := base.NewRecordData([]string{"code", "name"}, base.RecordMap{"code": "IR", "name": "Iran"})
+		assert.Nil(t, client.Insert("countries", base.Key{"code"}, data))
+
+		record, err := client.FindByID("countries", base.Key{"code"}, "IR")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Iran", record.Get("name"))
+	})
+
+	t.Run("compositeKey", func(t *testing.T) {
+		client := initMemory(t)
+		key := base.Key{"user_id", "group_id"}
+		for _, group := range []int{1, 2} {
+			data := base.NewRecordData([]string{"user_id", "group_id", "role"}, base.RecordMap{"user_id": 1, "group_id": group, "role": group})
+			assert.Nil(t, client.Insert("members", key, data))
+		}
+
+		record, err := client.FindByID("members", key, []interface{}{1, 2})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, record.Get("role"))
+
+		_, err = client.FindByID("members", key, 1)
+		assert.True(t, errors.Is(err, base.ErrInvalidID))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		data, err := client.FindByID("players", base.Key{"id"}, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, *base.ZeroRecordData(), data)
+	})
+}
+
+func TestMemory_UpdateByID(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		data := base.NewRecordData([]string{"team", "age"}, base.RecordMap{"team": "Real Madrid", "age": 28})
+		err := client.UpdateByID("players", base.Key{"id"}, 2, *data)
+
+		assert.Nil(t, err)
+
+		record, _ := client.FindByID("players", base.Key{"id"}, 2)
+		assert.Equal(t, "Mohamed Salah", record.Get("name"))
+		assert.Equal(t, "Real Madrid", record.Get("team"))
+		assert.Equal(t, 28, record.Get("age"))
+	})
+
+	t.Run("uniqueIndex", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+		assert.Nil(t, client.EnsureIndex("players", base.Index{Columns: []string{"name"}, Unique: true}))
+
+		data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Jamie Vardy"})
+		err := client.UpdateByID("players", base.Key{"id"}, 2, *data)
+
+		assert.NotNil(t, err)
+
+		record, _ := client.FindByID("players", base.Key{"id"}, 2)
+		assert.Equal(t, "Mohamed Salah", record.Get("name"))
+	})
+}
+
+func TestMemory_DeleteByID(t *testing.T) {
+	client := initMemory(t)
+	insertPlayers(t, client)
+
+	err := client.DeleteByID("players", base.Key{"id"}, 2)
+
+	assert.Nil(t, err)
+
+	_, err = client.FindByID("players", base.Key{"id"}, 2)
+	assert.NotNil(t, err)
+	assert.Len(t, client.database.tables["players"].records, 3)
+}
+
+func TestMemory_Query(t *testing.T) {
+	conditions := []base.Condition{
+		term.Equal{Field: "name", Value: "Test"},
+	}
+
+	client := initMemory(t)
+	r := client.Query("users", conditions...)
+
+	assert.IsType(t, new(memoryQuery), r)
+
+	q := r.(*memoryQuery)
+
+	assert.Equal(t, conditions, q.conditions)
+	assert.Equal(t, "users", q.table)
+	assert.Equal(t, client.database, q.database)
+}
+
+func TestMemory_Begin(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		txClient, err := client.Begin()
+		assert.Nil(t, err)
+
+		assert.Nil(t, txClient.DeleteByID("players", base.Key{"id"}, 1))
+		data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Harry Kane"})
+		assert.Nil(t, txClient.Insert("players", base.Key{"id"}, data))
+		assert.Equal(t, int64(5), data.Get("id"))
+
+		count, _ := client.Query("players").Count()
+		assert.Equal(t, 4, count)
+
+		assert.Nil(t, txClient.Commit())
+
+		_, err = client.FindByID("players", base.Key{"id"}, 1)
+		assert.NotNil(t, err)
+		record, err := client.FindByID("players", base.Key{"id"}, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, "Harry Kane", record.Get("name"))
+		assert.Equal(t, errNoTransaction, txClient.Commit())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		client := initMemory(t)
+		insertPlayers(t, client)
+
+		txClient, err := client.Begin()
+		assert.Nil(t, err)
+
+		_, err = txClient.Query("players").Delete()
+		assert.Nil(t, err)
+		assert.Nil(t, txClient.Rollback())
+
+		count, _ := client.Query("players").Count()
+		assert.Equal(t, 4, count)
+		assert.Equal(t, errNoTransaction, txClient.Rollback())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		client := initMemory(t)
+		txClient, _ := client.Begin()
+
+		_, err := txClient.(*Memory).Begin()
+
+		assert.Equal(t, errNestedTransaction, err)
+	})
+}
+
+func TestMemory_Close(t *testing.T) {
+	client := initMemory(t)
+	client.Close()
+
+	assert.Nil(t, client.database)
+}
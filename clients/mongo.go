@@ -1,6 +1,10 @@
 package clients
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/Kamva/octopus/base"
 	"github.com/Kamva/octopus/term"
 	"github.com/Kamva/shark"
@@ -13,6 +17,11 @@ type MongoDB struct {
 	session    base.MongoSession
 	dbName     string
 	collection base.MongoCollection
+
+	// url and ctx are kept for starting transactions by the official
+	// driver, as mgo does not support transactions.
+	url string
+	ctx context.Context
 }
 
 // CreateTable creates a `collectionName` collection. Since MongoDB is a
@@ -44,7 +53,8 @@ func (c *MongoDB) EnsureIndex(collectionName string, index base.Index) error {
 // Insert tries to insert `data` into `collectionName` and returns error if
 // anything went wrong. `data` should pass by reference to have exact
 // data on `collectionName`, otherwise updated record data isn't accessible.
-func (c *MongoDB) Insert(collectionName string, data *base.RecordData) error {
+// MongoDB documents are always identified by `_id`, so `key` is ignored.
+func (c *MongoDB) Insert(collectionName string, key base.Key, data *base.RecordData) error {
 	data.Set("_id", bson.NewObjectId())
 	err := c.GetCollection(collectionName).Insert(data.GetMap())
 
@@ -52,12 +62,17 @@ func (c *MongoDB) Insert(collectionName string, data *base.RecordData) error {
 }
 
 // FindByID searches through `collectionName` documents to find a doc that its
-// ID match with `id` and returns it alongside any possible error.
-func (c *MongoDB) FindByID(collectionName string, id interface{}) (base.RecordData, error) {
+// `_id` match with `id` and returns it alongside any possible error.
+func (c *MongoDB) FindByID(collectionName string, key base.Key, id interface{}) (base.RecordData, error) {
 	data := base.ZeroRecordData()
 	doc := make(base.RecordMap)
 
-	err := queryByID(c, collectionName, id).One(&doc)
+	objectID, err := convertObjectID(id)
+	if err != nil {
+		return *data, err
+	}
+
+	err = queryByID(c, collectionName, objectID).One(&doc)
 
 	for key, value := range doc {
 		data.Set(key, value)
@@ -66,15 +81,21 @@ func (c *MongoDB) FindByID(collectionName string, id interface{}) (base.RecordDa
 	return *data, err
 }
 
-// UpdateByID finds a document in `collectionName` that its ID match with `id`,
-// and updates it with data. It will return error if anything went wrong.
-func (c *MongoDB) UpdateByID(collectionName string, id interface{}, data base.RecordData) error {
-	return c.GetCollection(collectionName).UpdateId(id, data.GetMap())
+// UpdateByID finds a document in `collectionName` that its `_id` match with
+// `id`, and updates it with data. It will return error if anything went wrong.
+// Only fields in `data` are set, and other fields of document are kept.
+func (c *MongoDB) UpdateByID(collectionName string, key base.Key, id interface{}, data base.RecordData) error {
+	set := bson.M{}
+	for column, value := range *data.GetMap() {
+		set[column] = value
+	}
+
+	return c.GetCollection(collectionName).UpdateId(id, bson.M{"$set": set})
 }
 
-// DeleteByID finds a document in `collectionName` that its ID match with `id`,
-// and remove it entirely. It will return error if anything went wrong.
-func (c *MongoDB) DeleteByID(collectionName string, id interface{}) error {
+// DeleteByID finds a document in `collectionName` that its `_id` match with
+// `id`, and remove it entirely. It will return error if anything went wrong.
+func (c *MongoDB) DeleteByID(collectionName string, key base.Key, id interface{}) error {
 	return c.GetCollection(collectionName).RemoveId(id)
 }
 
@@ -83,7 +104,67 @@ func (c *MongoDB) Query(collectionName string, conditions ...base.Condition) bas
 	queryMap := c.parseConditions(conditions...)
 	query := queryMongoDB(c, collectionName, queryMap)
 
-	return newMongoQuery(query, c.GetCollection(collectionName), queryMap)
+	return newMongoQuery(query, c.GetCollection(collectionName), queryMap, c.parseConditions)
+}
+
+// Raw returns a query fetching documents of `collectionName` matching raw
+// `query` filter, which should be a `bson.M`. Mongo filters have no
+// arguments, so `args` are ignored.
+func (c *MongoDB) Raw(collectionName string, query interface{}, args ...interface{}) base.RawQuery {
+	filter, ok := query.(bson.M)
+	if !ok {
+		panic(fmt.Errorf("%w: raw mongodb query should be a bson.M filter, got %T", base.ErrUnsupportedType, query))
+	}
+
+	return newMongoQuery(queryMongoDB(c, collectionName, filter), c.GetCollection(collectionName), filter, c.parseConditions)
+}
+
+// Exec runs database `command`, e.g. a `bson.D` of an update or delete
+// command, and returns the number of affected documents reported by the
+// command. Mongo commands have no arguments, so `args` are ignored.
+func (c *MongoDB) Exec(command interface{}, args ...interface{}) (int, error) {
+	result := make(bson.M)
+	if err := runCommand(c, command, &result); err != nil {
+		return 0, err
+	}
+
+	n, _ := toInt64(result["n"])
+
+	return int(n), nil
+}
+
+// Aggregate runs aggregation `pipeline` on `collectionName` and returns
+// the documents resulted from the last stage of pipeline.
+func (c *MongoDB) Aggregate(collectionName string, pipeline *Pipeline) (base.RecordDataSet, error) {
+	items := make([]base.RecordMap, 0)
+	if err := runPipe(c.GetCollection(collectionName), pipeline.Stages(), &items); err != nil {
+		return nil, err
+	}
+
+	resultSet := make(base.RecordDataSet, 0, len(items))
+	for _, item := range items {
+		data := base.ZeroRecordData()
+		for key, value := range item {
+			data.Set(key, value)
+		}
+
+		resultSet = append(resultSet, *data)
+	}
+
+	return resultSet, nil
+}
+
+// Begin starts a new transaction and returns a client bound to it, which
+// runs all of its operations in the transaction. Transactions run in a new
+// session of the official MongoDB driver, so they are only supported by
+// replica sets and sharded clusters of MongoDB 4.0 and newer.
+func (c *MongoDB) Begin() (base.TxClient, error) {
+	session, err := startMongoTx(orBackground(c.ctx), c.url, c.dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoTx{session: session}, nil
 }
 
 // Close disconnect client from database and release the taken memory
@@ -92,6 +173,49 @@ func (c *MongoDB) Close() {
 	c.session = nil
 	c.collection = nil
 	c.dbName = ""
+	c.url = ""
+}
+
+// ConfigurePool applies connection pool settings of `config` on session
+func (c *MongoDB) ConfigurePool(config base.PoolConfig) {
+	if config.PoolLimit > 0 {
+		c.session.SetPoolLimit(config.PoolLimit)
+	}
+}
+
+// Acquire returns a client with a copy of session, which uses sockets
+// of the session pool. Closing the client releases its socket.
+func (c *MongoDB) Acquire() base.Client {
+	return &MongoDB{
+		session: copySession(c.session),
+		dbName:  c.dbName,
+		url:     c.url,
+		ctx:     c.ctx,
+	}
+}
+
+// WithContext returns a client with a copy of session, which its socket
+// timeout is set to the deadline of `ctx`. The mgo driver does not support
+// cancellation, so only the deadline of context is propagated to database.
+// Transactions started by the client are run with `ctx`.
+func (c *MongoDB) WithContext(ctx context.Context) base.Client {
+	session := copySession(c.session)
+	if deadline, ok := ctx.Deadline(); ok {
+		session.SetSocketTimeout(socketTimeout(deadline))
+	}
+
+	return &MongoDB{session: session, dbName: c.dbName, url: c.url, ctx: ctx}
+}
+
+// socketTimeout returns the remaining time to `deadline`. Zero timeout
+// disables the socket timeout of mgo, so expired deadlines get the
+// minimum timeout instead.
+func socketTimeout(deadline time.Time) time.Duration {
+	if timeout := time.Until(deadline); timeout > 0 {
+		return timeout
+	}
+
+	return time.Nanosecond
 }
 
 // GetCollection return collection instance with given name
@@ -103,72 +227,130 @@ func (c *MongoDB) GetCollection(collection string) base.MongoCollection {
 	return c.collection
 }
 
-// convert given interface id to objectId
-func (c *MongoDB) convertID(id interface{}) bson.ObjectId {
+// convertObjectID converts given interface id to objectId
+func convertObjectID(id interface{}) (bson.ObjectId, error) {
 	switch id.(type) {
 	case string:
-		return bson.ObjectIdHex(id.(string))
+		if bson.IsObjectIdHex(id.(string)) {
+			return bson.ObjectIdHex(id.(string)), nil
+		}
 	case bson.ObjectId:
-		return id.(bson.ObjectId)
+		if id.(bson.ObjectId).Valid() {
+			return id.(bson.ObjectId), nil
+		}
 	}
 
-	panic("Invalid ID for mongodb document.")
+	return "", fmt.Errorf("%w: %v is not a valid mongodb document id", base.ErrInvalidID, id)
 }
 
 // Parse conditions query into map of mongo query (bson.M)
 func (c *MongoDB) parseConditions(conditions ...base.Condition) bson.M {
 	queryMap := make(bson.M)
 	for _, condition := range conditions {
-		switch condition.(type) {
-		case term.Equal:
-			queryMap[condition.GetField()] = condition.GetValue()
-			break
-		case term.GreaterThan:
-			queryMap[condition.GetField()] = bson.M{
-				"$gt": condition.GetValue(),
-			}
-			break
-		case term.GreaterThanEqual:
-			queryMap[condition.GetField()] = bson.M{
-				"$gte": condition.GetValue(),
-			}
-			break
-		case term.In:
-			queryMap[condition.GetField()] = bson.M{
-				"$in": condition.GetValue(),
-			}
-			break
-		case term.IsNull:
-			queryMap[condition.GetField()] = bson.M{
-				"$eq": condition.GetValue(),
-			}
-			break
-		case term.LessThan:
-			queryMap[condition.GetField()] = bson.M{
-				"$lt": condition.GetValue(),
-			}
-			break
-		case term.LessThanEqual:
-			queryMap[condition.GetField()] = bson.M{
-				"$lte": condition.GetValue(),
-			}
-			break
-		case term.NotEqual:
-			queryMap[condition.GetField()] = bson.M{
-				"$ne": condition.GetValue(),
+		for key, value := range c.parseCondition(condition) {
+			// If the key is already in query map (e.g. multiple conditions on a
+			// field or multiple condition groups), it is added to `$and` list
+			// to prevent overwriting the previous condition.
+			if _, ok := queryMap[key]; ok {
+				and, _ := queryMap["$and"].([]bson.M)
+				queryMap["$and"] = append(and, bson.M{key: value})
+			} else {
+				queryMap[key] = value
 			}
-			break
-		case term.NotNull:
-			queryMap[condition.GetField()] = bson.M{
-				"$ne": condition.GetValue(),
-			}
-			break
 		}
 	}
 
 	return queryMap
 }
 
+// Parse a condition into mongo query. Condition groups are parsed recursively.
+func (c *MongoDB) parseCondition(condition base.Condition) bson.M {
+	switch condition.(type) {
+	case term.Equal:
+		return bson.M{condition.GetField(): encodeValue(condition.GetValue())}
+	case term.GreaterThan:
+		return bson.M{condition.GetField(): bson.M{
+			"$gt": encodeValue(condition.GetValue()),
+		}}
+	case term.GreaterThanEqual:
+		return bson.M{condition.GetField(): bson.M{
+			"$gte": encodeValue(condition.GetValue()),
+		}}
+	case term.In:
+		return bson.M{condition.GetField(): bson.M{
+			"$in": encodeValue(condition.GetValue()),
+		}}
+	case term.IsNull:
+		return bson.M{condition.GetField(): bson.M{
+			"$eq": condition.GetValue(),
+		}}
+	case term.LessThan:
+		return bson.M{condition.GetField(): bson.M{
+			"$lt": encodeValue(condition.GetValue()),
+		}}
+	case term.LessThanEqual:
+		return bson.M{condition.GetField(): bson.M{
+			"$lte": encodeValue(condition.GetValue()),
+		}}
+	case term.NotEqual:
+		return bson.M{condition.GetField(): bson.M{
+			"$ne": encodeValue(condition.GetValue()),
+		}}
+	case term.NotNull:
+		return bson.M{condition.GetField(): bson.M{
+			"$ne": condition.GetValue(),
+		}}
+	case term.NotIn:
+		return bson.M{condition.GetField(): bson.M{
+			"$nin": encodeValue(condition.GetValue()),
+		}}
+	case term.Between:
+		values := encodeValue(condition.GetValue()).([]interface{})
+		return bson.M{condition.GetField(): bson.M{
+			"$gte": values[0], "$lte": values[1],
+		}}
+	case term.Exists:
+		return bson.M{condition.GetField(): bson.M{
+			"$exists": true,
+		}}
+	case term.ArrayContains:
+		return bson.M{condition.GetField(): bson.M{
+			"$all": encodeValue(condition.GetValue()),
+		}}
+	case term.ArrayOverlaps:
+		return bson.M{condition.GetField(): bson.M{
+			"$in": encodeValue(condition.GetValue()),
+		}}
+	case term.ElemMatch:
+		return bson.M{condition.GetField(): bson.M{
+			"$elemMatch": c.parseConditions(condition.GetValue().([]base.Condition)...),
+		}}
+	case term.Like, term.ILike, term.Regex, term.StartsWith, term.EndsWith, term.Contains:
+		pattern, options := patternRegex(condition)
+		return bson.M{condition.GetField(): bson.RegEx{
+			Pattern: pattern, Options: options,
+		}}
+	case term.And:
+		return bson.M{"$and": c.parseGroup(condition.GetValue().([]base.Condition))}
+	case term.Or:
+		return bson.M{"$or": c.parseGroup(condition.GetValue().([]base.Condition))}
+	case term.Not:
+		return bson.M{"$nor": []bson.M{c.parseCondition(condition.GetValue().(base.Condition))}}
+	}
+
+	return bson.M{}
+}
+
+// Parse each condition of a condition group into a separate mongo query
+func (c *MongoDB) parseGroup(conditions []base.Condition) []bson.M {
+	queries := make([]bson.M, 0, len(conditions))
+	for _, condition := range conditions {
+		queries = append(queries, c.parseCondition(condition))
+	}
+
+	return queries
+}
+
 // NewMongoDB instantiates and returns a ne MongoDB session object
 func NewMongoDB(url string, dbName string) base.Client {
 	session, err := dial(url)
@@ -177,6 +359,7 @@ func NewMongoDB(url string, dbName string) base.Client {
 	return &MongoDB{
 		session: session,
 		dbName:  dbName,
+		url:     url,
 	}
 }
 
@@ -185,10 +368,22 @@ var dial = func(url string) (base.MongoSession, error) {
 	return mgo.Dial(url)
 }
 
+var copySession = func(session base.MongoSession) base.MongoSession {
+	return session.Copy()
+}
+
 var queryByID = func(c *MongoDB, collection string, id interface{}) base.MongoQuery {
-	return c.GetCollection(collection).FindId(c.convertID(id))
+	return c.GetCollection(collection).FindId(id)
 }
 
 var queryMongoDB = func(c *MongoDB, collection string, conditions bson.M) base.MongoQuery {
 	return c.GetCollection(collection).Find(conditions)
 }
+
+var runPipe = func(collection base.MongoCollection, pipeline []bson.M, result interface{}) error {
+	return collection.Pipe(pipeline).All(result)
+}
+
+var runCommand = func(c *MongoDB, command interface{}, result interface{}) error {
+	return c.session.DB(c.dbName).Run(command, result)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Kamva/octopus/base"
+	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 )
 
@@ -12,10 +13,19 @@ type mongoQuery struct {
 	query      base.MongoQuery
 	collection base.MongoCollection
 	queryMap   bson.M
+	parser     func(conditions ...base.Condition) bson.M
+
+	// Options of query are kept for building aggregation pipelines
+	sorts  []base.Sort
+	limit  int
+	skip   int
+	groups []string
+	having []base.Condition
 }
 
 // OrderBy set the order of returning result in following command
 func (q *mongoQuery) OrderBy(sorts ...base.Sort) base.QueryBuilder {
+	q.sorts = sorts
 	for _, sort := range sorts {
 		if sort.Descending {
 			q.query.Sort(fmt.Sprintf("-%s", sort.Column))
@@ -30,6 +40,7 @@ func (q *mongoQuery) OrderBy(sorts ...base.Sort) base.QueryBuilder {
 // Limit set the limit that determines how many results should be
 // returned in the following fetch command.
 func (q *mongoQuery) Limit(n int) base.QueryBuilder {
+	q.limit = n
 	q.query.Limit(n)
 
 	return q
@@ -37,18 +48,118 @@ func (q *mongoQuery) Limit(n int) base.QueryBuilder {
 
 // Skip set the starting offset of the following fetch command
 func (q *mongoQuery) Skip(n int) base.QueryBuilder {
+	q.skip = n
 	q.query.Skip(n)
 
 	return q
 }
 
+// Select limits fields of documents fetched by the following fetch command
+// to `fields`. Note that `_id` is always fetched.
+func (q *mongoQuery) Select(fields ...string) base.QueryBuilder {
+	q.query.Select(projection(fields, 1))
+
+	return q
+}
+
+// Omit excludes `fields` from fields of documents fetched by the following
+// fetch command.
+func (q *mongoQuery) Omit(fields ...string) base.QueryBuilder {
+	q.query.Select(projection(fields, 0))
+
+	return q
+}
+
+// GroupBy groups documents by values of `fields` in the following aggregate
+// command.
+func (q *mongoQuery) GroupBy(fields ...string) base.QueryBuilder {
+	q.groups = fields
+
+	return q
+}
+
+// Having filters groups of the following aggregate command by `conditions`,
+// which fields could be aliases of aggregations.
+func (q *mongoQuery) Having(conditions ...base.Condition) base.QueryBuilder {
+	q.having = conditions
+
+	return q
+}
+
 // Count execute a count command that will return the number records in
 // specified destination table. If the query conditions was empty, it
-// returns number of all records un destination table.
+// returns number of all records un destination table. Limit and skip of
+// query are not applied, the same as SQL databases, so counts could be
+// used as total of paginated queries.
 func (q *mongoQuery) Count() (int, error) {
+	if q.limit == 0 && q.skip == 0 {
+		return q.query.Count()
+	}
+
+	q.query.Limit(0)
+	q.query.Skip(0)
+	defer func() {
+		q.query.Limit(q.limit)
+		q.query.Skip(q.skip)
+	}()
+
 	return q.query.Count()
 }
 
+// CountDistinct execute a count command that will return the number of
+// distinct values of `field` in documents matching the query conditions.
+func (q *mongoQuery) CountDistinct(field string) (int, error) {
+	values := make([]interface{}, 0)
+	err := q.query.Distinct(field, &values)
+
+	return len(values), err
+}
+
+// Distinct returns distinct values of `field` in documents matching the
+// query conditions.
+func (q *mongoQuery) Distinct(field string) ([]interface{}, error) {
+	values := make([]interface{}, 0)
+	err := q.query.Distinct(field, &values)
+
+	return values, err
+}
+
+// Aggregate returns a row of grouping fields and `aggregations` for each
+// group of documents matching the query conditions, by running a `$group`
+// pipeline. All documents matching the conditions are aggregated in one row
+// if no grouping field is set.
+func (q *mongoQuery) Aggregate(aggregations ...base.Aggregation) (base.RecordDataSet, error) {
+	items := make([]base.RecordMap, 0)
+	if err := runPipe(q.collection, q.groupPipeline(aggregations), &items); err != nil {
+		return nil, err
+	}
+
+	resultSet := make(base.RecordDataSet, 0, len(items))
+	for _, item := range items {
+		data := base.ZeroRecordData()
+		for _, field := range q.groups {
+			data.Set(field, item[field])
+		}
+		for _, aggregation := range aggregations {
+			data.Set(aggregation.Alias, item[aggregation.Alias])
+		}
+
+		resultSet = append(resultSet, *data)
+	}
+
+	return resultSet, nil
+}
+
+// Exists checks whether any document matches with the query conditions
+func (q *mongoQuery) Exists() (bool, error) {
+	err := q.query.One(nil)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
 // First fetch data of the first record that match with query conditions.
 func (q *mongoQuery) First() (base.RecordData, error) {
 	data := base.ZeroRecordData()
@@ -104,8 +215,11 @@ func (q *mongoQuery) Update(data base.RecordData) (int, error) {
 	update := bson.M{"$set": set}
 
 	changeInfo, err := q.collection.UpdateAll(q.queryMap, update)
+	if err != nil {
+		return 0, err
+	}
 
-	return changeInfo.Updated, err
+	return changeInfo.Updated, nil
 }
 
 // Delete removes every records in destination table that match with condition
@@ -114,10 +228,80 @@ func (q *mongoQuery) Update(data base.RecordData) (int, error) {
 // was set.
 func (q *mongoQuery) Delete() (int, error) {
 	changeInfo, err := q.collection.RemoveAll(q.queryMap)
+	if err != nil {
+		return 0, err
+	}
+
+	return changeInfo.Removed, nil
+}
+
+func newMongoQuery(
+	query base.MongoQuery,
+	collection base.MongoCollection,
+	queryMap bson.M,
+	parser func(conditions ...base.Condition) bson.M,
+) *mongoQuery {
+	return &mongoQuery{query: query, collection: collection, queryMap: queryMap, parser: parser}
+}
+
+// projection returns the projection document of including (`include` is 1)
+// or excluding (`include` is 0) `fields`.
+func projection(fields []string, include int) bson.M {
+	selector := make(bson.M, len(fields))
+	for _, field := range fields {
+		selector[field] = include
+	}
 
-	return changeInfo.Removed, err
+	return selector
 }
 
-func newMongoQuery(query base.MongoQuery, collection base.MongoCollection, queryMap bson.M) *mongoQuery {
-	return &mongoQuery{query: query, collection: collection, queryMap: queryMap}
+// groupPipeline builds the aggregation pipeline that groups documents matching
+// query conditions and computes `aggregations` of each group. Having
+// conditions and sorts could use grouping fields and aggregation aliases.
+func (q *mongoQuery) groupPipeline(aggregations []base.Aggregation) []bson.M {
+	pipeline := &Pipeline{parser: q.parser}
+	pipeline.Stage(bson.M{"$match": q.queryMap}).Group(q.groups, aggregations...)
+
+	if len(q.having) > 0 {
+		pipeline.Match(q.having...)
+	}
+
+	if len(q.sorts) > 0 {
+		pipeline.Sort(q.sorts...)
+	}
+
+	if q.skip > 0 {
+		pipeline.Skip(q.skip)
+	}
+
+	if q.limit > 0 {
+		pipeline.Limit(q.limit)
+	}
+
+	return pipeline.Stages()
+}
+
+// aggregationOperator returns the `$group` accumulator of `aggregation`
+func aggregationOperator(aggregation base.Aggregation) bson.M {
+	field := "$" + aggregation.Field
+
+	switch aggregation.Func {
+	case base.SumFunc:
+		return bson.M{"$sum": field}
+	case base.AvgFunc:
+		return bson.M{"$avg": field}
+	case base.MinFunc:
+		return bson.M{"$min": field}
+	case base.MaxFunc:
+		return bson.M{"$max": field}
+	case base.CountFunc:
+		if aggregation.Field == "*" {
+			return bson.M{"$sum": 1}
+		}
+
+		// Only documents having a non-null value of field are counted
+		return bson.M{"$sum": bson.M{"$cond": []interface{}{bson.M{"$gt": []interface{}{field, nil}}, 1, 0}}}
+	}
+
+	panic(fmt.Errorf("%w: aggregate function %s", base.ErrUnsupportedType, aggregation.Func))
 }
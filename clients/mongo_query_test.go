@@ -1,10 +1,12 @@
 package clients
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/Kamva/octopus/base"
 	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/Kamva/octopus/term"
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
@@ -96,6 +98,179 @@ func TestMongoBuilder_Count(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, count)
 	})
+
+	t.Run("paginated", func(t *testing.T) {
+		query := new(MongoQuery)
+		rQuery := new(mgo.Query)
+		query.On("Limit", mock.Anything).Return(rQuery)
+		query.On("Skip", mock.Anything).Return(rQuery)
+		query.On("Count").Return(25, nil)
+
+		builder := initMongoBuilder(query)
+		builder.Limit(10).Skip(20)
+		count, err := builder.Count()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 25, count)
+
+		// Pagination is cleared for counting and restored afterward
+		limits := make([]interface{}, 0)
+		skips := make([]interface{}, 0)
+		for _, call := range query.Calls {
+			switch call.Method {
+			case "Limit":
+				limits = append(limits, call.Arguments.Get(0))
+			case "Skip":
+				skips = append(skips, call.Arguments.Get(0))
+			}
+		}
+		assert.Equal(t, []interface{}{10, 0, 10}, limits)
+		assert.Equal(t, []interface{}{20, 0, 20}, skips)
+	})
+}
+
+func TestMongoBuilder_CountDistinct(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		query := new(MongoQuery)
+		query.On("Distinct", "team", mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				values := args.Get(1).(*[]interface{})
+				*values = []interface{}{"Arsenal", "Chelsea", "Liverpool"}
+			})
+		count, err := initMongoBuilder(query).CountDistinct("team")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		query := new(MongoQuery)
+		query.On("Distinct", "team", mock.Anything).Return(errTest)
+		count, err := initMongoBuilder(query).CountDistinct("team")
+
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestMongoBuilder_Distinct(t *testing.T) {
+	query := new(MongoQuery)
+	query.On("Distinct", "team", mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			values := args.Get(1).(*[]interface{})
+			*values = []interface{}{"Arsenal", "Chelsea"}
+		})
+	values, err := initMongoBuilder(query).Distinct("team")
+
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Arsenal", "Chelsea"}, values)
+}
+
+func TestMongoBuilder_Aggregate(t *testing.T) {
+	original := runPipe
+	defer func() { runPipe = original }()
+
+	var pipeline []bson.M
+	runPipe = func(collection base.MongoCollection, p []bson.M, result interface{}) error {
+		pipeline = p
+		items := result.(*[]base.RecordMap)
+		*items = []base.RecordMap{{"team": "Chelsea", "sum_score": 120, "count": 4}}
+
+		return nil
+	}
+
+	client := &MongoDB{}
+	q := &mongoQuery{query: new(MongoQuery), queryMap: bson.M{"age": 19}, parser: client.parseConditions}
+	q.query.(*MongoQuery).On("Sort", "-sum_score").Return(new(mgo.Query))
+	q.query.(*MongoQuery).On("Limit", 5).Return(new(mgo.Query))
+
+	results, err := q.GroupBy("team").
+		Having(term.GreaterThan{Field: "count", Value: 2}).
+		OrderBy(base.Sort{Column: "sum_score", Descending: true}).
+		Limit(5).
+		Aggregate(
+			base.Aggregation{Func: base.SumFunc, Field: "score", Alias: "sum_score"},
+			base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"},
+		)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []bson.M{
+		{"$match": bson.M{"age": 19}},
+		{"$group": bson.M{
+			"_id":       bson.M{"team": "$team"},
+			"sum_score": bson.M{"$sum": "$score"},
+			"count":     bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{"_id": 0, "team": "$_id.team", "sum_score": 1, "count": 1}},
+		{"$match": bson.M{"count": bson.M{"$gt": 2}}},
+		{"$sort": bson.D{{Name: "sum_score", Value: -1}}},
+		{"$limit": 5},
+	}, pipeline)
+	assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+		[]string{"team", "sum_score", "count"},
+		base.RecordMap{"team": "Chelsea", "sum_score": 120, "count": 4},
+	)}, results)
+}
+
+func TestAggregationOperator(t *testing.T) {
+	assert.Equal(t, bson.M{"$avg": "$age"}, aggregationOperator(base.Aggregation{Func: base.AvgFunc, Field: "age"}))
+	assert.Equal(t, bson.M{"$min": "$age"}, aggregationOperator(base.Aggregation{Func: base.MinFunc, Field: "age"}))
+	assert.Equal(t, bson.M{"$max": "$age"}, aggregationOperator(base.Aggregation{Func: base.MaxFunc, Field: "age"}))
+	assert.Equal(
+		t,
+		bson.M{"$sum": bson.M{"$cond": []interface{}{bson.M{"$gt": []interface{}{"$age", nil}}, 1, 0}}},
+		aggregationOperator(base.Aggregation{Func: base.CountFunc, Field: "age"}),
+	)
+	assert.Panics(t, func() {
+		aggregationOperator(base.Aggregation{Func: "MEDIAN", Field: "age"})
+	})
+}
+
+func TestMongoBuilder_Select(t *testing.T) {
+	query := new(MongoQuery)
+	query.On("Select", bson.M{"name": 1, "age": 1}).Return(new(mgo.Query))
+	q := initMongoBuilder(query).Select("name", "age")
+
+	assert.IsType(t, new(mongoQuery), q)
+	query.AssertExpectations(t)
+}
+
+func TestMongoBuilder_Omit(t *testing.T) {
+	query := new(MongoQuery)
+	query.On("Select", bson.M{"stats": 0}).Return(new(mgo.Query))
+	q := initMongoBuilder(query).Omit("stats")
+
+	assert.IsType(t, new(mongoQuery), q)
+	query.AssertExpectations(t)
+}
+
+func TestMongoBuilder_Exists(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		query := new(MongoQuery)
+		query.On("One", nil).Return(nil)
+		exists, err := initMongoBuilder(query).Exists()
+
+		assert.Nil(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		query := new(MongoQuery)
+		query.On("One", nil).Return(mgo.ErrNotFound)
+		exists, err := initMongoBuilder(query).Exists()
+
+		assert.Nil(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		query := new(MongoQuery)
+		query.On("One", nil).Return(errTest)
+		exists, err := initMongoBuilder(query).Exists()
+
+		assert.NotNil(t, err)
+		assert.False(t, exists)
+	})
 }
 
 func TestMongoBuilder_First(t *testing.T) {
@@ -203,6 +378,22 @@ func TestMongoBuilder_Update(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, res)
 	})
+
+	t.Run("error", func(t *testing.T) {
+		query := new(MongoQuery)
+		collection := new(MongoCollection)
+		changes := *base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+		update := bson.M{"$set": bson.M{"name": "Test"}}
+		dupErr := &mgo.LastError{Code: 11000, Err: "duplicate key"}
+
+		// mgo returns no change info on errors
+		collection.On("UpdateAll", conditionsMap, update).Return(nil, dupErr)
+		res, err := initMongoBuilderWithCollection(query, collection).Update(changes)
+
+		assert.Equal(t, dupErr, err)
+		assert.Equal(t, 0, res)
+		assert.True(t, errors.Is(TranslateError(err), base.ErrDuplicateKey))
+	})
 }
 
 func TestMongoBuilder_Delete(t *testing.T) {
@@ -230,4 +421,16 @@ func TestMongoBuilder_Delete(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, res)
 	})
+
+	t.Run("error", func(t *testing.T) {
+		query := new(MongoQuery)
+		collection := new(MongoCollection)
+
+		// mgo returns no change info on errors
+		collection.On("RemoveAll", conditionsMap).Return(nil, errTest)
+		res, err := initMongoBuilderWithCollection(query, collection).Delete()
+
+		assert.Equal(t, errTest, err)
+		assert.Equal(t, 0, res)
+	})
 }
@@ -1,10 +1,14 @@
 package clients
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/Kamva/octopus/base"
 	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/Kamva/octopus/term"
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
@@ -43,6 +47,33 @@ var getQueryByIDMock = func(c *MongoDB, collection string, id interface{}, query
 	}
 }
 
+// documentCollection returns a collection mock holding `document`, which
+// applies updates of the document as MongoDB does. Updates with operators
+// change the given fields, and others replace the whole document.
+func documentCollection(id bson.ObjectId, document bson.M) *MongoCollection {
+	collection := new(MongoCollection)
+	collection.On("UpdateId", id, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		update := args.Get(1).(bson.M)
+		if set, ok := update["$set"].(bson.M); ok {
+			for key, value := range set {
+				document[key] = value
+			}
+			return
+		}
+
+		for key := range document {
+			if key != "_id" {
+				delete(document, key)
+			}
+		}
+		for key, value := range update {
+			document[key] = value
+		}
+	})
+
+	return collection
+}
+
 func initMongo(session base.MongoSession, collection base.MongoCollection) *MongoDB {
 	return &MongoDB{session: session, dbName: "test", collection: collection}
 }
@@ -62,6 +93,7 @@ func TestNewMongoDB(t *testing.T) {
 		mongo := client.(*MongoDB)
 
 		assert.Equal(t, "test", mongo.dbName)
+		assert.Equal(t, "localhost:27017", mongo.url)
 		assert.NotNil(t, mongo.session)
 	})
 
@@ -166,7 +198,7 @@ func TestMongoDB_Insert(t *testing.T) {
 
 		client := initMongo(session, collection)
 
-		err := client.Insert("users", data)
+		err := client.Insert("users", base.Key{"_id"}, data)
 
 		assert.Nil(t, err)
 
@@ -185,7 +217,7 @@ func TestMongoDB_Insert(t *testing.T) {
 
 		client := initMongo(session, collection)
 
-		err := client.Insert("users", data)
+		err := client.Insert("users", base.Key{"_id"}, data)
 
 		assert.NotNil(t, err)
 	})
@@ -212,7 +244,7 @@ func TestMongoDB_FindByID(t *testing.T) {
 		client := initMongo(session, collection)
 		queryByID = getQueryByIDMock(client, "users", id, query)
 
-		res, err := client.FindByID("users", id)
+		res, err := client.FindByID("users", base.Key{"_id"}, id)
 
 		assert.Nil(t, err)
 		assert.IsType(t, base.RecordData{}, res)
@@ -236,7 +268,7 @@ func TestMongoDB_FindByID(t *testing.T) {
 		client := initMongo(session, collection)
 		queryByID = getQueryByIDMock(client, "users", id, query)
 
-		res, err := client.FindByID("users", id)
+		res, err := client.FindByID("users", base.Key{"_id"}, id)
 
 		assert.NotNil(t, err)
 		assert.IsType(t, base.RecordData{}, res)
@@ -245,6 +277,15 @@ func TestMongoDB_FindByID(t *testing.T) {
 		assert.Nil(t, res.Get("age"))
 		assert.Nil(t, res.Get("status"))
 	})
+
+	t.Run("invalidID", func(t *testing.T) {
+		client := initMongo(new(MongoSession), new(MongoCollection))
+
+		res, err := client.FindByID("users", base.Key{"_id"}, "invalid")
+
+		assert.True(t, errors.Is(err, base.ErrInvalidID))
+		assert.Equal(t, 0, res.Length())
+	})
 }
 
 func TestMongoDB_UpdateByID(t *testing.T) {
@@ -257,10 +298,10 @@ func TestMongoDB_UpdateByID(t *testing.T) {
 		id := bson.NewObjectId()
 		session := new(MongoSession)
 		collection := new(MongoCollection)
-		collection.On("UpdateId", id, data.GetMap()).Return(nil)
+		collection.On("UpdateId", id, bson.M{"$set": bson.M(*data.GetMap())}).Return(nil)
 
 		client := initMongo(session, collection)
-		err := client.UpdateByID("users", id, *data)
+		err := client.UpdateByID("users", base.Key{"_id"}, id, *data)
 
 		assert.Nil(t, err)
 	})
@@ -274,13 +315,50 @@ func TestMongoDB_UpdateByID(t *testing.T) {
 		id := bson.NewObjectId()
 		session := new(MongoSession)
 		collection := new(MongoCollection)
-		collection.On("UpdateId", id, data.GetMap()).Return(errTest)
+		collection.On("UpdateId", id, bson.M{"$set": bson.M(*data.GetMap())}).Return(errTest)
 
 		client := initMongo(session, collection)
-		err := client.UpdateByID("users", id, *data)
+		err := client.UpdateByID("users", base.Key{"_id"}, id, *data)
 
 		assert.NotNil(t, err)
 	})
+
+	t.Run("softDelete", func(t *testing.T) {
+		id := bson.NewObjectId()
+		createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		document := bson.M{"_id": id, "name": "Test", "created_at": createdAt, "deleted_at": nil}
+		client := initMongo(new(MongoSession), documentCollection(id, document))
+
+		// Soft delete and restore set only the deletion time
+		deletedAt := createdAt.Add(time.Hour)
+		err := client.UpdateByID("users", base.Key{"_id"}, id, *base.NewRecordData(
+			[]string{"deleted_at"}, base.RecordMap{"deleted_at": &deletedAt},
+		))
+		assert.Nil(t, err)
+		assert.Equal(t, bson.M{"_id": id, "name": "Test", "created_at": createdAt, "deleted_at": &deletedAt}, document)
+
+		err = client.UpdateByID("users", base.Key{"_id"}, id, *base.NewRecordData(
+			[]string{"deleted_at"}, base.RecordMap{"deleted_at": nil},
+		))
+		assert.Nil(t, err)
+		assert.Equal(t, bson.M{"_id": id, "name": "Test", "created_at": createdAt, "deleted_at": nil}, document)
+	})
+
+	t.Run("keepsCreatedAt", func(t *testing.T) {
+		id := bson.NewObjectId()
+		createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		document := bson.M{"_id": id, "title": "Test", "created_at": createdAt, "updated_at": createdAt}
+		client := initMongo(new(MongoSession), documentCollection(id, document))
+
+		// Data of updates has no creation time
+		updatedAt := createdAt.Add(time.Hour)
+		err := client.UpdateByID("posts", base.Key{"_id"}, id, *base.NewRecordData(
+			[]string{"title", "updated_at"}, base.RecordMap{"title": "Updated", "updated_at": updatedAt},
+		))
+
+		assert.Nil(t, err)
+		assert.Equal(t, bson.M{"_id": id, "title": "Updated", "created_at": createdAt, "updated_at": updatedAt}, document)
+	})
 }
 
 func TestMongoDB_DeleteByID(t *testing.T) {
@@ -291,7 +369,7 @@ func TestMongoDB_DeleteByID(t *testing.T) {
 		collection.On("RemoveId", id).Return(nil)
 
 		client := initMongo(session, collection)
-		err := client.DeleteByID("users", id)
+		err := client.DeleteByID("users", base.Key{"_id"}, id)
 
 		assert.Nil(t, err)
 	})
@@ -303,7 +381,7 @@ func TestMongoDB_DeleteByID(t *testing.T) {
 		collection.On("RemoveId", id).Return(errTest)
 
 		client := initMongo(session, collection)
-		err := client.DeleteByID("users", id)
+		err := client.DeleteByID("users", base.Key{"_id"}, id)
 
 		assert.NotNil(t, err)
 	})
@@ -323,6 +401,245 @@ func TestMongoDB_Query(t *testing.T) {
 	assert.IsType(t, (*mongoQuery)(nil), q)
 }
 
+func TestMongoDB_ConfigurePool(t *testing.T) {
+	session := new(MongoSession)
+	session.On("SetPoolLimit", 20).Return()
+
+	client := initMongo(session, new(MongoCollection))
+	client.ConfigurePool(base.PoolConfig{PoolLimit: 20, MaxOpenConns: 10})
+
+	session.AssertExpectations(t)
+}
+
+func TestMongoDB_Acquire(t *testing.T) {
+	original := copySession
+	defer func() { copySession = original }()
+
+	session := new(MongoSession)
+	sessionCopy := new(MongoSession)
+	copySession = func(s base.MongoSession) base.MongoSession {
+		assert.Equal(t, session, s)
+		return sessionCopy
+	}
+
+	client := initMongo(session, new(MongoCollection))
+	client.url = "localhost:27017"
+	acquired := client.Acquire().(*MongoDB)
+
+	assert.Equal(t, sessionCopy, acquired.session)
+	assert.Equal(t, "test", acquired.dbName)
+	assert.Equal(t, "localhost:27017", acquired.url)
+	assert.Nil(t, acquired.collection)
+}
+
+func TestMongoDB_WithContext(t *testing.T) {
+	original := copySession
+	defer func() { copySession = original }()
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		session := new(MongoSession)
+		sessionCopy := new(MongoSession)
+		sessionCopy.On("SetSocketTimeout", mock.AnythingOfType("time.Duration")).
+			Return().
+			Run(func(args mock.Arguments) {
+				timeout := args.Get(0).(time.Duration)
+				assert.True(t, timeout > 0 && timeout <= time.Minute)
+			})
+		copySession = func(s base.MongoSession) base.MongoSession {
+			return sessionCopy
+		}
+
+		client := initMongo(session, new(MongoCollection))
+		client.url = "localhost:27017"
+		bound := client.WithContext(ctx).(*MongoDB)
+
+		assert.Equal(t, sessionCopy, bound.session)
+		assert.Equal(t, "test", bound.dbName)
+		assert.Equal(t, "localhost:27017", bound.url)
+		assert.Equal(t, ctx, bound.ctx)
+		sessionCopy.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		sessionCopy := new(MongoSession)
+		sessionCopy.On("SetSocketTimeout", time.Nanosecond).Return()
+		copySession = func(s base.MongoSession) base.MongoSession {
+			return sessionCopy
+		}
+
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		_ = client.WithContext(ctx)
+
+		sessionCopy.AssertExpectations(t)
+	})
+
+	t.Run("noDeadline", func(t *testing.T) {
+		sessionCopy := new(MongoSession)
+		copySession = func(s base.MongoSession) base.MongoSession {
+			return sessionCopy
+		}
+
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		_ = client.WithContext(context.Background())
+
+		sessionCopy.AssertNotCalled(t, "SetSocketTimeout", mock.Anything)
+	})
+}
+
+func TestMongoDB_Raw(t *testing.T) {
+	t.Run("filter", func(t *testing.T) {
+		original := queryMongoDB
+		defer func() { queryMongoDB = original }()
+
+		filter := bson.M{"age": bson.M{"$mod": []int{2, 0}}}
+		var queried bson.M
+		queryMongoDB = func(c *MongoDB, collection string, conditions bson.M) base.MongoQuery {
+			queried = conditions
+			return new(MongoQuery)
+		}
+
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		q := client.Raw("players", filter)
+
+		assert.Equal(t, filter, queried)
+		assert.IsType(t, (*mongoQuery)(nil), q)
+	})
+
+	t.Run("invalidQuery", func(t *testing.T) {
+		client := initMongo(new(MongoSession), new(MongoCollection))
+
+		assert.Panics(t, func() {
+			client.Raw("players", "db.players.find()")
+		})
+	})
+}
+
+func TestMongoDB_Exec(t *testing.T) {
+	original := runCommand
+	defer func() { runCommand = original }()
+
+	t.Run("success", func(t *testing.T) {
+		command := bson.D{{Name: "delete", Value: "players"}}
+		runCommand = func(c *MongoDB, cmd interface{}, result interface{}) error {
+			assert.Equal(t, command, cmd)
+			*result.(*bson.M) = bson.M{"n": 3, "ok": 1}
+
+			return nil
+		}
+
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		n, err := client.Exec(command)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		runCommand = func(c *MongoDB, cmd interface{}, result interface{}) error {
+			return errTest
+		}
+
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		n, err := client.Exec("ping")
+
+		assert.Equal(t, errTest, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func TestMongoDB_Aggregate(t *testing.T) {
+	original := runPipe
+	defer func() { runPipe = original }()
+
+	t.Run("success", func(t *testing.T) {
+		collection := new(MongoCollection)
+		var pipeline []bson.M
+		runPipe = func(c base.MongoCollection, p []bson.M, result interface{}) error {
+			assert.Equal(t, collection, c)
+			pipeline = p
+			items := result.(*[]base.RecordMap)
+			*items = []base.RecordMap{{"team": "Chelsea", "total": 120}, {"team": "Arsenal", "total": 90}}
+
+			return nil
+		}
+
+		client := initMongo(new(MongoSession), collection)
+		results, err := client.Aggregate("players", NewPipeline().Match(term.Equal{Field: "age", Value: 19}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []bson.M{{"$match": bson.M{"age": 19}}}, pipeline)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "Chelsea", results[0].Get("team"))
+		assert.Equal(t, 90, results[1].Get("total"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		runPipe = func(c base.MongoCollection, p []bson.M, result interface{}) error {
+			return errTest
+		}
+
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		results, err := client.Aggregate("players", NewPipeline())
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, results)
+	})
+}
+
+func TestMongoDB_Begin(t *testing.T) {
+	original := startMongoTx
+	defer func() { startMongoTx = original }()
+
+	t.Run("success", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		session := new(MongoTxSession)
+		startMongoTx = func(c context.Context, url string, dbName string) (base.MongoTxSession, error) {
+			assert.Equal(t, ctx, c)
+			assert.Equal(t, "mongodb://localhost:27017/test", url)
+			assert.Equal(t, "test", dbName)
+
+			return session, nil
+		}
+
+		client := &MongoDB{dbName: "test", url: "mongodb://localhost:27017/test", ctx: ctx}
+		txClient, err := client.Begin()
+
+		assert.Nil(t, err)
+		assert.Equal(t, session, txClient.(*MongoTx).session)
+	})
+
+	t.Run("background", func(t *testing.T) {
+		startMongoTx = func(c context.Context, url string, dbName string) (base.MongoTxSession, error) {
+			assert.Equal(t, context.Background(), c)
+
+			return new(MongoTxSession), nil
+		}
+
+		_, err := (&MongoDB{dbName: "test"}).Begin()
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		startMongoTx = func(c context.Context, url string, dbName string) (base.MongoTxSession, error) {
+			return nil, errTest
+		}
+
+		txClient, err := (&MongoDB{dbName: "test"}).Begin()
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, txClient)
+	})
+}
+
 func TestMongoDB_Close(t *testing.T) {
 	session := new(MongoSession)
 	collection := new(MongoCollection)
@@ -338,34 +655,121 @@ func TestMongoDB_Close(t *testing.T) {
 	assert.Equal(t, "", client.dbName)
 }
 
-func TestMongoDB_convertID(t *testing.T) {
+func TestConvertObjectID(t *testing.T) {
 	t.Run("objectId", func(t *testing.T) {
-		session := new(MongoSession)
-		client := initMongo(session, new(MongoCollection))
-
 		id := bson.NewObjectId()
-		ret := client.convertID(id)
+		ret, err := convertObjectID(id)
 
+		assert.Nil(t, err)
 		assert.Equal(t, id, ret)
 	})
 
 	t.Run("string", func(t *testing.T) {
-		session := new(MongoSession)
-		client := initMongo(session, new(MongoCollection))
-
 		id := bson.NewObjectId().Hex()
-		ret := client.convertID(id)
+		ret, err := convertObjectID(id)
 
+		assert.Nil(t, err)
 		assert.Equal(t, id, ret.Hex())
 		assert.Equal(t, bson.ObjectIdHex(id), ret)
 	})
 
 	t.Run("invalid", func(t *testing.T) {
-		session := new(MongoSession)
-		client := initMongo(session, new(MongoCollection))
+		for _, id := range []interface{}{10, "invalid", bson.ObjectId("short")} {
+			_, err := convertObjectID(id)
 
-		assert.Panics(t, func() {
-			client.convertID(10)
-		})
+			assert.True(t, errors.Is(err, base.ErrInvalidID))
+		}
+	})
+}
+
+func TestMongoDB_parseConditions(t *testing.T) {
+	t.Run("groups", func(t *testing.T) {
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		queryMap := client.parseConditions(
+			term.Or{
+				term.Equal{Field: "status", Value: "active"},
+				term.And{
+					term.Equal{Field: "owner", Value: 10},
+					term.Not{Condition: term.In{Field: "team", Values: []interface{}{"A", "B"}}},
+				},
+			},
+			term.NotNull{Field: "trophies"},
+		)
+
+		assert.Equal(t, bson.M{
+			"$or": []bson.M{
+				{"status": "active"},
+				{"$and": []bson.M{
+					{"owner": 10},
+					{"$nor": []bson.M{{"team": bson.M{"$in": []interface{}{"A", "B"}}}}},
+				}},
+			},
+			"trophies": bson.M{"$ne": nil},
+		}, queryMap)
+	})
+
+	t.Run("duplicateKeys", func(t *testing.T) {
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		queryMap := client.parseConditions(
+			term.GreaterThan{Field: "age", Value: 18},
+			term.LessThan{Field: "age", Value: 30},
+			term.Or{term.Equal{Field: "a", Value: 1}},
+			term.Or{term.Equal{Field: "b", Value: 2}},
+		)
+
+		assert.Equal(t, bson.M{
+			"age": bson.M{"$gt": 18},
+			"$or": []bson.M{{"a": 1}},
+			"$and": []bson.M{
+				{"age": bson.M{"$lt": 30}},
+				{"$or": []bson.M{{"b": 2}}},
+			},
+		}, queryMap)
+	})
+	t.Run("patterns", func(t *testing.T) {
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		queryMap := client.parseConditions(
+			term.Like{Field: "name", Pattern: "J_n%"},
+			term.ILike{Field: "team", Pattern: "man%"},
+			term.Regex{Field: "code", Pattern: "^[A-Z]{3}$"},
+			term.StartsWith{Field: "nickname", Value: "a.b"},
+			term.EndsWith{Field: "email", Value: "@example.com", IgnoreCase: true},
+			term.Contains{Field: "bio", Value: "(wow)"},
+		)
+
+		assert.Equal(t, bson.M{
+			"name":     bson.RegEx{Pattern: "^J.n.*$"},
+			"team":     bson.RegEx{Pattern: "^man.*$", Options: "i"},
+			"code":     bson.RegEx{Pattern: "^[A-Z]{3}$"},
+			"nickname": bson.RegEx{Pattern: `^a\.b`},
+			"email":    bson.RegEx{Pattern: `@example\.com$`, Options: "i"},
+			"bio":      bson.RegEx{Pattern: `\(wow\)`},
+		}, queryMap)
+	})
+	t.Run("rangesAndSets", func(t *testing.T) {
+		client := initMongo(new(MongoSession), new(MongoCollection))
+		queryMap := client.parseConditions(
+			term.Between{Field: "age", From: 18, To: 30},
+			term.NotIn{Field: "team", Values: []interface{}{"A", "B"}},
+			term.Exists{Field: "email"},
+			term.ArrayContains{Field: "tags", Values: []interface{}{"go", "sql"}},
+			term.ArrayOverlaps{Field: "scores", Values: []interface{}{1, 2}},
+			term.ElemMatch{Field: "items", Conditions: []base.Condition{
+				term.Equal{Field: "name", Value: "ball"},
+				term.GreaterThan{Field: "count", Value: 2},
+			}},
+		)
+
+		assert.Equal(t, bson.M{
+			"age":    bson.M{"$gte": 18, "$lte": 30},
+			"team":   bson.M{"$nin": []interface{}{"A", "B"}},
+			"email":  bson.M{"$exists": true},
+			"tags":   bson.M{"$all": []interface{}{"go", "sql"}},
+			"scores": bson.M{"$in": []interface{}{1, 2}},
+			"items": bson.M{"$elemMatch": bson.M{
+				"name":  "ball",
+				"count": bson.M{"$gt": 2},
+			}},
+		}, queryMap)
 	})
 }
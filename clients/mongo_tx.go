@@ -0,0 +1,435 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	driverbson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTx is a MongoDB client bound to a transaction. Since mgo does not
+// support transactions, the transaction runs in a session of the official
+// MongoDB driver, and documents are converted to and from bson values of
+// mgo, so they are the same as documents of MongoDB client.
+type MongoTx struct {
+	session base.MongoTxSession
+}
+
+// CreateTable returns an error, as collections could not be created in
+// transactions of MongoDB prior to 4.4.
+func (c *MongoTx) CreateTable(collectionName string, info base.TableInfo) error {
+	return fmt.Errorf("%w: collections could not be created in mongodb transactions", base.ErrInvalidTx)
+}
+
+// EnsureIndex returns an error, as indexes could not be created in
+// transactions of MongoDB prior to 4.4.
+func (c *MongoTx) EnsureIndex(collectionName string, index base.Index) error {
+	return fmt.Errorf("%w: indexes could not be created in mongodb transactions", base.ErrInvalidTx)
+}
+
+// Insert tries to insert `data` into `collectionName` in the transaction.
+// MongoDB documents are always identified by `_id`, so `key` is ignored.
+func (c *MongoTx) Insert(collectionName string, key base.Key, data *base.RecordData) error {
+	data.Set("_id", bson.NewObjectId())
+
+	return c.session.Insert(collectionName, data.GetMap())
+}
+
+// FindByID searches through `collectionName` documents to find a doc that its
+// `_id` match with `id` and returns it alongside any possible error.
+func (c *MongoTx) FindByID(collectionName string, key base.Key, id interface{}) (base.RecordData, error) {
+	data := base.ZeroRecordData()
+
+	objectID, err := convertObjectID(id)
+	if err != nil {
+		return *data, err
+	}
+
+	docs, err := c.session.Find(collectionName, bson.M{"_id": objectID}, base.MongoFindOptions{Limit: 1})
+	if err != nil {
+		return *data, err
+	}
+
+	if len(docs) == 0 {
+		return *data, mgo.ErrNotFound
+	}
+
+	for key, value := range docs[0] {
+		data.Set(key, value)
+	}
+
+	return *data, nil
+}
+
+// UpdateByID finds a document in `collectionName` that its `_id` match with
+// `id`, and updates it with data. Only fields in `data` are set, and other
+// fields of document are kept.
+func (c *MongoTx) UpdateByID(collectionName string, key base.Key, id interface{}, data base.RecordData) error {
+	set := bson.M{}
+	for column, value := range *data.GetMap() {
+		set[column] = value
+	}
+
+	n, err := c.session.Update(collectionName, bson.M{"_id": id}, bson.M{"$set": set})
+	if err == nil && n == 0 {
+		return mgo.ErrNotFound
+	}
+
+	return err
+}
+
+// DeleteByID finds a document in `collectionName` that its `_id` match with
+// `id`, and remove it entirely. It will return error if anything went wrong.
+func (c *MongoTx) DeleteByID(collectionName string, key base.Key, id interface{}) error {
+	n, err := c.session.Delete(collectionName, bson.M{"_id": id})
+	if err == nil && n == 0 {
+		return mgo.ErrNotFound
+	}
+
+	return err
+}
+
+// Query generates and returns query object for further operations
+func (c *MongoTx) Query(collectionName string, conditions ...base.Condition) base.QueryBuilder {
+	parser := new(MongoDB).parseConditions
+
+	return newMongoTxQuery(c.session, collectionName, parser(conditions...), parser)
+}
+
+// Raw returns a query fetching documents of `collectionName` matching raw
+// `query` filter, which should be a `bson.M`. Mongo filters have no
+// arguments, so `args` are ignored.
+func (c *MongoTx) Raw(collectionName string, query interface{}, args ...interface{}) base.RawQuery {
+	filter, ok := query.(bson.M)
+	if !ok {
+		panic(fmt.Errorf("%w: raw mongodb query should be a bson.M filter, got %T", base.ErrUnsupportedType, query))
+	}
+
+	return newMongoTxQuery(c.session, collectionName, filter, new(MongoDB).parseConditions)
+}
+
+// Exec runs database `command` in the transaction, and returns the number
+// of affected documents reported by the command. Mongo commands have no
+// arguments, so `args` are ignored.
+func (c *MongoTx) Exec(command interface{}, args ...interface{}) (int, error) {
+	result, err := c.session.Run(command)
+	if err != nil {
+		return 0, err
+	}
+
+	n, _ := toInt64(result["n"])
+
+	return int(n), nil
+}
+
+// Aggregate runs aggregation `pipeline` on `collectionName` in the
+// transaction and returns the documents resulted from the last stage.
+func (c *MongoTx) Aggregate(collectionName string, pipeline *Pipeline) (base.RecordDataSet, error) {
+	items, err := c.session.Aggregate(collectionName, pipeline.Stages())
+	if err != nil {
+		return nil, err
+	}
+
+	resultSet := make(base.RecordDataSet, 0, len(items))
+	for _, item := range items {
+		data := base.ZeroRecordData()
+		for key, value := range item {
+			data.Set(key, value)
+		}
+
+		resultSet = append(resultSet, *data)
+	}
+
+	return resultSet, nil
+}
+
+// Commit commits the transaction and ends its session
+func (c *MongoTx) Commit() error {
+	if c.session == nil {
+		return errNoTransaction
+	}
+
+	err := c.session.Commit()
+	c.session = nil
+
+	return err
+}
+
+// Rollback aborts the transaction and ends its session
+func (c *MongoTx) Rollback() error {
+	if c.session == nil {
+		return errNoTransaction
+	}
+
+	err := c.session.Abort()
+	c.session = nil
+
+	return err
+}
+
+// Close does nothing, as the session of transaction is ended on commit
+// or rollback of the transaction.
+func (c *MongoTx) Close() {}
+
+// mongoTxSession is a session of the official MongoDB driver which runs a
+// transaction on `db`. Its filters and documents are encoded by mgo, and
+// documents of results are decoded by mgo, so both drivers are compatible
+// on wire format of bson.
+type mongoTxSession struct {
+	client  *mongo.Client
+	session mongo.Session
+	ctx     mongo.SessionContext
+	db      *mongo.Database
+}
+
+// Find returns documents of `collection` matching `filter`
+func (s *mongoTxSession) Find(collection string, filter bson.M, opts base.MongoFindOptions) ([]bson.M, error) {
+	rawFilter, err := toRaw(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find()
+	if len(opts.Sort) > 0 {
+		sort, err := toRaw(opts.Sort)
+		if err != nil {
+			return nil, err
+		}
+		findOptions.SetSort(sort)
+	}
+
+	if len(opts.Projection) > 0 {
+		projection, err := toRaw(opts.Projection)
+		if err != nil {
+			return nil, err
+		}
+		findOptions.SetProjection(projection)
+	}
+
+	if opts.Skip > 0 {
+		findOptions.SetSkip(int64(opts.Skip))
+	}
+
+	if opts.Limit > 0 {
+		findOptions.SetLimit(int64(opts.Limit))
+	}
+
+	cursor, err := s.db.Collection(collection).Find(s.ctx, rawFilter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCursor(s.ctx, cursor)
+}
+
+// Count returns number of documents of `collection` matching `filter`
+func (s *mongoTxSession) Count(collection string, filter bson.M) (int, error) {
+	rawFilter, err := toRaw(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.db.Collection(collection).CountDocuments(s.ctx, rawFilter)
+
+	return int(n), err
+}
+
+// Distinct returns distinct values of `field` in documents of `collection`
+// matching `filter`.
+func (s *mongoTxSession) Distinct(collection string, field string, filter bson.M) ([]interface{}, error) {
+	rawFilter, err := toRaw(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := s.db.Collection(collection).Distinct(s.ctx, field, rawFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Values are decoded by the official driver, so they are encoded in a
+	// document to be decoded by mgo.
+	raw, err := driverbson.Marshal(driverbson.M{"values": values})
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := fromRaw(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _ := doc["values"].([]interface{})
+
+	return result, nil
+}
+
+// Aggregate runs aggregation `pipeline` on `collection` and returns the
+// documents resulted from the last stage of pipeline.
+func (s *mongoTxSession) Aggregate(collection string, pipeline []bson.M) ([]bson.M, error) {
+	stages := make(driverbson.A, 0, len(pipeline))
+	for _, stage := range pipeline {
+		raw, err := toRaw(stage)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, raw)
+	}
+
+	cursor, err := s.db.Collection(collection).Aggregate(s.ctx, stages)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeCursor(s.ctx, cursor)
+}
+
+// Insert inserts `document` into `collection`
+func (s *mongoTxSession) Insert(collection string, document interface{}) error {
+	raw, err := toRaw(document)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Collection(collection).InsertOne(s.ctx, raw)
+
+	return err
+}
+
+// Update updates documents of `collection` matching `filter` by `update`
+// and returns number of matched documents.
+func (s *mongoTxSession) Update(collection string, filter bson.M, update bson.M) (int, error) {
+	rawFilter, err := toRaw(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	rawUpdate, err := toRaw(update)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Collection(collection).UpdateMany(s.ctx, rawFilter, rawUpdate)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.MatchedCount), nil
+}
+
+// Delete removes documents of `collection` matching `filter` and returns
+// number of removed documents.
+func (s *mongoTxSession) Delete(collection string, filter bson.M) (int, error) {
+	rawFilter, err := toRaw(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Collection(collection).DeleteMany(s.ctx, rawFilter)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+// Run runs database `command` and returns its result document
+func (s *mongoTxSession) Run(command interface{}) (bson.M, error) {
+	raw, err := toRaw(command)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.db.RunCommand(s.ctx, raw).Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	return fromRaw(result)
+}
+
+// Commit commits the transaction and ends the session
+func (s *mongoTxSession) Commit() error {
+	defer s.end()
+
+	return s.session.CommitTransaction(s.ctx)
+}
+
+// Abort aborts the transaction and ends the session
+func (s *mongoTxSession) Abort() error {
+	defer s.end()
+
+	return s.session.AbortTransaction(s.ctx)
+}
+
+// end ends the session and disconnects its client
+func (s *mongoTxSession) end() {
+	s.session.EndSession(s.ctx)
+	_ = s.client.Disconnect(s.ctx)
+}
+
+// toRaw encodes `value` document by mgo to raw bson of the official driver
+func toRaw(value interface{}) (driverbson.Raw, error) {
+	data, err := bson.Marshal(value)
+
+	return driverbson.Raw(data), err
+}
+
+// fromRaw decodes `raw` bson document of the official driver by mgo
+func fromRaw(raw driverbson.Raw) (bson.M, error) {
+	doc := make(bson.M)
+	err := bson.Unmarshal(raw, &doc)
+
+	return doc, err
+}
+
+// decodeCursor decodes all documents of `cursor` by mgo and closes it
+func decodeCursor(ctx context.Context, cursor *mongo.Cursor) ([]bson.M, error) {
+	defer cursor.Close(ctx)
+
+	docs := make([]bson.M, 0)
+	for cursor.Next(ctx) {
+		doc, err := fromRaw(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return docs, cursor.Err()
+}
+
+// startMongoTx connects to MongoDB of `url` by the official driver, and
+// starts a transaction on `dbName` database in a new session. The client
+// is disconnected when the transaction is committed or aborted.
+var startMongoTx = func(ctx context.Context, url string, dbName string) (base.MongoTxSession, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(url))
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := client.StartSession()
+	if err != nil {
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+
+	if err = session.StartTransaction(); err != nil {
+		session.EndSession(ctx)
+		_ = client.Disconnect(ctx)
+		return nil, err
+	}
+
+	return &mongoTxSession{
+		client:  client,
+		session: session,
+		ctx:     mongo.NewSessionContext(ctx, session),
+		db:      client.Database(dbName),
+	}, nil
+}
@@ -0,0 +1,219 @@
+package clients
+
+import (
+	"github.com/Kamva/octopus/base"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+)
+
+// mongoTxQuery is a query of MongoDB documents which runs in a transaction
+type mongoTxQuery struct {
+	session    base.MongoTxSession
+	collection string
+	queryMap   bson.M
+	parser     func(conditions ...base.Condition) bson.M
+
+	sorts      []base.Sort
+	limit      int
+	skip       int
+	projection bson.M
+	groups     []string
+	having     []base.Condition
+}
+
+// OrderBy set the order of returning result in following command
+func (q *mongoTxQuery) OrderBy(sorts ...base.Sort) base.QueryBuilder {
+	q.sorts = sorts
+
+	return q
+}
+
+// Limit set the limit that determines how many results should be
+// returned in the following fetch command.
+func (q *mongoTxQuery) Limit(n int) base.QueryBuilder {
+	q.limit = n
+
+	return q
+}
+
+// Skip set the starting offset of the following fetch command
+func (q *mongoTxQuery) Skip(n int) base.QueryBuilder {
+	q.skip = n
+
+	return q
+}
+
+// Select limits fields of documents fetched by the following fetch command
+// to `fields`. Note that `_id` is always fetched.
+func (q *mongoTxQuery) Select(fields ...string) base.QueryBuilder {
+	q.projection = projection(fields, 1)
+
+	return q
+}
+
+// Omit excludes `fields` from fields of documents fetched by the following
+// fetch command.
+func (q *mongoTxQuery) Omit(fields ...string) base.QueryBuilder {
+	q.projection = projection(fields, 0)
+
+	return q
+}
+
+// GroupBy groups documents by values of `fields` in the following aggregate
+// command.
+func (q *mongoTxQuery) GroupBy(fields ...string) base.QueryBuilder {
+	q.groups = fields
+
+	return q
+}
+
+// Having filters groups of the following aggregate command by `conditions`,
+// which fields could be aliases of aggregations.
+func (q *mongoTxQuery) Having(conditions ...base.Condition) base.QueryBuilder {
+	q.having = conditions
+
+	return q
+}
+
+// Count returns the number of documents matching the query conditions.
+// Limit and skip of query are not applied, the same as MongoDB client.
+func (q *mongoTxQuery) Count() (int, error) {
+	return q.session.Count(q.collection, q.queryMap)
+}
+
+// CountDistinct returns the number of distinct values of `field` in
+// documents matching the query conditions.
+func (q *mongoTxQuery) CountDistinct(field string) (int, error) {
+	values, err := q.session.Distinct(q.collection, field, q.queryMap)
+
+	return len(values), err
+}
+
+// Distinct returns distinct values of `field` in documents matching the
+// query conditions.
+func (q *mongoTxQuery) Distinct(field string) ([]interface{}, error) {
+	return q.session.Distinct(q.collection, field, q.queryMap)
+}
+
+// Aggregate returns a row of grouping fields and `aggregations` for each
+// group of documents matching the query conditions, the same as MongoDB
+// client.
+func (q *mongoTxQuery) Aggregate(aggregations ...base.Aggregation) (base.RecordDataSet, error) {
+	pipeline := (&mongoQuery{
+		queryMap: q.queryMap,
+		parser:   q.parser,
+		sorts:    q.sorts,
+		limit:    q.limit,
+		skip:     q.skip,
+		groups:   q.groups,
+		having:   q.having,
+	}).groupPipeline(aggregations)
+
+	items, err := q.session.Aggregate(q.collection, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	resultSet := make(base.RecordDataSet, 0, len(items))
+	for _, item := range items {
+		data := base.ZeroRecordData()
+		for _, field := range q.groups {
+			data.Set(field, item[field])
+		}
+		for _, aggregation := range aggregations {
+			data.Set(aggregation.Alias, item[aggregation.Alias])
+		}
+
+		resultSet = append(resultSet, *data)
+	}
+
+	return resultSet, nil
+}
+
+// Exists checks whether any document matches with the query conditions
+func (q *mongoTxQuery) Exists() (bool, error) {
+	docs, err := q.session.Find(q.collection, q.queryMap, base.MongoFindOptions{Limit: 1})
+
+	return len(docs) > 0, err
+}
+
+// First fetch data of the first record that match with query conditions.
+func (q *mongoTxQuery) First() (base.RecordData, error) {
+	data := base.ZeroRecordData()
+
+	opts := q.findOptions()
+	opts.Limit = 1
+
+	docs, err := q.session.Find(q.collection, q.queryMap, opts)
+	if err != nil {
+		return *data, err
+	}
+
+	if len(docs) == 0 {
+		return *data, mgo.ErrNotFound
+	}
+
+	for key, value := range docs[0] {
+		data.Set(key, value)
+	}
+
+	return *data, nil
+}
+
+// All returns results that match with query conditions in RecordDataSet
+// format.
+func (q *mongoTxQuery) All() (base.RecordDataSet, error) {
+	resultSet := make(base.RecordDataSet, 0)
+
+	docs, err := q.session.Find(q.collection, q.queryMap, q.findOptions())
+	if err != nil {
+		return resultSet, err
+	}
+
+	for _, doc := range docs {
+		data := base.ZeroRecordData()
+		for key, value := range doc {
+			data.Set(key, value)
+		}
+
+		resultSet = append(resultSet, *data)
+	}
+
+	return resultSet, nil
+}
+
+// Update updates documents matching the query conditions with `data` and
+// returns number of affected documents.
+func (q *mongoTxQuery) Update(data base.RecordData) (int, error) {
+	set := bson.M{}
+	for column, value := range *data.GetMap() {
+		set[column] = value
+	}
+
+	return q.session.Update(q.collection, q.queryMap, bson.M{"$set": set})
+}
+
+// Delete removes documents matching the query conditions and returns
+// number of removed documents.
+func (q *mongoTxQuery) Delete() (int, error) {
+	return q.session.Delete(q.collection, q.queryMap)
+}
+
+// findOptions returns options of finding documents by the query
+func (q *mongoTxQuery) findOptions() base.MongoFindOptions {
+	opts := base.MongoFindOptions{Skip: q.skip, Limit: q.limit, Projection: q.projection}
+	if len(q.sorts) > 0 {
+		opts.Sort = sortDocument(q.sorts)
+	}
+
+	return opts
+}
+
+func newMongoTxQuery(
+	session base.MongoTxSession,
+	collection string,
+	queryMap bson.M,
+	parser func(conditions ...base.Condition) bson.M,
+) *mongoTxQuery {
+	return &mongoTxQuery{session: session, collection: collection, queryMap: queryMap, parser: parser}
+}
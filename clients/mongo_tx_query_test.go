@@ -0,0 +1,181 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/Kamva/octopus/term"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+func initMongoTxQuery(session *MongoTxSession) *mongoTxQuery {
+	return newMongoTxQuery(session, "players", conditionsMap, new(MongoDB).parseConditions)
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestMongoTxQuery_First(t *testing.T) {
+	t.Run("options", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{
+			Sort:       bson.D{{Name: "score", Value: -1}, {Name: "name", Value: 1}},
+			Skip:       5,
+			Limit:      1,
+			Projection: bson.M{"name": 1},
+		}).Return([]bson.M{{"name": "Test"}}, nil)
+
+		res, err := initMongoTxQuery(session).
+			OrderBy(base.Sort{Column: "score", Descending: true}, base.Sort{Column: "name"}).
+			Skip(5).
+			Limit(10).
+			Select("name").
+			First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Test", res.Get("name"))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{Limit: 1}).Return([]bson.M{}, nil)
+
+		_, err := initMongoTxQuery(session).First()
+
+		assert.Equal(t, mgo.ErrNotFound, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{Limit: 1}).Return(nil, errTest)
+
+		_, err := initMongoTxQuery(session).First()
+
+		assert.Equal(t, errTest, err)
+	})
+}
+
+func TestMongoTxQuery_All(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{
+			Limit:      2,
+			Projection: bson.M{"rate": 0},
+		}).Return([]bson.M{{"name": "A"}, {"name": "B"}}, nil)
+
+		res, err := initMongoTxQuery(session).Limit(2).Omit("rate").All()
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, "A", res[0].Get("name"))
+		assert.Equal(t, "B", res[1].Get("name"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{}).Return(nil, errTest)
+
+		res, err := initMongoTxQuery(session).All()
+
+		assert.Equal(t, errTest, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestMongoTxQuery_Exists(t *testing.T) {
+	t.Run("exists", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{Limit: 1}).
+			Return([]bson.M{{"name": "Test"}}, nil)
+
+		exists, err := initMongoTxQuery(session).Exists()
+
+		assert.Nil(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("notExists", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "players", conditionsMap, base.MongoFindOptions{Limit: 1}).Return([]bson.M{}, nil)
+
+		exists, err := initMongoTxQuery(session).Exists()
+
+		assert.Nil(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestMongoTxQuery_Count(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Count", "players", conditionsMap).Return(12, nil)
+
+	count, err := initMongoTxQuery(session).Limit(10).Skip(20).Count()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 12, count)
+}
+
+func TestMongoTxQuery_Distinct(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Distinct", "players", "team", conditionsMap).Return([]interface{}{"A", "B"}, nil)
+
+	values, err := initMongoTxQuery(session).Distinct("team")
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"A", "B"}, values)
+
+	count, err := initMongoTxQuery(session).CountDistinct("team")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestMongoTxQuery_Aggregate(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Aggregate", "players", []bson.M{
+		{"$match": bson.M{"age": 19}},
+		{"$group": bson.M{"_id": bson.M{"team": "$team"}, "count": bson.M{"$sum": 1}}},
+		{"$project": bson.M{"_id": 0, "team": "$_id.team", "count": 1}},
+		{"$match": bson.M{"count": bson.M{"$gt": 2}}},
+		{"$limit": 5},
+	}).Return([]bson.M{{"team": "Chelsea", "count": 4}}, nil)
+
+	q := newMongoTxQuery(session, "players", bson.M{"age": 19}, new(MongoDB).parseConditions)
+	results, err := q.GroupBy("team").
+		Having(term.GreaterThan{Field: "count", Value: 2}).
+		Limit(5).
+		Aggregate(base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+		[]string{"team", "count"},
+		base.RecordMap{"team": "Chelsea", "count": 4},
+	)}, results)
+}
+
+func TestMongoTxQuery_Update(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Update", "players", conditionsMap, bson.M{"$set": bson.M{"rate": 9}}).Return(3, nil)
+
+	data := *base.NewRecordData([]string{"rate"}, base.RecordMap{"rate": 9})
+	n, err := initMongoTxQuery(session).Update(data)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestMongoTxQuery_Delete(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Delete", "players", conditionsMap).Return(2, nil)
+
+	n, err := initMongoTxQuery(session).Delete()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+}
@@ -0,0 +1,285 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kamva/octopus/base"
+	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/Kamva/octopus/term"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	driverbson "go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestMongoTx_CreateTable(t *testing.T) {
+	client := &MongoTx{session: new(MongoTxSession)}
+
+	err := client.CreateTable("users", base.CollectionInfo{})
+
+	assert.True(t, errors.Is(err, base.ErrInvalidTx))
+}
+
+func TestMongoTx_EnsureIndex(t *testing.T) {
+	client := &MongoTx{session: new(MongoTxSession)}
+
+	err := client.EnsureIndex("users", base.Index{Columns: []string{"name"}})
+
+	assert.True(t, errors.Is(err, base.ErrInvalidTx))
+}
+
+func TestMongoTx_Insert(t *testing.T) {
+	session := new(MongoTxSession)
+	data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+	session.On("Insert", "users", data.GetMap()).Return(nil)
+
+	client := &MongoTx{session: session}
+	err := client.Insert("users", base.Key{"_id"}, data)
+
+	assert.Nil(t, err)
+	assert.IsType(t, bson.ObjectId(""), data.Get("_id"))
+	session.AssertExpectations(t)
+}
+
+func TestMongoTx_FindByID(t *testing.T) {
+	id := bson.NewObjectId()
+
+	t.Run("success", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "users", bson.M{"_id": id}, base.MongoFindOptions{Limit: 1}).
+			Return([]bson.M{{"_id": id, "name": "Test"}}, nil)
+
+		client := &MongoTx{session: session}
+		res, err := client.FindByID("users", base.Key{"_id"}, id.Hex())
+
+		assert.Nil(t, err)
+		assert.Equal(t, id, res.Get("_id"))
+		assert.Equal(t, "Test", res.Get("name"))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Find", "users", bson.M{"_id": id}, base.MongoFindOptions{Limit: 1}).
+			Return([]bson.M{}, nil)
+
+		client := &MongoTx{session: session}
+		_, err := client.FindByID("users", base.Key{"_id"}, id)
+
+		assert.Equal(t, mgo.ErrNotFound, err)
+	})
+
+	t.Run("invalidID", func(t *testing.T) {
+		session := new(MongoTxSession)
+
+		client := &MongoTx{session: session}
+		_, err := client.FindByID("users", base.Key{"_id"}, "invalid")
+
+		assert.True(t, errors.Is(err, base.ErrInvalidID))
+		session.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMongoTx_UpdateByID(t *testing.T) {
+	id := bson.NewObjectId()
+	data := *base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+	update := bson.M{"$set": bson.M{"name": "Test"}}
+
+	t.Run("success", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Update", "users", bson.M{"_id": id}, update).Return(1, nil)
+
+		client := &MongoTx{session: session}
+
+		assert.Nil(t, client.UpdateByID("users", base.Key{"_id"}, id, data))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Update", "users", bson.M{"_id": id}, update).Return(0, nil)
+
+		client := &MongoTx{session: session}
+
+		assert.Equal(t, mgo.ErrNotFound, client.UpdateByID("users", base.Key{"_id"}, id, data))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Update", "users", bson.M{"_id": id}, update).Return(0, errTest)
+
+		client := &MongoTx{session: session}
+
+		assert.Equal(t, errTest, client.UpdateByID("users", base.Key{"_id"}, id, data))
+	})
+}
+
+func TestMongoTx_DeleteByID(t *testing.T) {
+	id := bson.NewObjectId()
+
+	t.Run("success", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Delete", "users", bson.M{"_id": id}).Return(1, nil)
+
+		client := &MongoTx{session: session}
+
+		assert.Nil(t, client.DeleteByID("users", base.Key{"_id"}, id))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Delete", "users", bson.M{"_id": id}).Return(0, nil)
+
+		client := &MongoTx{session: session}
+
+		assert.Equal(t, mgo.ErrNotFound, client.DeleteByID("users", base.Key{"_id"}, id))
+	})
+}
+
+func TestMongoTx_Query(t *testing.T) {
+	session := new(MongoTxSession)
+	client := &MongoTx{session: session}
+
+	query := client.Query("users", term.Equal{Field: "name", Value: "Test"}).(*mongoTxQuery)
+
+	assert.Equal(t, session, query.session)
+	assert.Equal(t, "users", query.collection)
+	assert.Equal(t, bson.M{"name": "Test"}, query.queryMap)
+}
+
+func TestMongoTx_Raw(t *testing.T) {
+	t.Run("filter", func(t *testing.T) {
+		filter := bson.M{"age": bson.M{"$gt": 18}}
+		session := new(MongoTxSession)
+		session.On("Find", "users", filter, base.MongoFindOptions{}).
+			Return([]bson.M{{"name": "Test"}}, nil)
+
+		client := &MongoTx{session: session}
+		res, err := client.Raw("users", filter).All()
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, "Test", res[0].Get("name"))
+	})
+
+	t.Run("invalidFilter", func(t *testing.T) {
+		client := &MongoTx{session: new(MongoTxSession)}
+
+		assert.Panics(t, func() {
+			client.Raw("users", "age > 18")
+		})
+	})
+}
+
+func TestMongoTx_Exec(t *testing.T) {
+	command := bson.D{{Name: "delete", Value: "users"}}
+
+	t.Run("success", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Run", command).Return(bson.M{"n": 3, "ok": 1}, nil)
+
+		client := &MongoTx{session: session}
+		n, err := client.Exec(command)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Run", command).Return(nil, errTest)
+
+		client := &MongoTx{session: session}
+		n, err := client.Exec(command)
+
+		assert.Equal(t, errTest, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func TestMongoTx_Aggregate(t *testing.T) {
+	pipeline := NewPipeline().Limit(1)
+
+	t.Run("success", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Aggregate", "users", pipeline.Stages()).Return([]bson.M{{"name": "Test"}}, nil)
+
+		client := &MongoTx{session: session}
+		res, err := client.Aggregate("users", pipeline)
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, "Test", res[0].Get("name"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		session := new(MongoTxSession)
+		session.On("Aggregate", "users", pipeline.Stages()).Return(nil, errTest)
+
+		client := &MongoTx{session: session}
+		res, err := client.Aggregate("users", pipeline)
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestMongoTx_Commit(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Commit").Return(nil)
+
+	client := &MongoTx{session: session}
+
+	assert.Nil(t, client.Commit())
+	assert.Equal(t, errNoTransaction, client.Commit())
+	assert.Equal(t, errNoTransaction, client.Rollback())
+	session.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestMongoTx_Rollback(t *testing.T) {
+	session := new(MongoTxSession)
+	session.On("Abort").Return(errTest)
+
+	client := &MongoTx{session: session}
+
+	assert.Equal(t, errTest, client.Rollback())
+	assert.Equal(t, errNoTransaction, client.Rollback())
+	session.AssertNumberOfCalls(t, "Abort", 1)
+}
+
+func TestToRaw(t *testing.T) {
+	id := bson.NewObjectId()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	raw, err := toRaw(bson.M{"_id": id, "created_at": now, "tags": []string{"a"}})
+	assert.Nil(t, err)
+
+	doc := driverbson.M{}
+	assert.Nil(t, driverbson.Unmarshal(raw, &doc))
+
+	objectID, _ := primitive.ObjectIDFromHex(id.Hex())
+	assert.Equal(t, objectID, doc["_id"])
+	assert.Equal(t, primitive.NewDateTimeFromTime(now), doc["created_at"])
+	assert.Equal(t, driverbson.A{"a"}, doc["tags"])
+}
+
+func TestFromRaw(t *testing.T) {
+	id := primitive.NewObjectID()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	raw, err := driverbson.Marshal(driverbson.M{"_id": id, "created_at": now, "tags": driverbson.A{"a"}})
+	assert.Nil(t, err)
+
+	doc, err := fromRaw(raw)
+
+	assert.Nil(t, err)
+	assert.Equal(t, bson.ObjectIdHex(id.Hex()), doc["_id"])
+	assert.Equal(t, now, doc["created_at"].(time.Time).UTC())
+	assert.Equal(t, []interface{}{"a"}, doc["tags"])
+}
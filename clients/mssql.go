@@ -1,9 +1,11 @@
 package clients
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/Kamva/octopus/base"
@@ -14,21 +16,32 @@ import (
 	_ "github.com/denisenkom/go-mssqldb"
 )
 
+// sqlServerCICollation is the case insensitive collation used for
+// matching fields with ILike conditions on SQL Server.
+const sqlServerCICollation = "Latin1_General_CI_AS"
+
 // SQLServer is the Microsoft SQL Server session
 type SQLServer struct {
 	session base.SQLDatabase
+	tx      base.SQLTx
+	ctx     context.Context
+
+	// borrowed is set if session is owned by another client, e.g.
+	// the pool client that this client is acquired from.
+	borrowed bool
 }
 
 // CreateTable creates `tableName` table with field and structure
 // defined in `structure` parameter for each table fields
 func (c *SQLServer) CreateTable(tableName string, info base.TableInfo) error {
-	existenceCheckQuery := c.generateTableExistenceCheckQuery(tableName)
+	args := c.newArgs()
+	existenceCheckQuery := c.generateTableExistenceCheckQuery(tableName, args)
 	createQuery := c.generateCreateQuery(tableName, info)
 
-	_, err := c.session.Exec(fmt.Sprintf(
+	_, err := c.executor().Exec(fmt.Sprintf(
 		"IF NOT EXISTS (%s) BEGIN %s END",
 		existenceCheckQuery, createQuery,
-	))
+	), args.values...)
 
 	return err
 }
@@ -62,15 +75,16 @@ func (c *SQLServer) EnsureIndex(tableName string, index base.Index) error {
 		)
 	}
 
+	args := c.newArgs()
 	existenceCheckQuery := fmt.Sprintf(
 		"SELECT * FROM sys.indexes WHERE name = %s AND object_id = OBJECT_ID(%s)",
-		c.enquoteValue(indexName), c.enquoteValue(tableName),
+		args.bind(indexName), args.bind(tableName),
 	)
 
-	_, err := c.session.Exec(fmt.Sprintf(
+	_, err := c.executor().Exec(fmt.Sprintf(
 		"IF NOT EXISTS (%s) BEGIN %s END",
 		existenceCheckQuery, createQuery,
-	))
+	), args.values...)
 
 	return err
 }
@@ -78,14 +92,16 @@ func (c *SQLServer) EnsureIndex(tableName string, index base.Index) error {
 // Insert tries to insert `data` into `tableName` and returns error if
 // anything went wrong. `data` should pass by reference to have exact
 // data on `tableName`, otherwise updated record data isn't accessible.
-func (c *SQLServer) Insert(tableName string, data *base.RecordData) error {
-	strings.Join(data.GetColumns(), ", ")
-	rows, err := queryDB(c.session, fmt.Sprintf(
+func (c *SQLServer) Insert(tableName string, key base.Key, data *base.RecordData) error {
+	args := c.newArgs()
+	placeholders := args.bindAll(data.GetValues())
+
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
 		"INSERT INTO %s (%s) OUTPUT inserted.* VALUES (%s)",
 		tableName,
 		strings.Join(data.GetColumns(), ", "),
-		strings.Join(data.GetValues(c.enquoteValue), ", "),
-	))
+		strings.Join(placeholders, ", "),
+	), args.values...)
 
 	if err != nil {
 		return err
@@ -95,13 +111,20 @@ func (c *SQLServer) Insert(tableName string, data *base.RecordData) error {
 }
 
 // FindByID searches through `tableName` records to find a row that its
-// ID match with `id` and returns it alongside any possible error.
-func (c *SQLServer) FindByID(tableName string, id interface{}) (base.RecordData, error) {
+// `key` primary key match with `id` and returns it alongside any
+// possible error.
+func (c *SQLServer) FindByID(tableName string, key base.Key, id interface{}) (base.RecordData, error) {
 	data := *base.ZeroRecordData()
-	rows, err := queryDB(c.session, fmt.Sprintf(
-		"SELECT * FROM %s WHERE ID = %v",
-		tableName, id,
-	))
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return data, err
+	}
+
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
 
 	if err != nil {
 		return data, err
@@ -117,42 +140,134 @@ func (c *SQLServer) FindByID(tableName string, id interface{}) (base.RecordData,
 	return data, err
 }
 
-// UpdateByID finds a record in `tableName` that its ID match with `id`,
-// and updates it with data. It will return error if anything went wrong.
-func (c *SQLServer) UpdateByID(tableName string, id interface{}, data base.RecordData) error {
-	updateQuery := prepareUpdate(data, c.enquoteValue)
-	_, err := c.session.Exec(fmt.Sprintf(
-		"UPDATE %s SET %s WHERE ID = %v",
-		tableName, updateQuery, id,
-	))
+// UpdateByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and updates it with data. It will return error if
+// anything went wrong.
+func (c *SQLServer) UpdateByID(tableName string, key base.Key, id interface{}, data base.RecordData) error {
+	args := c.newArgs()
+	updateQuery := prepareUpdate(data, args)
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		tableName, updateQuery, condition,
+	), args.values...)
 
 	return err
 }
 
-// DeleteByID finds a record in `tableName` that its ID match with `id`,
-// and remove it entirely. It will return error if anything went wrong.
-func (c *SQLServer) DeleteByID(tableName string, id interface{}) error {
-	_, err := c.session.Exec(fmt.Sprintf(
-		"DELETE FROM %s WHERE ID = %v",
-		tableName, id,
-	))
+// DeleteByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and remove it entirely. It will return error if
+// anything went wrong.
+func (c *SQLServer) DeleteByID(tableName string, key base.Key, id interface{}) error {
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"DELETE FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
 
 	return err
 }
 
 // Query generates and returns sqlQuery object for further operations
 func (c *SQLServer) Query(tableName string, conditions ...base.Condition) base.QueryBuilder {
-	return newSQLQuery(c.session, tableName, conditions, c.enquoteValue)
+	return newSQLQuery(c.executor(), tableName, conditions, c.placeholder, c.convertValue, c.match)
 }
 
-// Close disconnect session from database and release the taken memory
+// Raw returns a query fetching records of raw SQL `query` with `args`.
+// Tables are named by the query, so `tableName` is ignored.
+func (c *SQLServer) Raw(tableName string, query interface{}, args ...interface{}) base.RawQuery {
+	return newRawSQLQuery(c.executor(), query, args)
+}
+
+// Exec executes raw SQL `command` with `args` and returns number of affected rows
+func (c *SQLServer) Exec(command interface{}, args ...interface{}) (int, error) {
+	return execSQL(c.executor(), command, args)
+}
+
+// ConfigurePool applies connection pool settings of `config` on session
+func (c *SQLServer) ConfigurePool(config base.PoolConfig) {
+	configureSQLPool(c.session, config)
+}
+
+// Acquire returns a client using connections of the session pool
+func (c *SQLServer) Acquire() base.Client {
+	return &SQLServer{session: c.session, ctx: c.ctx, borrowed: true}
+}
+
+// Begin starts a new transaction and returns a client bound to it,
+// which runs all of its operations in the transaction.
+func (c *SQLServer) Begin() (base.TxClient, error) {
+	if c.tx != nil {
+		return nil, errNestedTransaction
+	}
+
+	tx, err := beginTx(c.session, orBackground(c.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLServer{session: c.session, tx: tx, ctx: c.ctx, borrowed: true}, nil
+}
+
+// Commit commits the transaction that client is bound to
+func (c *SQLServer) Commit() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Commit()
+}
+
+// Rollback aborts the transaction that client is bound to
+func (c *SQLServer) Rollback() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Rollback()
+}
+
+// Close disconnect session from database and release the taken memory.
+// Borrowed sessions are left open for the client owning them.
 func (c *SQLServer) Close() {
-	_ = c.session.Close()
+	if !c.borrowed {
+		_ = c.session.Close()
+	}
+
 	c.session = nil
+	c.tx = nil
+}
+
+// WithContext returns a copy of client which runs all of its statements
+// with `ctx`, so they are stopped when context is canceled or expired.
+func (c *SQLServer) WithContext(ctx context.Context) base.Client {
+	client := *c
+	client.ctx = ctx
+
+	return &client
+}
+
+// Get the executor of statements bound to client context, which is the
+// transaction if client is bound to one, or the database session otherwise.
+func (c *SQLServer) executor() base.SQLExecutor {
+	if c.tx != nil {
+		return withContext(c.tx, c.ctx)
+	}
+
+	return withContext(c.session, c.ctx)
 }
 
 // Generate sqlQuery that search given table with given schema
-func (c *SQLServer) generateTableExistenceCheckQuery(table string) string {
+func (c *SQLServer) generateTableExistenceCheckQuery(table string, args *sqlArgs) string {
 	parts := strings.Split(table, ".")
 
 	if len(parts) != 2 {
@@ -164,30 +279,60 @@ func (c *SQLServer) generateTableExistenceCheckQuery(table string) string {
 
 	return fmt.Sprintf(
 		"SELECT * FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = %s AND TABLE_NAME = %s",
-		c.enquoteValue(parts[0]), c.enquoteValue(parts[1]),
+		args.bind(parts[0]), args.bind(parts[1]),
 	)
 }
 
-// Enquote values to a proper presentation of their type in sql string
-func (c *SQLServer) enquoteValue(i interface{}) string {
+// Generate the SQL Server bind parameter placeholder for nth argument
+func (c *SQLServer) placeholder(n int) string {
+	return fmt.Sprintf("@p%d", n)
+}
+
+// Create a new argument list for a query using SQL Server placeholders
+func (c *SQLServer) newArgs() *sqlArgs {
+	return newSQLArgs(c.placeholder, c.convertValue)
+}
+
+// Generate the SQL Server expression of matching field with pattern
+func (c *SQLServer) match(field string, pattern string, mode base.MatchMode) string {
+	switch mode {
+	case base.ILikeMatch:
+		// Field is collated with a case insensitive collation, so it is
+		// matched case insensitively regardless of the column collation.
+		return fmt.Sprintf("%s COLLATE %s LIKE %s", field, sqlServerCICollation, pattern)
+	case base.RegexMatch:
+		panic(fmt.Errorf("%w: Regex condition is not supported by SQL Server", base.ErrUnsupportedType))
+	case base.ArrayContainsMatch, base.ArrayOverlapsMatch:
+		panic(fmt.Errorf("%w: Array conditions are not supported by SQL Server", base.ErrUnsupportedType))
+	}
+
+	return fmt.Sprintf("%s LIKE %s", field, pattern)
+}
+
+// Convert values to a proper presentation of their type for mssql driver
+func (c *SQLServer) convertValue(i interface{}) interface{} {
+	i = encodeValue(i)
+	if i == nil {
+		return nil
+	}
+
+	if isDriverValue(i) {
+		return i
+	}
+
 	t := reflect.TypeOf(i)
 
 	switch t.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		reflect.Float32, reflect.Float64:
-		return fmt.Sprintf("%v", i)
-	case reflect.String:
-		return fmt.Sprintf("N'%s'", i.(string))
-	case reflect.Bool:
-		b := i.(bool)
-		if b {
-			return "1"
-		}
-		return "0"
-	}
-
-	panic(fmt.Sprintf("Value with type of %s is not supported", t.Kind().String()))
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return i
+	case reflect.Uint64:
+		// driver does not accept uint64 values with high bit set
+		return strconv.FormatUint(reflect.ValueOf(i).Uint(), 10)
+	}
+
+	panic(fmt.Errorf("%w: value with type of %s", base.ErrUnsupportedType, t.Kind().String()))
 }
 
 func (c *SQLServer) generateCreateQuery(table string, info base.TableInfo) string {
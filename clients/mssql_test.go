@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -19,7 +20,7 @@ var errTest = errors.New("something went wrong")
 
 type sqlOpener func(d string, u string) (base.SQLDatabase, error)
 
-type dbQuerier func(db base.SQLDatabase, query string) (base.SQLRows, error)
+type dbQuerier func(db base.SQLExecutor, query string, args ...interface{}) (base.SQLRows, error)
 
 var sqlOpenMock = func(d string, u string, sqlDB *SQLDatabase, err error) sqlOpener {
 	return func(d string, u string) (base.SQLDatabase, error) {
@@ -27,13 +28,19 @@ var sqlOpenMock = func(d string, u string, sqlDB *SQLDatabase, err error) sqlOpe
 	}
 }
 
-var queryDBMock = func(db base.SQLDatabase, query string, rows base.SQLRows) dbQuerier {
-	return func(db base.SQLDatabase, query string) (base.SQLRows, error) {
-		_, err := db.Query(query)
+var queryDBMock = func(db base.SQLExecutor, query string, rows base.SQLRows) dbQuerier {
+	return func(db base.SQLExecutor, query string, args ...interface{}) (base.SQLRows, error) {
+		_, err := db.Query(query, args...)
 		return rows, err
 	}
 }
 
+var beginTxMock = func(tx base.SQLTx, err error) func(db base.SQLDatabase, ctx context.Context) (base.SQLTx, error) {
+	return func(db base.SQLDatabase, ctx context.Context) (base.SQLTx, error) {
+		return tx, err
+	}
+}
+
 func initSQLServer(session base.SQLDatabase) *SQLServer {
 	return &SQLServer{session: session}
 }
@@ -94,7 +101,7 @@ func TestSQLServer_CreateTable(t *testing.T) {
 
 		createQuery := "IF NOT EXISTS (" +
 			"SELECT * FROM INFORMATION_SCHEMA.TABLES " +
-			"WHERE TABLE_SCHEMA = N'dbo' AND TABLE_NAME = N'accounts'" +
+			"WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2" +
 			") BEGIN " +
 			"CREATE TABLE dbo.accounts (" +
 			"ID INT IDENTITY PRIMARY KEY, " +
@@ -109,7 +116,7 @@ func TestSQLServer_CreateTable(t *testing.T) {
 			"Unsigned DECIMAL" +
 			") END"
 
-		session.On("Exec", createQuery).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, createQuery, "dbo", "accounts").Return(nil, nil)
 
 		client := initSQLServer(session)
 		err := client.CreateTable("dbo.accounts", getSQLTableStructure())
@@ -130,7 +137,7 @@ func TestSQLServer_CreateTable(t *testing.T) {
 	t.Run("dbExecError", func(t *testing.T) {
 		session := new(SQLDatabase)
 
-		session.On("Exec", mock.AnythingOfType("string")).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.Anything).Return(nil, errTest)
 
 		client := initSQLServer(session)
 		err := client.CreateTable("dbo.accounts", getSQLTableStructure())
@@ -145,10 +152,10 @@ func TestSQLServer_EnsureIndex(t *testing.T) {
 
 		query := "IF NOT EXISTS (" +
 			"SELECT * FROM sys.indexes " +
-			"WHERE name = N'Name_index' AND object_id = OBJECT_ID(N'dbo.accounts')" +
+			"WHERE name = @p1 AND object_id = OBJECT_ID(@p2)" +
 			") BEGIN CREATE INDEX Name_index ON dbo.accounts (Name) END"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, "Name_index", "dbo.accounts").Return(nil, nil)
 
 		client := initSQLServer(session)
 		err := client.EnsureIndex("dbo.accounts", base.Index{
@@ -163,10 +170,10 @@ func TestSQLServer_EnsureIndex(t *testing.T) {
 
 		query := "IF NOT EXISTS (" +
 			"SELECT * FROM sys.indexes " +
-			"WHERE name = N'Name_Email_index' AND object_id = OBJECT_ID(N'dbo.accounts')" +
+			"WHERE name = @p1 AND object_id = OBJECT_ID(@p2)" +
 			") BEGIN CREATE INDEX Name_Email_index ON dbo.accounts (Name, Email) END"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, "Name_Email_index", "dbo.accounts").Return(nil, nil)
 
 		client := initSQLServer(session)
 		err := client.EnsureIndex("dbo.accounts", base.Index{
@@ -181,10 +188,10 @@ func TestSQLServer_EnsureIndex(t *testing.T) {
 
 		query := "IF NOT EXISTS (" +
 			"SELECT * FROM sys.indexes " +
-			"WHERE name = N'Name_unique_index' AND object_id = OBJECT_ID(N'dbo.accounts')" +
+			"WHERE name = @p1 AND object_id = OBJECT_ID(@p2)" +
 			") BEGIN CREATE UNIQUE INDEX Name_unique_index ON dbo.accounts (Name) END"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, "Name_unique_index", "dbo.accounts").Return(nil, nil)
 
 		client := initSQLServer(session)
 		err := client.EnsureIndex("dbo.accounts", base.Index{
@@ -200,10 +207,10 @@ func TestSQLServer_EnsureIndex(t *testing.T) {
 
 		query := "IF NOT EXISTS (" +
 			"SELECT * FROM sys.indexes " +
-			"WHERE name = N'Name_Email_unique_index' AND object_id = OBJECT_ID(N'dbo.accounts')" +
+			"WHERE name = @p1 AND object_id = OBJECT_ID(@p2)" +
 			") BEGIN CREATE UNIQUE INDEX Name_Email_unique_index ON dbo.accounts (Name, Email) END"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, "Name_Email_unique_index", "dbo.accounts").Return(nil, nil)
 
 		client := initSQLServer(session)
 		err := client.EnsureIndex("dbo.accounts", base.Index{
@@ -217,7 +224,7 @@ func TestSQLServer_EnsureIndex(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		session := new(SQLDatabase)
 
-		session.On("Exec", mock.AnythingOfType("string")).
+		session.On("ExecContext", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.Anything).
 			Return(nil, errTest)
 
 		client := initSQLServer(session)
@@ -235,11 +242,12 @@ func TestSQLServer_Insert(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		query := "INSERT INTO dbo.players (name, rate, available) " +
-			"OUTPUT inserted.* VALUES (N'Test', 3.5, 1)"
+			"OUTPUT inserted.* VALUES (@p1, @p2, @p3)"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, "Test", 3.5, true).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
@@ -263,7 +271,7 @@ func TestSQLServer_Insert(t *testing.T) {
 			[]string{"name", "rate", "available"},
 			base.RecordMap{"name": "Test", "rate": 3.5, "available": true},
 		)
-		err := client.Insert("dbo.players", data)
+		err := client.Insert("dbo.players", base.Key{"id"}, data)
 
 		assert.Nil(t, err)
 
@@ -278,11 +286,12 @@ func TestSQLServer_Insert(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		query := "INSERT INTO dbo.players (name, rate, available) " +
-			"OUTPUT inserted.* VALUES (N'Test', 3.5, 0)"
+			"OUTPUT inserted.* VALUES (@p1, @p2, @p3)"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, "Test", 3.5, false).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
@@ -306,7 +315,7 @@ func TestSQLServer_Insert(t *testing.T) {
 			[]string{"name", "rate", "available"},
 			base.RecordMap{"name": "Test", "rate": 3.5, "available": false},
 		)
-		err := client.Insert("dbo.players", data)
+		err := client.Insert("dbo.players", base.Key{"id"}, data)
 
 		assert.Nil(t, err)
 
@@ -325,7 +334,7 @@ func TestSQLServer_Insert(t *testing.T) {
 		)
 
 		assert.Panics(t, func() {
-			_ = client.Insert("dbo.players", data)
+			_ = client.Insert("dbo.players", base.Key{"id"}, data)
 		})
 	})
 
@@ -334,11 +343,12 @@ func TestSQLServer_Insert(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		query := "INSERT INTO dbo.players (name, rate, available) " +
-			"OUTPUT inserted.* VALUES (N'Test', 3.5, 1)"
+			"OUTPUT inserted.* VALUES (@p1, @p2, @p3)"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, errTest)
+		session.On("QueryContext", mock.Anything, query, "Test", 3.5, true).Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initSQLServer(session)
@@ -346,7 +356,7 @@ func TestSQLServer_Insert(t *testing.T) {
 			[]string{"name", "rate", "available"},
 			base.RecordMap{"name": "Test", "rate": 3.5, "available": true},
 		)
-		err := client.Insert("dbo.players", data)
+		err := client.Insert("dbo.players", base.Key{"id"}, data)
 
 		assert.NotNil(t, err)
 	})
@@ -356,11 +366,12 @@ func TestSQLServer_Insert(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		query := "INSERT INTO dbo.players (name, rate, available) " +
-			"OUTPUT inserted.* VALUES (N'Test', 3.5, 1)"
+			"OUTPUT inserted.* VALUES (@p1, @p2, @p3)"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, "Test", 3.5, true).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
@@ -376,7 +387,7 @@ func TestSQLServer_Insert(t *testing.T) {
 			[]string{"name", "rate", "available"},
 			base.RecordMap{"name": "Test", "rate": 3.5, "available": true},
 		)
-		err := client.Insert("dbo.players", data)
+		err := client.Insert("dbo.players", base.Key{"id"}, data)
 
 		assert.NotNil(t, err)
 	})
@@ -387,11 +398,12 @@ func TestSQLServer_FindByID(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "SELECT * FROM dbo.players WHERE ID = 1"
+		query := "SELECT * FROM dbo.players WHERE ID = @p1"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
 			[]string{"id", "name", "rate", "available"},
@@ -409,7 +421,7 @@ func TestSQLServer_FindByID(t *testing.T) {
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initSQLServer(session)
-		data, err := client.FindByID("dbo.players", 1)
+		data, err := client.FindByID("dbo.players", base.Key{"ID"}, 1)
 
 		assert.Nil(t, err)
 
@@ -423,16 +435,18 @@ func TestSQLServer_FindByID(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "SELECT * FROM dbo.players WHERE ID = 1"
+		query := "SELECT * FROM dbo.players WHERE ID = @p1"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initSQLServer(session)
-		data, err := client.FindByID("dbo.players", 1)
+		data, err := client.FindByID("dbo.players", base.Key{"ID"}, 1)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, *base.ZeroRecordData(), data)
@@ -442,15 +456,16 @@ func TestSQLServer_FindByID(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "SELECT * FROM dbo.players WHERE ID = 1"
+		query := "SELECT * FROM dbo.players WHERE ID = @p1"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, errTest)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initSQLServer(session)
-		data, err := client.FindByID("dbo.players", 1)
+		data, err := client.FindByID("dbo.players", base.Key{"ID"}, 1)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, *base.ZeroRecordData(), data)
@@ -459,33 +474,33 @@ func TestSQLServer_FindByID(t *testing.T) {
 
 func TestSQLServer_UpdateByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		query := "UPDATE dbo.players SET name = N'Updated Test', available = 0 WHERE ID = 1"
+		query := "UPDATE dbo.players SET name = @p1, available = @p2 WHERE ID = @p3"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, "Updated Test", 0, 1).Return(nil, nil)
 
 		client := initSQLServer(session)
 		data := base.NewRecordData(
 			[]string{"name", "available"},
 			base.RecordMap{"name": "Updated Test", "available": 0},
 		)
-		err := client.UpdateByID("dbo.players", 1, *data)
+		err := client.UpdateByID("dbo.players", base.Key{"ID"}, 1, *data)
 
 		assert.Nil(t, err)
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		query := "UPDATE dbo.players SET name = N'Updated Test', rate = 9.1 WHERE ID = 1"
+		query := "UPDATE dbo.players SET name = @p1, rate = @p2 WHERE ID = @p3"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, query, "Updated Test", 9.1, 1).Return(nil, errTest)
 
 		client := initSQLServer(session)
 		data := base.NewRecordData(
 			[]string{"name", "rate"},
 			base.RecordMap{"name": "Updated Test", "rate": 9.1},
 		)
-		err := client.UpdateByID("dbo.players", 1, *data)
+		err := client.UpdateByID("dbo.players", base.Key{"ID"}, 1, *data)
 
 		assert.NotNil(t, err)
 	})
@@ -493,25 +508,25 @@ func TestSQLServer_UpdateByID(t *testing.T) {
 
 func TestSQLServer_DeleteByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		query := "DELETE FROM dbo.players WHERE ID = 1"
+		query := "DELETE FROM dbo.players WHERE ID = @p1"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, 1).Return(nil, nil)
 
 		client := initSQLServer(session)
-		err := client.DeleteByID("dbo.players", 1)
+		err := client.DeleteByID("dbo.players", base.Key{"ID"}, 1)
 
 		assert.Nil(t, err)
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		query := "DELETE FROM dbo.players WHERE ID = 1"
+		query := "DELETE FROM dbo.players WHERE ID = @p1"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, query, 1).Return(nil, errTest)
 
 		client := initSQLServer(session)
-		err := client.DeleteByID("dbo.players", 1)
+		err := client.DeleteByID("dbo.players", base.Key{"ID"}, 1)
 
 		assert.NotNil(t, err)
 	})
@@ -534,6 +549,43 @@ func TestSQLServer_Query(t *testing.T) {
 	assert.Equal(t, "dbo.players", q.table)
 }
 
+func TestSQLServer_match(t *testing.T) {
+	client := initSQLServer(new(SQLDatabase))
+
+	assert.Equal(t, "name LIKE @p1", client.match("name", "@p1", base.LikeMatch))
+	assert.Equal(t, "name COLLATE Latin1_General_CI_AS LIKE @p1", client.match("name", "@p1", base.ILikeMatch))
+	assert.PanicsWithError(t, "unsupported type: Regex condition is not supported by SQL Server", func() {
+		_ = client.match("name", "@p1", base.RegexMatch)
+	})
+	assert.PanicsWithError(t, "unsupported type: Array conditions are not supported by SQL Server", func() {
+		_ = client.match("tags", "@p1", base.ArrayContainsMatch)
+	})
+	assert.PanicsWithError(t, "unsupported type: Array conditions are not supported by SQL Server", func() {
+		_ = client.match("tags", "@p1", base.ArrayOverlapsMatch)
+	})
+}
+
+func TestSQLServer_Begin(t *testing.T) {
+	original := beginTx
+	defer func() { beginTx = original }()
+
+	session := new(SQLDatabase)
+	tx := new(SQLTx)
+	tx.On("ExecContext", mock.Anything, "DELETE FROM users WHERE ID = @p1", 1).Return(nil, nil)
+	beginTx = beginTxMock(tx, nil)
+
+	client := initSQLServer(session)
+	txClient, err := client.Begin()
+
+	assert.Nil(t, err)
+	assert.Nil(t, txClient.DeleteByID("users", base.Key{"ID"}, 1))
+
+	txClient.Close()
+
+	session.AssertNotCalled(t, "ExecContext")
+	session.AssertNotCalled(t, "Close")
+}
+
 func TestSQLServer_Close(t *testing.T) {
 	session := new(SQLDatabase)
 	session.On("Close").Return(nil)
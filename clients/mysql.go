@@ -0,0 +1,371 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/shark"
+
+	// Register mysql driver to database/sql So you can use
+	// sql.Open("mysql", ...) to open mysql connection session
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// MySQL is the MySQL/MariaDB client
+type MySQL struct {
+	session base.SQLDatabase
+	tx      base.SQLTx
+	ctx     context.Context
+
+	// borrowed is set if session is owned by another client, e.g.
+	// the pool client that this client is acquired from.
+	borrowed bool
+}
+
+// CreateTable creates `tableName` table with field and structure
+// defined in `structure` parameter for each table fields
+func (c *MySQL) CreateTable(tableName string, info base.TableInfo) error {
+	_, err := c.executor().Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s )",
+		tableName, info.GetInfo().(string),
+	))
+
+	return err
+}
+
+// EnsureIndex ensures that `index` is exists on `tableName` table,
+// if not, it tries to create index with specified condition in
+// `index` on `tableName`.
+func (c *MySQL) EnsureIndex(tableName string, index base.Index) error {
+	columns := strings.Join(index.Columns, ", ")
+
+	var indexName, createQuery string
+	if index.Unique {
+		indexName = fmt.Sprintf(
+			"%s_unique_index",
+			strings.Join(index.Columns, "_"),
+		)
+
+		createQuery = fmt.Sprintf(
+			"CREATE UNIQUE INDEX %s ON %s (%s)",
+			indexName, tableName, columns,
+		)
+	} else {
+		indexName = fmt.Sprintf(
+			"%s_index",
+			strings.Join(index.Columns, "_"),
+		)
+
+		createQuery = fmt.Sprintf(
+			"CREATE INDEX %s ON %s (%s)",
+			indexName, tableName, columns,
+		)
+	}
+
+	// MySQL does not support `IF NOT EXISTS` on index creation,
+	// so we check the index existence on information schema.
+	args := c.newArgs()
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"SELECT COUNT(*) AS count FROM information_schema.statistics "+
+			"WHERE table_schema = DATABASE() AND table_name = %s AND index_name = %s",
+		args.bind(tableName), args.bind(indexName),
+	), args.values...)
+
+	if err != nil {
+		return err
+	}
+
+	count, err := fetchCount(rows)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	_, err = c.executor().Exec(createQuery)
+
+	return err
+}
+
+// Insert tries to insert `data` into `tableName` and returns error if
+// anything went wrong. `data` should pass by reference to have exact
+// data on `tableName`, otherwise updated record data isn't accessible.
+func (c *MySQL) Insert(tableName string, key base.Key, data *base.RecordData) error {
+	args := c.newArgs()
+	placeholders := args.bindAll(data.GetValues())
+
+	res, err := c.executor().Exec(fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		tableName,
+		strings.Join(data.GetColumns(), ", "),
+		strings.Join(placeholders, ", "),
+	), args.values...)
+
+	if err != nil {
+		return err
+	}
+
+	// MySQL has no RETURNING clause, so the inserted record is read
+	// back by its generated auto increment ID if table has any. Keys
+	// of composite primary keys are never generated.
+	id, err := res.LastInsertId()
+	if err != nil || id == 0 || key.IsComposite() {
+		return err
+	}
+
+	args = c.newArgs()
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s = %s",
+		tableName, key[0], args.bind(id),
+	), args.values...)
+
+	if err != nil {
+		return err
+	}
+
+	err = fetchSingleRecord(rows, data)
+	data.PruneData(pruneBytes)
+
+	return err
+}
+
+// FindByID searches through `tableName` records to find a row that its
+// `key` primary key match with `id` and returns it alongside any
+// possible error.
+func (c *MySQL) FindByID(tableName string, key base.Key, id interface{}) (base.RecordData, error) {
+	data := *base.ZeroRecordData()
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return data, err
+	}
+
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
+
+	if err != nil {
+		return data, err
+	}
+
+	err = fetchSingleRecord(rows, &data)
+
+	if err != nil {
+		data.Zero()
+		return data, err
+	}
+
+	data.PruneData(pruneBytes)
+
+	return data, err
+}
+
+// UpdateByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and updates it with data. It will return error if
+// anything went wrong.
+func (c *MySQL) UpdateByID(tableName string, key base.Key, id interface{}, data base.RecordData) error {
+	args := c.newArgs()
+	updateQuery := prepareUpdate(data, args)
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		tableName, updateQuery, condition,
+	), args.values...)
+
+	return err
+}
+
+// DeleteByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and remove it entirely. It will return error if
+// anything went wrong.
+func (c *MySQL) DeleteByID(tableName string, key base.Key, id interface{}) error {
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"DELETE FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
+
+	return err
+}
+
+// Query generates and returns sqlQuery object for further operations
+func (c *MySQL) Query(tableName string, conditions ...base.Condition) base.QueryBuilder {
+	query := newSQLQuery(c.executor(), tableName, conditions, c.placeholder, c.convertValue, c.match)
+	query.paginator = c.paginate
+	query.pruner = pruneBytes
+
+	return query
+}
+
+// Raw returns a query fetching records of raw SQL `query` with `args`.
+// Tables are named by the query, so `tableName` is ignored.
+func (c *MySQL) Raw(tableName string, query interface{}, args ...interface{}) base.RawQuery {
+	rawQuery := newRawSQLQuery(c.executor(), query, args)
+	rawQuery.pruner = pruneBytes
+
+	return rawQuery
+}
+
+// Exec executes raw SQL `command` with `args` and returns number of affected rows
+func (c *MySQL) Exec(command interface{}, args ...interface{}) (int, error) {
+	return execSQL(c.executor(), command, args)
+}
+
+// ConfigurePool applies connection pool settings of `config` on session
+func (c *MySQL) ConfigurePool(config base.PoolConfig) {
+	configureSQLPool(c.session, config)
+}
+
+// Acquire returns a client using connections of the session pool
+func (c *MySQL) Acquire() base.Client {
+	return &MySQL{session: c.session, ctx: c.ctx, borrowed: true}
+}
+
+// Begin starts a new transaction and returns a client bound to it,
+// which runs all of its operations in the transaction.
+func (c *MySQL) Begin() (base.TxClient, error) {
+	if c.tx != nil {
+		return nil, errNestedTransaction
+	}
+
+	tx, err := beginTx(c.session, orBackground(c.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MySQL{session: c.session, tx: tx, ctx: c.ctx, borrowed: true}, nil
+}
+
+// Commit commits the transaction that client is bound to
+func (c *MySQL) Commit() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Commit()
+}
+
+// Rollback aborts the transaction that client is bound to
+func (c *MySQL) Rollback() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Rollback()
+}
+
+// Close disconnect session from database and release the taken memory.
+// Borrowed sessions are left open for the client owning them.
+func (c *MySQL) Close() {
+	if !c.borrowed {
+		_ = c.session.Close()
+	}
+
+	c.session = nil
+	c.tx = nil
+}
+
+// WithContext returns a copy of client which runs all of its statements
+// with `ctx`, so they are stopped when context is canceled or expired.
+func (c *MySQL) WithContext(ctx context.Context) base.Client {
+	client := *c
+	client.ctx = ctx
+
+	return &client
+}
+
+// Get the executor of statements bound to client context, which is the
+// transaction if client is bound to one, or the database session otherwise.
+func (c *MySQL) executor() base.SQLExecutor {
+	if c.tx != nil {
+		return withContext(c.tx, c.ctx)
+	}
+
+	return withContext(c.session, c.ctx)
+}
+
+// Generate the MySQL bind parameter placeholder, which is
+// the same for all arguments regardless of their position.
+func (c *MySQL) placeholder(int) string {
+	return "?"
+}
+
+// Create a new argument list for a query using MySQL placeholders
+func (c *MySQL) newArgs() *sqlArgs {
+	return newSQLArgs(c.placeholder, c.convertValue)
+}
+
+// Generate the MySQL expression of matching field with pattern
+func (c *MySQL) match(field string, pattern string, mode base.MatchMode) string {
+	switch mode {
+	case base.ILikeMatch:
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", field, pattern)
+	case base.RegexMatch:
+		return fmt.Sprintf("%s REGEXP %s", field, pattern)
+	case base.ArrayContainsMatch, base.ArrayOverlapsMatch:
+		panic(fmt.Errorf("%w: Array conditions are not supported by MySQL", base.ErrUnsupportedType))
+	}
+
+	return fmt.Sprintf("%s LIKE %s", field, pattern)
+}
+
+// Generate the MySQL LIMIT and OFFSET clause. OFFSET is not accepted without
+// LIMIT, so the largest unsigned limit is set if only `offset` is given.
+func (c *MySQL) paginate(limit int, offset int) string {
+	if limit == 0 && offset > 0 {
+		return fmt.Sprintf("LIMIT 18446744073709551615 OFFSET %v", offset)
+	}
+
+	return paginate(limit, offset)
+}
+
+// Convert values to a proper presentation of their type for mysql driver
+func (c *MySQL) convertValue(i interface{}) interface{} {
+	i = encodeValue(i)
+	if i == nil {
+		return nil
+	}
+
+	if isDriverValue(i) {
+		return i
+	}
+
+	t := reflect.TypeOf(i)
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return i
+	case reflect.Map, reflect.Struct:
+		bytes, err := json.Marshal(i)
+		shark.PanicIfError(err)
+		return string(bytes)
+	}
+
+	panic(fmt.Errorf("%w: value with type of %s", base.ErrUnsupportedType, t.Kind().String()))
+}
+
+// NewMySQL instantiate and return a new MySQL session object
+func NewMySQL(url string) base.Client {
+	session, err := sqlOpen("mysql", url)
+	shark.PanicIfError(err)
+
+	return &MySQL{session: session}
+}
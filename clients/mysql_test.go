@@ -0,0 +1,477 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+func initMySQL(session base.SQLDatabase) *MySQL {
+	return &MySQL{session: session}
+}
+
+func getMySQLTableStructure() base.TableStructure {
+	return base.TableStructure{
+		{Name: "id", Type: "INT", Options: "AUTO_INCREMENT PRIMARY KEY"},
+		{Name: "name", Type: "VARCHAR(255)", Options: "NOT NULL"},
+		{Name: "age", Type: "INT", Options: "NULL"},
+		{Name: "status", Type: "BOOLEAN", Options: "DEFAULT TRUE"},
+	}
+}
+
+var indexExistenceQuery = "SELECT COUNT(*) AS count FROM information_schema.statistics " +
+	"WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ?"
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestNewMySQL(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		original := sqlOpen
+		defer func() { sqlOpen = original }()
+
+		db := new(SQLDatabase)
+		url := "root@tcp(localhost:3306)/test"
+		sqlOpen = sqlOpenMock("mysql", url, db, nil)
+
+		assert.NotPanics(t, func() {
+			client := NewMySQL(url)
+			sql := client.(*MySQL)
+
+			assert.Equal(t, db, sql.session)
+		})
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		original := sqlOpen
+		defer func() { sqlOpen = original }()
+
+		db := new(SQLDatabase)
+		url := "invalid URL"
+		sqlOpen = sqlOpenMock("mysql", url, db, errTest)
+
+		assert.Panics(t, func() {
+			_ = NewMySQL(url)
+		})
+	})
+}
+
+func TestMySQL_CreateTable(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		session := new(SQLDatabase)
+
+		createQuery := "CREATE TABLE IF NOT EXISTS users ( " +
+			"id INT AUTO_INCREMENT PRIMARY KEY, " +
+			"name VARCHAR(255) NOT NULL, " +
+			"age INT NULL, " +
+			"status BOOLEAN DEFAULT TRUE )"
+
+		session.On("ExecContext", mock.Anything, createQuery).Return(nil, nil)
+
+		client := initMySQL(session)
+		err := client.CreateTable("users", getMySQLTableStructure())
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("dbExecError", func(t *testing.T) {
+		session := new(SQLDatabase)
+
+		session.On("ExecContext", mock.Anything, mock.AnythingOfType("string")).Return(nil, errTest)
+
+		client := initMySQL(session)
+		err := client.CreateTable("users", getMySQLTableStructure())
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestMySQL_EnsureIndex(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "CREATE UNIQUE INDEX name_email_unique_index ON users (name, email)"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, indexExistenceQuery, "users", "name_email_unique_index").Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return([]string{"count"}, nil)
+		rows.On("Scan", mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				arg := args.Get(0).(*interface{})
+				*arg = int64(0)
+			})
+
+		queryDB = queryDBMock(session, indexExistenceQuery, rows)
+		client := initMySQL(session)
+		err := client.EnsureIndex("users", base.Index{
+			Columns: []string{"name", "email"},
+			Unique:  true,
+		})
+
+		assert.Nil(t, err)
+		session.AssertCalled(t, "ExecContext", mock.Anything, query)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, indexExistenceQuery, "users", "name_index").Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return([]string{"count"}, nil)
+		rows.On("Scan", mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				arg := args.Get(0).(*interface{})
+				*arg = int64(1)
+			})
+
+		queryDB = queryDBMock(session, indexExistenceQuery, rows)
+		client := initMySQL(session)
+		err := client.EnsureIndex("users", base.Index{
+			Columns: []string{"name"},
+		})
+
+		assert.Nil(t, err)
+		session.AssertNotCalled(t, "ExecContext", mock.Anything)
+	})
+
+	t.Run("existsAsBytes", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, indexExistenceQuery, "users", "name_index").Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return([]string{"count"}, nil)
+		rows.On("Scan", mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				arg := args.Get(0).(*interface{})
+				*arg = []byte("1")
+			})
+
+		queryDB = queryDBMock(session, indexExistenceQuery, rows)
+		client := initMySQL(session)
+		err := client.EnsureIndex("users", base.Index{
+			Columns: []string{"name"},
+		})
+
+		assert.Nil(t, err)
+		session.AssertNotCalled(t, "ExecContext", mock.Anything)
+	})
+
+	t.Run("queryError", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, indexExistenceQuery, "users", "name_index").Return(nil, errTest)
+
+		queryDB = queryDBMock(session, indexExistenceQuery, new(SQLRows))
+		client := initMySQL(session)
+		err := client.EnsureIndex("users", base.Index{
+			Columns: []string{"name"},
+		})
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestMySQL_Insert(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		insertQuery := "INSERT INTO users (name, age, status) VALUES (?, ?, ?)"
+		selectQuery := "SELECT * FROM users WHERE id = ?"
+
+		session := new(SQLDatabase)
+		session.On("ExecContext", mock.Anything, insertQuery, "Test", 5, true).Return(result{1}, nil)
+		session.On("QueryContext", mock.Anything, selectQuery, int64(1)).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return(
+			[]string{"id", "name", "age", "status"},
+			nil,
+		)
+
+		rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				values := []interface{}{int64(1), []byte("Test"), int64(5), int64(1)}
+				for i, value := range values {
+					arg := args.Get(i).(*interface{})
+					*arg = value
+				}
+			})
+
+		queryDB = queryDBMock(session, selectQuery, rows)
+		client := initMySQL(session)
+		data := base.NewRecordData(
+			[]string{"name", "age", "status"},
+			base.RecordMap{"name": "Test", "age": 5, "status": true},
+		)
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.Nil(t, err)
+
+		assert.Equal(t, int64(1), data.Get("id"))
+		assert.Equal(t, "Test", data.Get("name"))
+		assert.Equal(t, int64(5), data.Get("age"))
+		assert.Equal(t, int64(1), data.Get("status"))
+	})
+
+	t.Run("withoutAutoIncrement", func(t *testing.T) {
+		insertQuery := "INSERT INTO users (name) VALUES (?)"
+
+		session := new(SQLDatabase)
+		session.On("ExecContext", mock.Anything, insertQuery, "Test").Return(result{0}, nil)
+
+		client := initMySQL(session)
+		data := base.NewRecordData(
+			[]string{"name"},
+			base.RecordMap{"name": "Test"},
+		)
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Test", data.Get("name"))
+		session.AssertNotCalled(t, "QueryContext", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unsupportedType", func(t *testing.T) {
+		session := new(SQLDatabase)
+		client := initMySQL(session)
+		data := base.NewRecordData(
+			[]string{"invalidSlice"},
+			base.RecordMap{
+				"invalidSlice": []int{1, 2},
+			},
+		)
+		assert.Panics(t, func() {
+			_ = client.Insert("users", base.Key{"id"}, data)
+		})
+	})
+
+	t.Run("execError", func(t *testing.T) {
+		insertQuery := "INSERT INTO users (name, age, status) VALUES (?, ?, ?)"
+
+		session := new(SQLDatabase)
+		session.On("ExecContext", mock.Anything, insertQuery, "Test", 5, true).Return(nil, errTest)
+
+		client := initMySQL(session)
+		data := base.NewRecordData(
+			[]string{"name", "age", "status"},
+			base.RecordMap{"name": "Test", "age": 5, "status": true},
+		)
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestMySQL_FindByID(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "SELECT * FROM users WHERE id = ?"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return(
+			[]string{"id", "name", "rate", "available"},
+			nil,
+		)
+		rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				values := []interface{}{int64(1), []byte("Test"), 3.5, int64(1)}
+				for i, value := range values {
+					arg := args.Get(i).(*interface{})
+					*arg = value
+				}
+			})
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initMySQL(session)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
+
+		assert.Nil(t, err)
+
+		assert.Equal(t, int64(1), data.Get("id"))
+		assert.Equal(t, "Test", data.Get("name"))
+		assert.Equal(t, 3.5, data.Get("rate"))
+		assert.Equal(t, int64(1), data.Get("available"))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "SELECT * FROM users WHERE id = ?"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initMySQL(session)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, *base.ZeroRecordData(), data)
+	})
+}
+
+func TestMySQL_UpdateByID(t *testing.T) {
+	query := "UPDATE users SET name = ?, available = ? WHERE id = ?"
+
+	session := new(SQLDatabase)
+	session.On("ExecContext", mock.Anything, query, "Updated Test", false, 1).Return(nil, nil)
+
+	client := initMySQL(session)
+	data := base.NewRecordData(
+		[]string{"name", "available"},
+		base.RecordMap{"name": "Updated Test", "available": false},
+	)
+	err := client.UpdateByID("users", base.Key{"id"}, 1, *data)
+
+	assert.Nil(t, err)
+}
+
+func TestMySQL_DeleteByID(t *testing.T) {
+	query := "DELETE FROM users WHERE id = ?"
+
+	session := new(SQLDatabase)
+	session.On("ExecContext", mock.Anything, query, 1).Return(nil, errTest)
+
+	client := initMySQL(session)
+	err := client.DeleteByID("users", base.Key{"id"}, 1)
+
+	assert.NotNil(t, err)
+}
+
+func TestMySQL_Query(t *testing.T) {
+	conditions := []base.Condition{
+		term.Equal{Field: "name", Value: "Test"},
+	}
+
+	session := new(SQLDatabase)
+	client := initMySQL(session)
+	r := client.Query("users", conditions...)
+
+	assert.IsType(t, new(sqlQuery), r)
+
+	q := r.(*sqlQuery)
+
+	assert.Equal(t, conditions, q.conditions)
+	assert.Equal(t, "users", q.table)
+	assert.Equal(t, "?", q.placeholder(3))
+	assert.NotNil(t, q.paginator)
+	assert.NotNil(t, q.pruner)
+}
+
+func TestMySQL_paginate(t *testing.T) {
+	client := initMySQL(new(SQLDatabase))
+
+	assert.Equal(t, "", client.paginate(0, 0))
+	assert.Equal(t, "LIMIT 10", client.paginate(10, 0))
+	assert.Equal(t, "LIMIT 10 OFFSET 5", client.paginate(10, 5))
+	assert.Equal(t, "LIMIT 18446744073709551615 OFFSET 5", client.paginate(0, 5))
+
+	t.Run("skip", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "SELECT * FROM users WHERE name = ? ORDER BY id ASC LIMIT 18446744073709551615 OFFSET 1"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, "Test").Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Err").Return(nil)
+		rows.On("Next").Return(false)
+		rows.On("Columns").Return([]string{"id", "name"}, nil)
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initMySQL(session)
+		_, err := client.Query("users", term.Equal{Field: "name", Value: "Test"}).
+			OrderBy(base.Sort{Column: "id"}).
+			Skip(1).
+			All()
+
+		assert.Nil(t, err)
+		session.AssertCalled(t, "QueryContext", mock.Anything, query, "Test")
+	})
+}
+
+func TestMySQL_match(t *testing.T) {
+	client := initMySQL(new(SQLDatabase))
+
+	assert.Equal(t, "name LIKE ?", client.match("name", "?", base.LikeMatch))
+	assert.Equal(t, "LOWER(name) LIKE LOWER(?)", client.match("name", "?", base.ILikeMatch))
+	assert.Equal(t, "name REGEXP ?", client.match("name", "?", base.RegexMatch))
+	assert.PanicsWithError(t, "unsupported type: Array conditions are not supported by MySQL", func() {
+		_ = client.match("tags", "?", base.ArrayContainsMatch)
+	})
+	assert.PanicsWithError(t, "unsupported type: Array conditions are not supported by MySQL", func() {
+		_ = client.match("tags", "?", base.ArrayOverlapsMatch)
+	})
+}
+
+func TestMySQL_Begin(t *testing.T) {
+	original := beginTx
+	defer func() { beginTx = original }()
+
+	session := new(SQLDatabase)
+	tx := new(SQLTx)
+	tx.On("ExecContext", mock.Anything, "DELETE FROM users WHERE id = ?", 1).Return(nil, nil)
+	beginTx = beginTxMock(tx, nil)
+
+	client := initMySQL(session)
+	txClient, err := client.Begin()
+
+	assert.Nil(t, err)
+	assert.Nil(t, txClient.DeleteByID("users", base.Key{"id"}, 1))
+
+	txClient.Close()
+
+	session.AssertNotCalled(t, "ExecContext")
+	session.AssertNotCalled(t, "Close")
+}
+
+func TestMySQL_Close(t *testing.T) {
+	session := new(SQLDatabase)
+	session.On("Close").Return(nil)
+	client := initMySQL(session)
+	client.Close()
+
+	assert.Nil(t, client.session)
+}
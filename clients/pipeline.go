@@ -0,0 +1,162 @@
+package clients
+
+import (
+	"github.com/Kamva/octopus/base"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Pipeline is a builder of MongoDB aggregation pipelines. Stages are added
+// in order of calling their methods, and stages that have no method could
+// be added by `Stage`.
+type Pipeline struct {
+	parser func(conditions ...base.Condition) bson.M
+	stages []bson.M
+}
+
+// Match adds a `$match` stage that filters documents by `conditions`
+func (p *Pipeline) Match(conditions ...base.Condition) *Pipeline {
+	return p.Stage(bson.M{"$match": p.parser(conditions...)})
+}
+
+// Project adds a `$project` stage that reshapes documents by `fields`,
+// which values are 0 or 1 for excluding or including fields, or any
+// expression of aggregation pipeline for computing them.
+func (p *Pipeline) Project(fields bson.M) *Pipeline {
+	return p.Stage(bson.M{"$project": fields})
+}
+
+// Group adds a `$group` stage that groups documents by values of `fields`
+// and computes `aggregations` of each group. Grouping fields are projected
+// out of group `_id`, so documents of next stages have grouping fields
+// and aggregation aliases. All documents are aggregated in one document
+// if no grouping field is given.
+func (p *Pipeline) Group(fields []string, aggregations ...base.Aggregation) *Pipeline {
+	var id interface{}
+	project := bson.M{"_id": 0}
+	if len(fields) > 0 {
+		keys := make(bson.M, len(fields))
+		for _, field := range fields {
+			keys[field] = "$" + field
+			project[field] = "$_id." + field
+		}
+		id = keys
+	}
+
+	group := bson.M{"_id": id}
+	for _, aggregation := range aggregations {
+		group[aggregation.Alias] = aggregationOperator(aggregation)
+		project[aggregation.Alias] = 1
+	}
+
+	return p.Stage(bson.M{"$group": group}).Project(project)
+}
+
+// Sort adds a `$sort` stage that sorts documents by `sorts` in order
+func (p *Pipeline) Sort(sorts ...base.Sort) *Pipeline {
+	return p.Stage(bson.M{"$sort": sortDocument(sorts)})
+}
+
+// Skip adds a `$skip` stage that skips the first `n` documents
+func (p *Pipeline) Skip(n int) *Pipeline {
+	return p.Stage(bson.M{"$skip": n})
+}
+
+// Limit adds a `$limit` stage that passes only the first `n` documents
+func (p *Pipeline) Limit(n int) *Pipeline {
+	return p.Stage(bson.M{"$limit": n})
+}
+
+// Lookup adds a `$lookup` stage that joins documents of `from` collection
+// which `foreignField` equals to `localField` of document, as an array in
+// `as` field.
+func (p *Pipeline) Lookup(from string, localField string, foreignField string, as string) *Pipeline {
+	return p.Stage(bson.M{"$lookup": bson.M{
+		"from":         from,
+		"localField":   localField,
+		"foreignField": foreignField,
+		"as":           as,
+	}})
+}
+
+// Unwind adds an `$unwind` stage that outputs a document for each element
+// of `field` array. Documents which `field` is missing, null or an empty
+// array are kept if `preserveEmpty` is true.
+func (p *Pipeline) Unwind(field string, preserveEmpty bool) *Pipeline {
+	return p.Stage(bson.M{"$unwind": bson.M{
+		"path":                       "$" + field,
+		"preserveNullAndEmptyArrays": preserveEmpty,
+	}})
+}
+
+// Facet adds a `$facet` stage that runs each of `facets` pipelines on the
+// same documents, and outputs a single document with results of each
+// pipeline in a field named by its key.
+func (p *Pipeline) Facet(facets map[string]*Pipeline) *Pipeline {
+	fields := make(bson.M, len(facets))
+	for name, facet := range facets {
+		fields[name] = facet.Stages()
+	}
+
+	return p.Stage(bson.M{"$facet": fields})
+}
+
+// Bucket adds a `$bucket` stage that groups documents into buckets by value
+// of `field`, which bounds are given in ascending order by `boundaries`.
+// Documents out of boundaries are grouped in `defaultBucket` bucket if it's
+// not nil. Buckets have `count` of their documents if no aggregation is given.
+func (p *Pipeline) Bucket(
+	field string,
+	boundaries []interface{},
+	defaultBucket interface{},
+	aggregations ...base.Aggregation,
+) *Pipeline {
+	bucket := bson.M{"groupBy": "$" + field, "boundaries": boundaries}
+	if defaultBucket != nil {
+		bucket["default"] = defaultBucket
+	}
+
+	if len(aggregations) > 0 {
+		output := make(bson.M, len(aggregations))
+		for _, aggregation := range aggregations {
+			output[aggregation.Alias] = aggregationOperator(aggregation)
+		}
+		bucket["output"] = output
+	}
+
+	return p.Stage(bson.M{"$bucket": bucket})
+}
+
+// Stage adds `stage` to the pipeline as it is
+func (p *Pipeline) Stage(stage bson.M) *Pipeline {
+	p.stages = append(p.stages, stage)
+
+	return p
+}
+
+// Stages returns stages of the pipeline
+func (p *Pipeline) Stages() []bson.M {
+	if p.stages == nil {
+		return []bson.M{}
+	}
+
+	return p.stages
+}
+
+// NewPipeline instantiates an empty aggregation pipeline
+func NewPipeline() *Pipeline {
+	return &Pipeline{parser: new(MongoDB).parseConditions}
+}
+
+// sortDocument returns the ordered document of sorting by `sorts`
+func sortDocument(sorts []base.Sort) bson.D {
+	fields := make(bson.D, 0, len(sorts))
+	for _, sort := range sorts {
+		order := 1
+		if sort.Descending {
+			order = -1
+		}
+		fields = append(fields, bson.DocElem{Name: sort.Column, Value: order})
+	}
+
+	return fields
+}
@@ -0,0 +1,81 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/term"
+	"github.com/globalsign/mgo/bson"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Run("stages", func(t *testing.T) {
+		pipeline := NewPipeline().
+			Match(term.Equal{Field: "status", Value: "paid"}, term.GreaterThan{Field: "amount", Value: 0}).
+			Lookup("customers", "customer_id", "_id", "customer").
+			Unwind("customer", false).
+			Group([]string{"customer.country"}, base.Aggregation{Func: base.SumFunc, Field: "amount", Alias: "total"}).
+			Sort(base.Sort{Column: "total", Descending: true}, base.Sort{Column: "customer.country"}).
+			Skip(10).
+			Limit(5).
+			Project(bson.M{"total": 1}).
+			Stage(bson.M{"$out": "reports"})
+
+		assert.Equal(t, []bson.M{
+			{"$match": bson.M{"status": "paid", "amount": bson.M{"$gt": 0}}},
+			{"$lookup": bson.M{"from": "customers", "localField": "customer_id", "foreignField": "_id", "as": "customer"}},
+			{"$unwind": bson.M{"path": "$customer", "preserveNullAndEmptyArrays": false}},
+			{"$group": bson.M{"_id": bson.M{"customer.country": "$customer.country"}, "total": bson.M{"$sum": "$amount"}}},
+			{"$project": bson.M{"_id": 0, "customer.country": "$_id.customer.country", "total": 1}},
+			{"$sort": bson.D{{Name: "total", Value: -1}, {Name: "customer.country", Value: 1}}},
+			{"$skip": 10},
+			{"$limit": 5},
+			{"$project": bson.M{"total": 1}},
+			{"$out": "reports"},
+		}, pipeline.Stages())
+	})
+
+	t.Run("groupAll", func(t *testing.T) {
+		pipeline := NewPipeline().Group(nil, base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"})
+
+		assert.Equal(t, []bson.M{
+			{"$group": bson.M{"_id": nil, "count": bson.M{"$sum": 1}}},
+			{"$project": bson.M{"_id": 0, "count": 1}},
+		}, pipeline.Stages())
+	})
+
+	t.Run("facet", func(t *testing.T) {
+		pipeline := NewPipeline().Facet(map[string]*Pipeline{
+			"latest": NewPipeline().Sort(base.Sort{Column: "created_at", Descending: true}).Limit(3),
+			"total":  NewPipeline().Group(nil, base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"}),
+			"empty":  NewPipeline(),
+		})
+
+		assert.Equal(t, []bson.M{{"$facet": bson.M{
+			"latest": []bson.M{{"$sort": bson.D{{Name: "created_at", Value: -1}}}, {"$limit": 3}},
+			"total": []bson.M{
+				{"$group": bson.M{"_id": nil, "count": bson.M{"$sum": 1}}},
+				{"$project": bson.M{"_id": 0, "count": 1}},
+			},
+			"empty": []bson.M{},
+		}}}, pipeline.Stages())
+	})
+
+	t.Run("bucket", func(t *testing.T) {
+		pipeline := NewPipeline().
+			Bucket("age", []interface{}{0, 18, 65}, "other",
+				base.Aggregation{Func: base.AvgFunc, Field: "score", Alias: "avg_score"}).
+			Bucket("price", []interface{}{0, 100}, nil)
+
+		assert.Equal(t, []bson.M{
+			{"$bucket": bson.M{
+				"groupBy":    "$age",
+				"boundaries": []interface{}{0, 18, 65},
+				"default":    "other",
+				"output":     bson.M{"avg_score": bson.M{"$avg": "$score"}},
+			}},
+			{"$bucket": bson.M{"groupBy": "$price", "boundaries": []interface{}{0, 100}}},
+		}, pipeline.Stages())
+	})
+}
@@ -1,9 +1,11 @@
 package clients
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/Kamva/octopus/base"
@@ -17,12 +19,18 @@ import (
 // Postgres is the PostgreSQL client
 type Postgres struct {
 	session base.SQLDatabase
+	tx      base.SQLTx
+	ctx     context.Context
+
+	// borrowed is set if session is owned by another client, e.g.
+	// the pool client that this client is acquired from.
+	borrowed bool
 }
 
 // CreateTable creates `tableName` table with field and structure
 // defined in `structure` parameter for each table fields
 func (c *Postgres) CreateTable(tableName string, info base.TableInfo) error {
-	_, err := c.session.Exec(fmt.Sprintf(
+	_, err := c.executor().Exec(fmt.Sprintf(
 		"CREATE TABLE IF NOT EXISTS %s ( %s )",
 		tableName, info.GetInfo().(string),
 	))
@@ -59,7 +67,7 @@ func (c *Postgres) EnsureIndex(tableName string, index base.Index) error {
 		)
 	}
 
-	_, err := c.session.Exec(createQuery)
+	_, err := c.executor().Exec(createQuery)
 
 	return err
 }
@@ -67,42 +75,42 @@ func (c *Postgres) EnsureIndex(tableName string, index base.Index) error {
 // Insert tries to insert `data` into `tableName` and returns error if
 // anything went wrong. `data` should pass by reference to have exact
 // data on `tableName`, otherwise updated record data isn't accessible.
-func (c *Postgres) Insert(tableName string, data *base.RecordData) error {
-	strings.Join(data.GetColumns(), ", ")
+func (c *Postgres) Insert(tableName string, key base.Key, data *base.RecordData) error {
+	args := c.newArgs()
+	placeholders := args.bindAll(data.GetValues())
 
-	rows, err := queryDB(c.session, fmt.Sprintf(
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
 		"INSERT INTO %s (%s) VALUES (%s) RETURNING *",
 		tableName,
 		strings.Join(data.GetColumns(), ", "),
-		strings.Join(data.GetValues(c.enquoteValue), ", "),
-	))
+		strings.Join(placeholders, ", "),
+	), args.values...)
 
 	if err != nil {
 		return err
 	}
 
 	err = fetchSingleRecord(rows, data)
-
-	data.PruneData(func(recordMap *base.RecordMap) {
-		maps := *recordMap
-		for key, value := range maps {
-			if v, ok := value.([]uint8); ok {
-				(*recordMap)[key] = string(v)
-			}
-		}
-	})
+	data.PruneData(pruneBytes)
 
 	return err
 }
 
 // FindByID searches through `tableName` records to find a row that its
-// ID match with `id` and returns it alongside any possible error.
-func (c *Postgres) FindByID(tableName string, id interface{}) (base.RecordData, error) {
+// `key` primary key match with `id` and returns it alongside any
+// possible error.
+func (c *Postgres) FindByID(tableName string, key base.Key, id interface{}) (base.RecordData, error) {
 	data := *base.ZeroRecordData()
-	rows, err := queryDB(c.session, fmt.Sprintf(
-		"SELECT * FROM %s WHERE id = %v",
-		tableName, id,
-	))
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return data, err
+	}
+
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
 
 	if err != nil {
 		return data, err
@@ -118,64 +126,193 @@ func (c *Postgres) FindByID(tableName string, id interface{}) (base.RecordData,
 	return data, err
 }
 
-// UpdateByID finds a record in `tableName` that its ID match with `id`,
-// and updates it with data. It will return error if anything went wrong.
-func (c *Postgres) UpdateByID(tableName string, id interface{}, data base.RecordData) error {
-	updateQuery := prepareUpdate(data, c.enquoteValue)
-	_, err := c.session.Exec(fmt.Sprintf(
-		"UPDATE %s SET %s WHERE id = %v",
-		tableName, updateQuery, id,
-	))
+// UpdateByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and updates it with data. It will return error if
+// anything went wrong.
+func (c *Postgres) UpdateByID(tableName string, key base.Key, id interface{}, data base.RecordData) error {
+	args := c.newArgs()
+	updateQuery := prepareUpdate(data, args)
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		tableName, updateQuery, condition,
+	), args.values...)
 
 	return err
 }
 
-// DeleteByID finds a record in `tableName` that its ID match with `id`,
-// and remove it entirely. It will return error if anything went wrong.
-func (c *Postgres) DeleteByID(tableName string, id interface{}) error {
-	_, err := c.session.Exec(fmt.Sprintf(
-		"DELETE FROM %s WHERE id = %v",
-		tableName, id,
-	))
+// DeleteByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and remove it entirely. It will return error if
+// anything went wrong.
+func (c *Postgres) DeleteByID(tableName string, key base.Key, id interface{}) error {
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"DELETE FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
 
 	return err
 }
 
 // Query generates and returns sqlQuery object for further operations
 func (c *Postgres) Query(tableName string, conditions ...base.Condition) base.QueryBuilder {
-	return newSQLQuery(c.session, tableName, conditions, c.enquoteValue)
+	return newSQLQuery(c.executor(), tableName, conditions, c.placeholder, c.convertValue, c.match)
+}
+
+// Raw returns a query fetching records of raw SQL `query` with `args`.
+// Tables are named by the query, so `tableName` is ignored.
+func (c *Postgres) Raw(tableName string, query interface{}, args ...interface{}) base.RawQuery {
+	return newRawSQLQuery(c.executor(), query, args)
 }
 
-// Close disconnect session from database and release the taken memory
+// Exec executes raw SQL `command` with `args` and returns number of affected rows
+func (c *Postgres) Exec(command interface{}, args ...interface{}) (int, error) {
+	return execSQL(c.executor(), command, args)
+}
+
+// ConfigurePool applies connection pool settings of `config` on session
+func (c *Postgres) ConfigurePool(config base.PoolConfig) {
+	configureSQLPool(c.session, config)
+}
+
+// Acquire returns a client using connections of the session pool
+func (c *Postgres) Acquire() base.Client {
+	return &Postgres{session: c.session, ctx: c.ctx, borrowed: true}
+}
+
+// Begin starts a new transaction and returns a client bound to it,
+// which runs all of its operations in the transaction.
+func (c *Postgres) Begin() (base.TxClient, error) {
+	if c.tx != nil {
+		return nil, errNestedTransaction
+	}
+
+	tx, err := beginTx(c.session, orBackground(c.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Postgres{session: c.session, tx: tx, ctx: c.ctx, borrowed: true}, nil
+}
+
+// Commit commits the transaction that client is bound to
+func (c *Postgres) Commit() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Commit()
+}
+
+// Rollback aborts the transaction that client is bound to
+func (c *Postgres) Rollback() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Rollback()
+}
+
+// Close disconnect session from database and release the taken memory.
+// Borrowed sessions are left open for the client owning them.
 func (c *Postgres) Close() {
-	_ = c.session.Close()
+	if !c.borrowed {
+		_ = c.session.Close()
+	}
+
 	c.session = nil
+	c.tx = nil
+}
+
+// WithContext returns a copy of client which runs all of its statements
+// with `ctx`, so they are stopped when context is canceled or expired.
+func (c *Postgres) WithContext(ctx context.Context) base.Client {
+	client := *c
+	client.ctx = ctx
+
+	return &client
+}
+
+// Get the executor of statements bound to client context, which is the
+// transaction if client is bound to one, or the database session otherwise.
+func (c *Postgres) executor() base.SQLExecutor {
+	if c.tx != nil {
+		return withContext(c.tx, c.ctx)
+	}
+
+	return withContext(c.session, c.ctx)
 }
 
-// Enquote values to a proper presentation of their type in sql string
-func (c *Postgres) enquoteValue(i interface{}) string {
+// Generate the PostgreSQL bind parameter placeholder for nth argument
+func (c *Postgres) placeholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// Create a new argument list for a query using PostgreSQL placeholders
+func (c *Postgres) newArgs() *sqlArgs {
+	return newSQLArgs(c.placeholder, c.convertValue)
+}
+
+// Generate the PostgreSQL expression of matching field with pattern
+func (c *Postgres) match(field string, pattern string, mode base.MatchMode) string {
+	switch mode {
+	case base.ILikeMatch:
+		return fmt.Sprintf("%s ILIKE %s", field, pattern)
+	case base.RegexMatch:
+		return fmt.Sprintf("%s ~ %s", field, pattern)
+	case base.ArrayContainsMatch:
+		return fmt.Sprintf("%s @> %s", field, pattern)
+	case base.ArrayOverlapsMatch:
+		return fmt.Sprintf("%s && %s", field, pattern)
+	}
+
+	return fmt.Sprintf("%s LIKE %s", field, pattern)
+}
+
+// Convert values to a proper presentation of their type for pq driver
+func (c *Postgres) convertValue(i interface{}) interface{} {
+	i = encodeValue(i)
+	if i == nil {
+		return nil
+	}
+
+	if isDriverValue(i) {
+		return i
+	}
+
 	t := reflect.TypeOf(i)
 
 	switch t.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		reflect.Float32, reflect.Float64, reflect.Bool:
-		return fmt.Sprintf("%v", i)
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return i
+	case reflect.Uint64:
+		// uint64 values may overflow int64, so they are passed as
+		// string and database converts them to the DECIMAL column.
+		return strconv.FormatUint(reflect.ValueOf(i).Uint(), 10)
 	case reflect.Array, reflect.Slice:
-		return c.enquoteSliceValue(i)
+		return c.convertSliceValue(i)
 	case reflect.Map, reflect.Struct:
 		bytes, err := json.Marshal(i)
 		shark.PanicIfError(err)
-		return fmt.Sprintf("'%s'", string(bytes))
-	case reflect.String:
-		return fmt.Sprintf("'%s'", i.(string))
+		return string(bytes)
 	}
 
-	panic(fmt.Sprintf("Value with type of %s is not supported", t.Kind().String()))
+	panic(fmt.Errorf("%w: value with type of %s", base.ErrUnsupportedType, t.Kind().String()))
 }
 
-// Enquote arrays and slices to a proper presentation of their type in sql string
-func (c *Postgres) enquoteSliceValue(i interface{}) string {
+// Convert arrays and slices to PostgreSQL array literal
+func (c *Postgres) convertSliceValue(i interface{}) interface{} {
 	t := reflect.TypeOf(i).Elem()
 
 	tmp := make([]string, 0)
@@ -192,7 +329,7 @@ func (c *Postgres) enquoteSliceValue(i interface{}) string {
 			tmp = append(tmp, fmt.Sprintf("%v", item))
 		}
 
-		return fmt.Sprintf("'{%s}'", strings.Join(tmp, ","))
+		return fmt.Sprintf("{%s}", strings.Join(tmp, ","))
 	case reflect.Map, reflect.Struct:
 		data, _ := json.Marshal(i)
 		_ = json.Unmarshal(data, &slice)
@@ -200,19 +337,55 @@ func (c *Postgres) enquoteSliceValue(i interface{}) string {
 		for _, item := range slice {
 			bytes, err := json.Marshal(item)
 			shark.PanicIfError(err)
-			tmp = append(tmp, fmt.Sprintf("'%s'", string(bytes)))
+			tmp = append(tmp, quoteArrayElement(string(bytes)))
 		}
 
-		return fmt.Sprintf("array[%s]::json[]", strings.Join(tmp, ","))
+		return fmt.Sprintf("{%s}", strings.Join(tmp, ","))
 	case reflect.String:
-		for _, item := range i.([]string) {
-			tmp = append(tmp, fmt.Sprintf("\"%s\"", item))
+		v := reflect.ValueOf(i)
+		for j := 0; j < v.Len(); j++ {
+			tmp = append(tmp, quoteArrayElement(v.Index(j).String()))
 		}
 
-		return fmt.Sprintf("'{%s}'", strings.Join(tmp, ","))
+		return fmt.Sprintf("{%s}", strings.Join(tmp, ","))
+	case reflect.Interface:
+		v := reflect.ValueOf(i)
+		for j := 0; j < v.Len(); j++ {
+			tmp = append(tmp, c.convertArrayElement(v.Index(j).Interface()))
+		}
+
+		return fmt.Sprintf("{%s}", strings.Join(tmp, ","))
+	}
+
+	panic(fmt.Errorf("%w: value with type of []%s", base.ErrUnsupportedType, t.Kind().String()))
+}
+
+// Convert an element of a slice with mixed element types (e.g. values of
+// array conditions) to its presentation in PostgreSQL array literal.
+func (c *Postgres) convertArrayElement(i interface{}) string {
+	if i == nil {
+		return "NULL"
+	}
+
+	switch reflect.TypeOf(i).Kind() {
+	case reflect.String:
+		return quoteArrayElement(reflect.ValueOf(i).String())
+	case reflect.Map, reflect.Struct:
+		bytes, err := json.Marshal(i)
+		shark.PanicIfError(err)
+		return quoteArrayElement(string(bytes))
 	}
 
-	panic(fmt.Sprintf("Value with type of []%s is not supported", t.Kind().String()))
+	return fmt.Sprintf("%v", i)
+}
+
+// quoteArrayElement double quotes an element of PostgreSQL array
+// literal and escapes the quotes and backslashes inside it.
+func quoteArrayElement(element string) string {
+	element = strings.Replace(element, `\`, `\\`, -1)
+	element = strings.Replace(element, `"`, `\"`, -1)
+
+	return `"` + element + `"`
 }
 
 // NewPostgres instantiate and return a new PostgreSQL session object
@@ -1,7 +1,13 @@
 package clients
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/Kamva/octopus/base"
 	. "github.com/Kamva/octopus/clients/internal"
@@ -27,6 +33,26 @@ func initPostgres(session base.SQLDatabase) *Postgres {
 	return &Postgres{session: session}
 }
 
+// point is a custom type which is stored as text by pointCodec
+type point struct {
+	X, Y int
+}
+
+type pointCodec struct{}
+
+func (pointCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return "POINT"
+}
+
+func (pointCodec) Encode(value interface{}) (interface{}, error) {
+	p := value.(point)
+	return fmt.Sprintf("(%d,%d)", p.X, p.Y), nil
+}
+
+func (pointCodec) Decode(value interface{}, field reflect.Value) error {
+	return nil
+}
+
 // ----------------
 //    Unit Tests
 // ----------------
@@ -72,7 +98,7 @@ func TestPostgres_CreateTable(t *testing.T) {
 			"age INT NULL, " +
 			"status BOOLEAN DEFAULT TRUE )"
 
-		session.On("Exec", createQuery).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, createQuery).Return(nil, nil)
 
 		client := initPostgres(session)
 		err := client.CreateTable("users", getPGTableStructure())
@@ -83,7 +109,7 @@ func TestPostgres_CreateTable(t *testing.T) {
 	t.Run("dbExecError", func(t *testing.T) {
 		session := new(SQLDatabase)
 
-		session.On("Exec", mock.AnythingOfType("string")).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, mock.AnythingOfType("string")).Return(nil, errTest)
 
 		client := initPostgres(session)
 		err := client.CreateTable("users", getSQLTableStructure())
@@ -98,7 +124,7 @@ func TestPostgres_EnsureIndex(t *testing.T) {
 
 		query := "CREATE INDEX IF NOT EXISTS name_index ON users (name)"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query).Return(nil, nil)
 
 		client := initPostgres(session)
 		err := client.EnsureIndex("users", base.Index{
@@ -113,7 +139,7 @@ func TestPostgres_EnsureIndex(t *testing.T) {
 
 		query := "CREATE INDEX IF NOT EXISTS name_email_index ON users (name, email)"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query).Return(nil, nil)
 
 		client := initPostgres(session)
 		err := client.EnsureIndex("users", base.Index{
@@ -128,7 +154,7 @@ func TestPostgres_EnsureIndex(t *testing.T) {
 
 		query := "CREATE UNIQUE INDEX IF NOT EXISTS name_unique_index ON users (name)"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query).Return(nil, nil)
 
 		client := initPostgres(session)
 		err := client.EnsureIndex("users", base.Index{
@@ -144,7 +170,7 @@ func TestPostgres_EnsureIndex(t *testing.T) {
 
 		query := "CREATE UNIQUE INDEX IF NOT EXISTS name_email_unique_index ON users (name, email)"
 
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query).Return(nil, nil)
 
 		client := initPostgres(session)
 		err := client.EnsureIndex("users", base.Index{
@@ -158,7 +184,7 @@ func TestPostgres_EnsureIndex(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		session := new(SQLDatabase)
 
-		session.On("Exec", mock.AnythingOfType("string")).
+		session.On("ExecContext", mock.Anything, mock.AnythingOfType("string")).
 			Return(nil, errTest)
 
 		client := initPostgres(session)
@@ -175,11 +201,12 @@ func TestPostgres_Insert(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "INSERT INTO users (name, age, status) VALUES ('Test', 5, true) RETURNING *"
+		query := "INSERT INTO users (name, age, status) VALUES ($1, $2, $3) RETURNING *"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, "Test", 5, true).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
@@ -203,7 +230,7 @@ func TestPostgres_Insert(t *testing.T) {
 			[]string{"name", "age", "status"},
 			base.RecordMap{"name": "Test", "age": 5, "status": true},
 		)
-		err := client.Insert("users", data)
+		err := client.Insert("users", base.Key{"id"}, data)
 
 		assert.Nil(t, err)
 
@@ -218,11 +245,12 @@ func TestPostgres_Insert(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		query := "INSERT INTO users (number_slice, map_slice, string_slice, json) VALUES " +
-			"('{2,3,5,7}', array['{\"a\":\"b\"}','{\"c\":\"d\"}']::json[], '{\"a\",\"b\"}', '{\"e\":\"f\"}') RETURNING *"
+			"($1, $2, $3, $4) RETURNING *"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, "{2,3,5,7}", `{"{\"a\":\"b\"}","{\"c\":\"d\"}"}`, `{"a","b"}`, `{"e":"f"}`).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
@@ -254,7 +282,7 @@ func TestPostgres_Insert(t *testing.T) {
 				"json":         map[string]string{"e": "f"},
 			},
 		)
-		err := client.Insert("users", data)
+		err := client.Insert("users", base.Key{"id"}, data)
 
 		assert.Nil(t, err)
 
@@ -275,7 +303,7 @@ func TestPostgres_Insert(t *testing.T) {
 			},
 		)
 		assert.Panics(t, func() {
-			_ = client.Insert("users", data)
+			_ = client.Insert("users", base.Key{"id"}, data)
 		})
 	})
 
@@ -289,7 +317,7 @@ func TestPostgres_Insert(t *testing.T) {
 			},
 		)
 		assert.Panics(t, func() {
-			_ = client.Insert("users", data)
+			_ = client.Insert("users", base.Key{"id"}, data)
 		})
 	})
 
@@ -297,11 +325,12 @@ func TestPostgres_Insert(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "INSERT INTO users (name, age, status) VALUES ('Test', 5, true) RETURNING *"
+		query := "INSERT INTO users (name, age, status) VALUES ($1, $2, $3) RETURNING *"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, errTest)
+		session.On("QueryContext", mock.Anything, query, "Test", 5, true).Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initPostgres(session)
@@ -310,7 +339,7 @@ func TestPostgres_Insert(t *testing.T) {
 			[]string{"name", "age", "status"},
 			base.RecordMap{"name": "Test", "age": 5, "status": true},
 		)
-		err := client.Insert("users", data)
+		err := client.Insert("users", base.Key{"id"}, data)
 
 		assert.NotNil(t, err)
 	})
@@ -321,11 +350,12 @@ func TestPostgres_FindByID(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "SELECT * FROM users WHERE id = 1"
+		query := "SELECT * FROM users WHERE id = $1"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(
 			[]string{"id", "name", "rate", "available"},
@@ -343,7 +373,7 @@ func TestPostgres_FindByID(t *testing.T) {
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initPostgres(session)
-		data, err := client.FindByID("users", 1)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
 
 		assert.Nil(t, err)
 
@@ -357,16 +387,18 @@ func TestPostgres_FindByID(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "SELECT * FROM users WHERE id = 1"
+		query := "SELECT * FROM users WHERE id = $1"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, nil)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initPostgres(session)
-		data, err := client.FindByID("users", 1)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, *base.ZeroRecordData(), data)
@@ -376,15 +408,16 @@ func TestPostgres_FindByID(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		query := "SELECT * FROM users WHERE id = 1"
+		query := "SELECT * FROM users WHERE id = $1"
 
 		session := new(SQLDatabase)
-		session.On("Query", query).Return(nil, errTest)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		queryDB = queryDBMock(session, query, rows)
 		client := initPostgres(session)
-		data, err := client.FindByID("users", 1)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, *base.ZeroRecordData(), data)
@@ -393,59 +426,105 @@ func TestPostgres_FindByID(t *testing.T) {
 
 func TestPostgres_UpdateByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		query := "UPDATE users SET name = 'Updated Test', available = false WHERE id = 1"
+		query := "UPDATE users SET name = $1, available = $2 WHERE id = $3"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, "Updated Test", false, 1).Return(nil, nil)
 
 		client := initPostgres(session)
 		data := base.NewRecordData(
 			[]string{"name", "available"},
 			base.RecordMap{"name": "Updated Test", "available": false},
 		)
-		err := client.UpdateByID("users", 1, *data)
+		err := client.UpdateByID("users", base.Key{"id"}, 1, *data)
 
 		assert.Nil(t, err)
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		query := "UPDATE users SET name = 'Updated Test', rate = 9.1 WHERE id = 1"
+		query := "UPDATE users SET name = $1, rate = $2 WHERE id = $3"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, query, "Updated Test", 9.1, 1).Return(nil, errTest)
 
 		client := initPostgres(session)
 		data := base.NewRecordData(
 			[]string{"name", "rate"},
 			base.RecordMap{"name": "Updated Test", "rate": 9.1},
 		)
-		err := client.UpdateByID("users", 1, *data)
+		err := client.UpdateByID("users", base.Key{"id"}, 1, *data)
 
 		assert.NotNil(t, err)
 	})
 }
 
+func TestPostgres_customKey(t *testing.T) {
+	session := new(SQLDatabase)
+	session.On("QueryContext", mock.Anything, "SELECT * FROM users WHERE user_id = $1", 1).Return(nil, errTest)
+	session.On("ExecContext", mock.Anything, "UPDATE users SET name = $1 WHERE user_id = $2", "Test", 1).Return(nil, nil)
+	session.On("ExecContext", mock.Anything, "DELETE FROM users WHERE user_id = $1", 1).Return(nil, nil)
+
+	client := initPostgres(session)
+	data := base.NewRecordData([]string{"name"}, base.RecordMap{"name": "Test"})
+	_, err := client.FindByID("users", base.Key{"user_id"}, 1)
+
+	assert.Equal(t, errTest, err)
+	assert.Nil(t, client.UpdateByID("users", base.Key{"user_id"}, 1, *data))
+	assert.Nil(t, client.DeleteByID("users", base.Key{"user_id"}, 1))
+	session.AssertExpectations(t)
+}
+
+func TestPostgres_compositeKey(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		key := base.Key{"user_id", "group_id"}
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, "SELECT * FROM members WHERE user_id = $1 AND group_id = $2", 1, 2).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, "UPDATE members SET role = $1 WHERE user_id = $2 AND group_id = $3", "admin", 1, 2).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, "DELETE FROM members WHERE user_id = $1 AND group_id = $2", 1, 2).Return(nil, nil)
+
+		client := initPostgres(session)
+		data := base.NewRecordData([]string{"role"}, base.RecordMap{"role": "admin"})
+		_, err := client.FindByID("members", key, []interface{}{1, 2})
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, client.UpdateByID("members", key, []interface{}{1, 2}, *data))
+		assert.Nil(t, client.DeleteByID("members", key, []interface{}{1, 2}))
+		session.AssertExpectations(t)
+	})
+
+	t.Run("invalidID", func(t *testing.T) {
+		key := base.Key{"user_id", "group_id"}
+		client := initPostgres(new(SQLDatabase))
+
+		_, err := client.FindByID("members", key, 1)
+		assert.True(t, errors.Is(err, base.ErrInvalidID))
+
+		err = client.DeleteByID("members", key, []interface{}{1})
+		assert.True(t, errors.Is(err, base.ErrInvalidID))
+	})
+}
+
 func TestPostgres_DeleteByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		query := "DELETE FROM users WHERE id = 1"
+		query := "DELETE FROM users WHERE id = $1"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, nil)
+		session.On("ExecContext", mock.Anything, query, 1).Return(nil, nil)
 
 		client := initPostgres(session)
-		err := client.DeleteByID("users", 1)
+		err := client.DeleteByID("users", base.Key{"id"}, 1)
 
 		assert.Nil(t, err)
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		query := "DELETE FROM users WHERE id = 1"
+		query := "DELETE FROM users WHERE id = $1"
 
 		session := new(SQLDatabase)
-		session.On("Exec", query).Return(nil, errTest)
+		session.On("ExecContext", mock.Anything, query, 1).Return(nil, errTest)
 
 		client := initPostgres(session)
-		err := client.DeleteByID("users", 1)
+		err := client.DeleteByID("users", base.Key{"id"}, 1)
 
 		assert.NotNil(t, err)
 	})
@@ -468,6 +547,152 @@ func TestPostgres_Query(t *testing.T) {
 	assert.Equal(t, "users", q.table)
 }
 
+func TestPostgres_match(t *testing.T) {
+	client := initPostgres(new(SQLDatabase))
+
+	assert.Equal(t, "name LIKE $1", client.match("name", "$1", base.LikeMatch))
+	assert.Equal(t, "name ILIKE $1", client.match("name", "$1", base.ILikeMatch))
+	assert.Equal(t, "name ~ $1", client.match("name", "$1", base.RegexMatch))
+	assert.Equal(t, "name @> $1", client.match("name", "$1", base.ArrayContainsMatch))
+	assert.Equal(t, "name && $1", client.match("name", "$1", base.ArrayOverlapsMatch))
+}
+
+func TestPostgres_ConfigurePool(t *testing.T) {
+	session := new(SQLDatabase)
+	session.On("SetMaxOpenConns", 10).Return()
+	session.On("SetConnMaxLifetime", time.Minute).Return()
+
+	client := initPostgres(session)
+	client.ConfigurePool(base.PoolConfig{MaxOpenConns: 10, ConnMaxLifetime: time.Minute, PoolLimit: 5})
+
+	session.AssertExpectations(t)
+	session.AssertNotCalled(t, "SetMaxIdleConns", mock.Anything)
+}
+
+func TestPostgres_Acquire(t *testing.T) {
+	session := new(SQLDatabase)
+	client := initPostgres(session)
+
+	acquired := client.Acquire().(*Postgres)
+	acquired.Close()
+
+	assert.Nil(t, acquired.session)
+	session.AssertNotCalled(t, "Close")
+}
+
+func TestPostgres_WithContext(t *testing.T) {
+	t.Run("session", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		query := "DELETE FROM users WHERE id = $1"
+		session := new(SQLDatabase)
+		session.On("ExecContext", ctx, query, 1).Return(nil, nil)
+
+		client := initPostgres(session)
+		bound := client.WithContext(ctx).(*Postgres)
+		err := bound.DeleteByID("users", base.Key{"id"}, 1)
+
+		assert.Nil(t, err)
+		assert.Nil(t, client.ctx)
+		session.AssertExpectations(t)
+	})
+
+	t.Run("transaction", func(t *testing.T) {
+		original := beginTx
+		defer func() { beginTx = original }()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		query := "DELETE FROM users WHERE id = $1"
+		session := new(SQLDatabase)
+		tx := new(SQLTx)
+		tx.On("ExecContext", ctx, query, 1).Return(nil, nil)
+		beginTx = func(db base.SQLDatabase, c context.Context) (base.SQLTx, error) {
+			assert.Equal(t, ctx, c)
+			return tx, nil
+		}
+
+		client := initPostgres(session)
+		txClient, err := client.WithContext(ctx).(*Postgres).Begin()
+		assert.Nil(t, err)
+
+		err = txClient.DeleteByID("users", base.Key{"id"}, 1)
+
+		assert.Nil(t, err)
+		tx.AssertExpectations(t)
+		session.AssertNotCalled(t, "ExecContext")
+	})
+}
+
+func TestPostgres_Begin(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		original := beginTx
+		defer func() { beginTx = original }()
+
+		session := new(SQLDatabase)
+		tx := new(SQLTx)
+		beginTx = beginTxMock(tx, nil)
+
+		client := initPostgres(session)
+		txClient, err := client.Begin()
+
+		assert.Nil(t, err)
+		assert.Equal(t, session, txClient.(*Postgres).session)
+		assert.Equal(t, tx, txClient.(*Postgres).tx)
+		assert.True(t, txClient.(*Postgres).borrowed)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		original := beginTx
+		defer func() { beginTx = original }()
+
+		beginTx = beginTxMock(nil, errTest)
+
+		client := initPostgres(new(SQLDatabase))
+		txClient, err := client.Begin()
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, txClient)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		client := &Postgres{session: new(SQLDatabase), tx: new(SQLTx)}
+		_, err := client.Begin()
+
+		assert.Equal(t, errNestedTransaction, err)
+	})
+}
+
+func TestPostgres_transaction(t *testing.T) {
+	session := new(SQLDatabase)
+	tx := new(SQLTx)
+	tx.On("ExecContext", mock.Anything, "DELETE FROM users WHERE id = $1", 1).Return(nil, nil)
+	tx.On("Commit").Return(nil)
+	tx.On("Rollback").Return(errTest)
+
+	client := &Postgres{session: session, tx: tx, borrowed: true}
+
+	assert.Nil(t, client.DeleteByID("users", base.Key{"id"}, 1))
+	assert.Nil(t, client.Commit())
+	assert.Equal(t, errTest, client.Rollback())
+
+	client.Close()
+
+	assert.Nil(t, client.session)
+	assert.Nil(t, client.tx)
+	session.AssertNotCalled(t, "ExecContext")
+	session.AssertNotCalled(t, "Close")
+}
+
+func TestPostgres_noTransaction(t *testing.T) {
+	client := initPostgres(new(SQLDatabase))
+
+	assert.Equal(t, errNoTransaction, client.Commit())
+	assert.Equal(t, errNoTransaction, client.Rollback())
+}
+
 func TestPostgres_Close(t *testing.T) {
 	session := new(SQLDatabase)
 	session.On("Close").Return(nil)
@@ -476,3 +701,46 @@ func TestPostgres_Close(t *testing.T) {
 
 	assert.Nil(t, client.session)
 }
+
+func TestPostgres_convertValue(t *testing.T) {
+	client := initPostgres(new(SQLDatabase))
+
+	t.Run("quotedString", func(t *testing.T) {
+		assert.Equal(t, "O'Neil", client.convertValue("O'Neil"))
+	})
+
+	t.Run("bigUnsigned", func(t *testing.T) {
+		assert.Equal(t, "18446744073709551615", client.convertValue(uint64(18446744073709551615)))
+	})
+
+	t.Run("stringSlice", func(t *testing.T) {
+		assert.Equal(t, `{"a\"b","c\\d"}`, client.convertValue([]string{`a"b`, `c\d`}))
+	})
+
+	t.Run("interfaceSlice", func(t *testing.T) {
+		assert.Equal(
+			t,
+			`{"go",1,true,NULL,"{\"key\":\"value\"}"}`,
+			client.convertValue([]interface{}{"go", 1, true, nil, map[string]string{"key": "value"}}),
+		)
+	})
+
+	t.Run("driverValues", func(t *testing.T) {
+		now := time.Now()
+		name := sql.NullString{String: "Test", Valid: true}
+
+		assert.Equal(t, now, client.convertValue(now))
+		assert.Equal(t, name, client.convertValue(name))
+	})
+
+	t.Run("codec", func(t *testing.T) {
+		base.RegisterCodec(reflect.TypeOf(point{}), pointCodec{})
+
+		assert.Equal(t, "(1,2)", client.convertValue(point{X: 1, Y: 2}))
+		assert.Equal(t, []interface{}{"(1,2)", "(3,4)"}, encodeValue([]interface{}{point{1, 2}, point{3, 4}}))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, client.convertValue(nil))
+	})
+}
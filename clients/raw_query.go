@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"fmt"
+
+	"github.com/Kamva/octopus/base"
+)
+
+// rawSQLQuery is a raw SQL query, which records are fetched and converted
+// the same as records of generated queries.
+type rawSQLQuery struct {
+	session base.SQLExecutor
+	query   string
+	args    []interface{}
+	pruner  base.Pruner
+}
+
+// First fetch data of the first record resulted from the query
+func (q *rawSQLQuery) First() (base.RecordData, error) {
+	data := base.ZeroRecordData()
+	rows, err := queryDB(q.session, q.query, q.args...)
+	if err != nil {
+		return *data, err
+	}
+
+	err = fetchSingleRecord(rows, data)
+	if err == nil && q.pruner != nil {
+		data.PruneData(q.pruner)
+	}
+
+	return *data, err
+}
+
+// All returns all records resulted from the query in RecordDataSet format
+func (q *rawSQLQuery) All() (base.RecordDataSet, error) {
+	rows, err := queryDB(q.session, q.query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := fetchResults(rows)
+	if err == nil && q.pruner != nil {
+		for i := range results {
+			results[i].PruneData(q.pruner)
+		}
+	}
+
+	return results, err
+}
+
+func newRawSQLQuery(session base.SQLExecutor, query interface{}, args []interface{}) *rawSQLQuery {
+	return &rawSQLQuery{session: session, query: sqlStatement(query), args: args}
+}
+
+// execSQL executes raw SQL `command` with `args` and returns number of
+// affected rows.
+func execSQL(session base.SQLExecutor, command interface{}, args []interface{}) (int, error) {
+	res, err := session.Exec(sqlStatement(command), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+
+	return int(rowsAffected), nil
+}
+
+// sqlStatement returns the raw SQL `statement`, which should be a string
+func sqlStatement(statement interface{}) string {
+	if query, ok := statement.(string); ok {
+		return query
+	}
+
+	panic(fmt.Errorf("%w: raw SQL statement should be a string, got %T", base.ErrUnsupportedType, statement))
+}
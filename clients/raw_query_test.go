@@ -0,0 +1,146 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRawSQLQuery_First(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		sqlQuery := "SELECT id, name FROM players WHERE rate > $1 ORDER BY rate DESC"
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery, 8.5).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return([]string{"id", "name"}, nil)
+		rows.On("Scan", mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*interface{}) = 1
+				*args.Get(1).(*interface{}) = []byte("Test")
+			})
+
+		queryDB = queryDBMock(session, sqlQuery, rows)
+		query := newRawSQLQuery(session, sqlQuery, []interface{}{8.5})
+		query.pruner = pruneBytes
+		data, err := query.First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, data.Get("id"))
+		assert.Equal(t, "Test", data.Get("name"))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		sqlQuery := "SELECT * FROM players WHERE id = $1"
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery, 5).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
+
+		queryDB = queryDBMock(session, sqlQuery, rows)
+		_, err := newRawSQLQuery(session, sqlQuery, []interface{}{5}).First()
+
+		assert.Equal(t, base.ErrNotFound, err)
+	})
+
+	t.Run("queryError", func(t *testing.T) {
+		sqlQuery := "SELECT * FROM players"
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery).Return(nil, errTest)
+
+		_, err := newRawSQLQuery(session, sqlQuery, nil).First()
+
+		assert.Equal(t, errTest, err)
+	})
+}
+
+func TestRawSQLQuery_All(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		sqlQuery := "SELECT * FROM players"
+		limit := 3
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.SetLimit(limit)
+		rows.On("Err").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return(columns, nil)
+		args := make([]interface{}, 0, 11)
+		for i := 0; i < 11; i++ {
+			args = append(args, mock.Anything)
+		}
+		rows.On("Scan", args...).Return(nil).Run(recordGenerator)
+
+		queryDB = queryDBMock(session, sqlQuery, rows)
+		results, err := newRawSQLQuery(session, sqlQuery, nil).All()
+
+		assert.Nil(t, err)
+		assert.Equal(t, limit, len(results))
+	})
+
+	t.Run("queryError", func(t *testing.T) {
+		sqlQuery := "SELECT * FROM players"
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery).Return(nil, errTest)
+
+		results, err := newRawSQLQuery(session, sqlQuery, nil).All()
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, results)
+	})
+
+	t.Run("invalidQuery", func(t *testing.T) {
+		assert.PanicsWithError(t, "unsupported type: raw SQL statement should be a string, got int", func() {
+			newRawSQLQuery(new(SQLDatabase), 1, nil)
+		})
+	})
+}
+
+func TestExecSQL(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		sqlQuery := "UPDATE players SET rate = rate + 1 WHERE team = ?"
+
+		session := new(SQLDatabase)
+		session.On("Exec", sqlQuery, "Chelsea").Return(result{4}, nil)
+
+		n, err := execSQL(session, sqlQuery, []interface{}{"Chelsea"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 4, n)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		sqlQuery := "DELETE FROM players"
+
+		session := new(SQLDatabase)
+		session.On("Exec", sqlQuery).Return(result{}, errTest)
+
+		n, err := execSQL(session, sqlQuery, nil)
+
+		assert.True(t, errors.Is(err, errTest))
+		assert.Equal(t, 0, n)
+	})
+}
@@ -10,17 +10,44 @@ import (
 
 // sqlQuery is a struct containing information about sqlQuery
 type sqlQuery struct {
-	session    base.SQLDatabase
-	table      string
-	conditions []base.Condition
-	enquoter   base.Enquoter
-	sorts      []base.Sort
-	limit      int
-	offset     int
+	session      base.SQLExecutor
+	table        string
+	conditions   []base.Condition
+	placeholder  base.Placeholder
+	converter    base.Converter
+	matcher      base.Matcher
+	paginator    base.Paginator
+	pruner       base.Pruner
+	sorts        []base.Sort
+	limit        int
+	offset       int
+	columns      []string
+	omitted      []string
+	tableColumns []string
+	groups       []string
+	having       []base.Condition
+
+	// aliases maps aliases of aggregations to their expression while
+	// HAVING clause is parsed, as aliases are not valid in it.
+	aliases map[string]string
 }
 
-func newSQLQuery(session base.SQLDatabase, table string, conditions []base.Condition, enquoter base.Enquoter) *sqlQuery {
-	return &sqlQuery{session: session, table: table, conditions: conditions, enquoter: enquoter}
+func newSQLQuery(
+	session base.SQLExecutor,
+	table string,
+	conditions []base.Condition,
+	placeholder base.Placeholder,
+	converter base.Converter,
+	matcher base.Matcher,
+) *sqlQuery {
+	return &sqlQuery{
+		session:     session,
+		table:       table,
+		conditions:  conditions,
+		placeholder: placeholder,
+		converter:   converter,
+		matcher:     matcher,
+	}
 }
 
 // OrderBy set the order of returning result in following command
@@ -45,23 +72,137 @@ func (q *sqlQuery) Skip(n int) base.QueryBuilder {
 	return q
 }
 
+// Select limits columns fetched by the following fetch command to `fields`
+func (q *sqlQuery) Select(fields ...string) base.QueryBuilder {
+	q.columns = fields
+
+	return q
+}
+
+// Omit excludes `fields` from columns fetched by the following fetch
+// command. Other columns of table are selected if they are bound, and
+// otherwise omitted columns are removed from fetched records.
+func (q *sqlQuery) Omit(fields ...string) base.QueryBuilder {
+	q.omitted = fields
+
+	return q
+}
+
+// BindColumns sets columns of the queried table, which are selected except
+// the omitted ones.
+func (q *sqlQuery) BindColumns(columns []string) {
+	q.tableColumns = columns
+}
+
+// GroupBy groups records by values of `fields` in the following aggregate
+// command.
+func (q *sqlQuery) GroupBy(fields ...string) base.QueryBuilder {
+	q.groups = fields
+
+	return q
+}
+
+// Having filters groups of the following aggregate command by `conditions`,
+// which fields could be aliases of aggregations.
+func (q *sqlQuery) Having(conditions ...base.Condition) base.QueryBuilder {
+	q.having = conditions
+
+	return q
+}
+
 // Count execute a count command that will return the number records in
 // specified destination table. If the query conditions was empty, it
 // returns number of all records un destination table.
 func (q *sqlQuery) Count() (int, error) {
-	data := base.NewRecordData([]string{"count"}, map[string]interface{}{"count": 0})
+	return q.count("COUNT(*)")
+}
+
+// CountDistinct execute a count command that will return the number of
+// distinct non-null values of `field` in records matching the query
+// conditions.
+func (q *sqlQuery) CountDistinct(field string) (int, error) {
+	return q.count(fmt.Sprintf("COUNT(DISTINCT %s)", field))
+}
+
+// Distinct returns distinct values of `field` in records matching the query
+// conditions, in order of query sorts.
+func (q *sqlQuery) Distinct(field string) ([]interface{}, error) {
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
+	optionClause := q.parseOptions()
+
+	rows, err := queryDB(q.session, strings.TrimRight(fmt.Sprintf(
+		"SELECT DISTINCT %s FROM %s%s %s", field, q.table, whereClause, optionClause,
+	), " "), args.values...)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := fetchResults(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0, len(results))
+	for _, result := range results {
+		if q.pruner != nil {
+			result.PruneData(q.pruner)
+		}
+		values = append(values, result.Get(field))
+	}
+
+	return values, nil
+}
+
+// Aggregate returns a row of grouping columns and `aggregations` for each
+// group of records matching the query conditions. All records matching the
+// conditions are aggregated in one row if no grouping column is set.
+func (q *sqlQuery) Aggregate(aggregations ...base.Aggregation) (base.RecordDataSet, error) {
+	columns := make([]string, 0, len(q.groups)+len(aggregations))
+	columns = append(columns, q.groups...)
+	for _, aggregation := range aggregations {
+		columns = append(columns, fmt.Sprintf("%s AS %s", aggregation.Expression(), aggregation.Alias))
+	}
+
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
+	groupClause := q.parseGroup(aggregations, args)
+	optionClause := q.parseOptions()
+
+	rows, err := queryDB(q.session, strings.TrimRight(fmt.Sprintf(
+		"SELECT %s FROM %s%s%s %s", strings.Join(columns, ", "), q.table, whereClause, groupClause, optionClause,
+	), " "), args.values...)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := fetchResults(rows)
+	if err == nil && q.pruner != nil {
+		for i := range results {
+			results[i].PruneData(q.pruner)
+		}
+	}
+
+	return results, err
+}
+
+// Exists checks whether any record matches with the query conditions
+func (q *sqlQuery) Exists() (bool, error) {
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
 
 	rows, err := queryDB(q.session, fmt.Sprintf(
-		"SELECT COUNT(*) AS count FROM %s", q.table,
-	))
+		"SELECT CASE WHEN EXISTS (SELECT 1 FROM %s%s) THEN 1 ELSE 0 END AS count",
+		q.table, whereClause,
+	), args.values...)
 
 	if err != nil {
-		return data.Get("count").(int), err
+		return false, err
 	}
 
-	err = fetchSingleRecord(rows, data)
+	n, err := fetchCount(rows)
 
-	return data.Get("count").(int), err
+	return n > 0, err
 }
 
 // All returns results that match with sqlQuery conditions in RecordDataSet
@@ -69,44 +210,53 @@ func (q *sqlQuery) Count() (int, error) {
 // in specified destination table or error if anything went wrong.
 // It will panic if no destination table was set before call All.
 func (q *sqlQuery) All() (base.RecordDataSet, error) {
-	whereClause := q.parseWhere()
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
 	optionClause := q.parseOptions()
-	var query string
-
-	if whereClause != "" {
-		query = strings.TrimRight(fmt.Sprintf(
-			"SELECT * FROM %s WHERE %s %s", q.table, whereClause, optionClause,
-		), " ")
-	} else {
-		query = strings.TrimRight(fmt.Sprintf(
-			"SELECT * FROM %s %s", q.table, optionClause,
-		), " ")
-	}
 
-	rows, err := queryDB(q.session, query)
+	rows, err := queryDB(q.session, strings.TrimRight(fmt.Sprintf(
+		"SELECT %s FROM %s%s %s", q.parseColumns(), q.table, whereClause, optionClause,
+	), " "), args.values...)
 	if err != nil {
 		return nil, err
 	}
 
-	return fetchResults(rows)
+	results, err := fetchResults(rows)
+	if err == nil {
+		for i := range results {
+			omitColumns(&results[i], q.omitted)
+			if q.pruner != nil {
+				results[i].PruneData(q.pruner)
+			}
+		}
+	}
+
+	return results, err
 }
 
 // First fetch data of the first record that match with sqlQuery conditions.
 func (q *sqlQuery) First() (base.RecordData, error) {
-	whereClause := q.parseWhere()
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
 	q.limit = 1
 	optionClause := q.parseOptions()
 
 	data := base.ZeroRecordData()
 	rows, err := queryDB(q.session, strings.TrimRight(fmt.Sprintf(
-		"SELECT * FROM %s WHERE %s %s", q.table, whereClause, optionClause,
-	), " "))
+		"SELECT %s FROM %s%s %s", q.parseColumns(), q.table, whereClause, optionClause,
+	), " "), args.values...)
 
 	if err != nil {
 		return *data, err
 	}
 
 	err = fetchSingleRecord(rows, data)
+	if err == nil {
+		omitColumns(data, q.omitted)
+		if q.pruner != nil {
+			data.PruneData(q.pruner)
+		}
+	}
 
 	return *data, err
 }
@@ -114,18 +264,23 @@ func (q *sqlQuery) First() (base.RecordData, error) {
 // Update updates records that math with sqlQuery conditions with `data` and
 // returns number of affected rows and error if anything went wring. If
 // the sqlQuery condition was empty it'll update all records in destination
-// table. And panics if no destination table was set before call Update.
+// table. It returns error if `data` is empty.
 func (q *sqlQuery) Update(data base.RecordData) (int, error) {
 	if data.Length() == 0 {
-		panic("change data could not be empty")
+		return 0, errEmptyUpdate
 	}
 
-	setClause := q.parseChanges(data)
-	whereClause := q.parseWhere()
+	args := q.newArgs()
+	setClause := q.parseChanges(data, args)
+	whereClause := q.parseWhere(args)
 
 	res, err := q.session.Exec(fmt.Sprintf(
-		"UPDATE %s SET %s WHERE %s", q.table, setClause, whereClause,
-	))
+		"UPDATE %s SET %s%s", q.table, setClause, whereClause,
+	), args.values...)
+	if err != nil {
+		return 0, err
+	}
+
 	rowsAffected, _ := res.RowsAffected()
 
 	return int(rowsAffected), err
@@ -136,76 +291,237 @@ func (q *sqlQuery) Update(data base.RecordData) (int, error) {
 // It will removes all records inside destination table if no condition sqlQuery
 // was set and panics if the destination table is not set before call Delete.
 func (q *sqlQuery) Delete() (int, error) {
-	whereClause := q.parseWhere()
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
 
 	res, err := q.session.Exec(fmt.Sprintf(
-		"DELETE FROM %s WHERE %s", q.table, whereClause,
-	))
+		"DELETE FROM %s%s", q.table, whereClause,
+	), args.values...)
+	if err != nil {
+		return 0, err
+	}
+
 	rowsAffected, _ := res.RowsAffected()
 
 	return int(rowsAffected), err
 }
 
-func (q *sqlQuery) parseWhere() string {
-	clauses := make([]string, 0, len(q.conditions))
-	for _, condition := range q.conditions {
-		switch condition.(type) {
-		case term.Equal:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s = %s", condition.GetField(), q.enquoter(condition.GetValue()),
-			))
-		case term.NotEqual:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s != %s", condition.GetField(), q.enquoter(condition.GetValue()),
-			))
-		case term.GreaterThan:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s > %s", condition.GetField(), q.enquoter(condition.GetValue()),
-			))
-		case term.GreaterThanEqual:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s >= %s", condition.GetField(), q.enquoter(condition.GetValue()),
-			))
-		case term.LessThan:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s < %s", condition.GetField(), q.enquoter(condition.GetValue()),
-			))
-		case term.LessThanEqual:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s <= %s", condition.GetField(), q.enquoter(condition.GetValue()),
-			))
-		case term.IsNull:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s IS NULL", condition.GetField(),
-			))
-		case term.NotNull:
-			clauses = append(clauses, fmt.Sprintf(
-				"%s IS NOT NULL", condition.GetField(),
-			))
-		case term.In:
-			values := condition.GetValue().([]interface{})
-			valueStrings := make([]string, 0, len(values))
-			for _, value := range values {
-				valueStrings = append(valueStrings, q.enquoter(value))
-			}
-			clauses = append(clauses, fmt.Sprintf(
-				"%s IN (%s)", condition.GetField(), strings.Join(valueStrings, ", "),
-			))
+// count runs a query selecting `expression` from records matching the
+// query conditions and returns the result as a number.
+func (q *sqlQuery) count(expression string) (int, error) {
+	args := q.newArgs()
+	whereClause := q.parseWhere(args)
+
+	rows, err := queryDB(q.session, fmt.Sprintf(
+		"SELECT %s AS count FROM %s%s", expression, q.table, whereClause,
+	), args.values...)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return fetchCount(rows)
+}
+
+// newArgs creates an empty argument list for a new statement
+func (q *sqlQuery) newArgs() *sqlArgs {
+	return newSQLArgs(q.placeholder, q.converter)
+}
+
+// parseWhere generates the WHERE clause of query conditions, prefixed by a
+// space, and binds condition values to args. It returns an empty string if
+// there is no condition.
+func (q *sqlQuery) parseWhere(args *sqlArgs) string {
+	clauses := q.parseConditions(q.conditions, args)
+
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(clauses, " AND ")
+}
+
+// parseGroup generates the GROUP BY and HAVING clauses of aggregate command,
+// prefixed by a space, and binds having condition values to args. Aliases
+// of `aggregations` in having conditions are replaced by their expression.
+func (q *sqlQuery) parseGroup(aggregations []base.Aggregation, args *sqlArgs) (clause string) {
+	if len(q.groups) > 0 {
+		clause = " GROUP BY " + strings.Join(q.groups, ", ")
+	}
+
+	q.aliases = make(map[string]string, len(aggregations))
+	for _, aggregation := range aggregations {
+		q.aliases[aggregation.Alias] = aggregation.Expression()
+	}
+	defer func() { q.aliases = nil }()
+
+	if clauses := q.parseConditions(q.having, args); len(clauses) > 0 {
+		clause += " HAVING " + strings.Join(clauses, " AND ")
+	}
+
+	return clause
+}
+
+// parseConditions generates the SQL expression of each condition
+func (q *sqlQuery) parseConditions(conditions []base.Condition, args *sqlArgs) []string {
+	clauses := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		if clause := q.parseCondition(condition, args); clause != "" {
+			clauses = append(clauses, clause)
 		}
 	}
 
-	return strings.Join(clauses, " AND ")
+	return clauses
 }
 
-func (q *sqlQuery) parseOptions() (query string) {
-	if q.limit > 0 {
-		query += fmt.Sprintf("LIMIT %v ", q.limit)
+// parseCondition generates the SQL expression of a condition and binds its
+// values to args. Condition groups are parenthesized and parsed recursively.
+func (q *sqlQuery) parseCondition(condition base.Condition, args *sqlArgs) string {
+	switch condition.(type) {
+	case term.Equal:
+		return fmt.Sprintf(
+			"%s = %s", q.fieldOf(condition.GetField()), args.bind(condition.GetValue()),
+		)
+	case term.NotEqual:
+		return fmt.Sprintf(
+			"%s != %s", q.fieldOf(condition.GetField()), args.bind(condition.GetValue()),
+		)
+	case term.GreaterThan:
+		return fmt.Sprintf(
+			"%s > %s", q.fieldOf(condition.GetField()), args.bind(condition.GetValue()),
+		)
+	case term.GreaterThanEqual:
+		return fmt.Sprintf(
+			"%s >= %s", q.fieldOf(condition.GetField()), args.bind(condition.GetValue()),
+		)
+	case term.LessThan:
+		return fmt.Sprintf(
+			"%s < %s", q.fieldOf(condition.GetField()), args.bind(condition.GetValue()),
+		)
+	case term.LessThanEqual:
+		return fmt.Sprintf(
+			"%s <= %s", q.fieldOf(condition.GetField()), args.bind(condition.GetValue()),
+		)
+	case term.IsNull:
+		return fmt.Sprintf(
+			"%s IS NULL", q.fieldOf(condition.GetField()),
+		)
+	case term.NotNull:
+		return fmt.Sprintf(
+			"%s IS NOT NULL", q.fieldOf(condition.GetField()),
+		)
+	case term.In:
+		values := condition.GetValue().([]interface{})
+		return fmt.Sprintf(
+			"%s IN (%s)", q.fieldOf(condition.GetField()), strings.Join(args.bindAll(values), ", "),
+		)
+	case term.NotIn:
+		values := condition.GetValue().([]interface{})
+		return fmt.Sprintf(
+			"%s NOT IN (%s)", q.fieldOf(condition.GetField()), strings.Join(args.bindAll(values), ", "),
+		)
+	case term.Between:
+		values := condition.GetValue().([]interface{})
+		return fmt.Sprintf(
+			"%s BETWEEN %s AND %s", q.fieldOf(condition.GetField()), args.bind(values[0]), args.bind(values[1]),
+		)
+	case term.Exists:
+		return fmt.Sprintf(
+			"%s IS NOT NULL", q.fieldOf(condition.GetField()),
+		)
+	case term.ArrayContains:
+		return q.matcher(q.fieldOf(condition.GetField()), args.bind(condition.GetValue()), base.ArrayContainsMatch)
+	case term.ArrayOverlaps:
+		return q.matcher(q.fieldOf(condition.GetField()), args.bind(condition.GetValue()), base.ArrayOverlapsMatch)
+	case term.ElemMatch:
+		panic(fmt.Errorf("%w: ElemMatch condition is only supported by MongoDB", base.ErrUnsupportedType))
+	case term.Like:
+		return q.matcher(q.fieldOf(condition.GetField()), args.bind(condition.GetValue()), base.LikeMatch)
+	case term.ILike:
+		return q.matcher(q.fieldOf(condition.GetField()), args.bind(condition.GetValue()), base.ILikeMatch)
+	case term.Regex:
+		return q.matcher(q.fieldOf(condition.GetField()), args.bind(condition.GetValue()), base.RegexMatch)
+	case term.StartsWith:
+		c := condition.(term.StartsWith)
+		return q.parseSearch(q.fieldOf(c.Field), escapeLike(c.Value)+"%", c.IgnoreCase, args)
+	case term.EndsWith:
+		c := condition.(term.EndsWith)
+		return q.parseSearch(q.fieldOf(c.Field), "%"+escapeLike(c.Value), c.IgnoreCase, args)
+	case term.Contains:
+		c := condition.(term.Contains)
+		return q.parseSearch(q.fieldOf(c.Field), "%"+escapeLike(c.Value)+"%", c.IgnoreCase, args)
+	case term.And:
+		clauses := q.parseConditions(condition.GetValue().([]base.Condition), args)
+		if len(clauses) == 0 {
+			return "1 = 1"
+		}
+		return "(" + strings.Join(clauses, " AND ") + ")"
+	case term.Or:
+		clauses := q.parseConditions(condition.GetValue().([]base.Condition), args)
+		if len(clauses) == 0 {
+			return "1 = 0"
+		}
+		return "(" + strings.Join(clauses, " OR ") + ")"
+	case term.Not:
+		clause := q.parseCondition(condition.GetValue().(base.Condition), args)
+		if clause == "" {
+			return ""
+		}
+		return "NOT (" + clause + ")"
+	}
+
+	return ""
+}
+
+// fieldOf returns the expression of `field` in conditions, which is the
+// aggregation expression if field is an alias of aggregations.
+func (q *sqlQuery) fieldOf(field string) string {
+	if expression, ok := q.aliases[field]; ok {
+		return expression
+	}
+
+	return field
+}
+
+// parseSearch generates the LIKE expression of matching `field` with an
+// escaped `pattern`, which is case insensitive if `ignoreCase` is set.
+func (q *sqlQuery) parseSearch(field string, pattern string, ignoreCase bool, args *sqlArgs) string {
+	mode := base.LikeMatch
+	if ignoreCase {
+		mode = base.ILikeMatch
+	}
+
+	return fmt.Sprintf("%s ESCAPE '%s'", q.matcher(field, args.bind(pattern), mode), likeEscape)
+}
+
+// parseColumns generates the column list of fetch commands, which is all
+// columns if no column is selected. Omitted columns are excluded from bound
+// columns of table.
+func (q *sqlQuery) parseColumns() string {
+	if len(q.columns) > 0 {
+		return strings.Join(q.columns, ", ")
+	}
+
+	if len(q.omitted) == 0 || len(q.tableColumns) == 0 {
+		return "*"
 	}
 
-	if q.offset > 0 {
-		query += fmt.Sprintf("OFFSET %v ", q.offset)
+	omitted := make(map[string]bool, len(q.omitted))
+	for _, column := range q.omitted {
+		omitted[column] = true
 	}
 
+	columns := make([]string, 0, len(q.tableColumns))
+	for _, column := range q.tableColumns {
+		if !omitted[column] {
+			columns = append(columns, column)
+		}
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+func (q *sqlQuery) parseOptions() (query string) {
 	sorts := make([]string, 0, len(q.sorts))
 	for _, sort := range q.sorts {
 		var order string
@@ -218,19 +534,51 @@ func (q *sqlQuery) parseOptions() (query string) {
 	}
 
 	if len(sorts) > 0 {
-		query += fmt.Sprintf("ORDER BY %s", strings.Join(sorts, ", "))
+		query += fmt.Sprintf("ORDER BY %s ", strings.Join(sorts, ", "))
+	}
+
+	paginator := q.paginator
+	if paginator == nil {
+		paginator = paginate
 	}
+	query += paginator(q.limit, q.offset)
 
 	return strings.TrimRight(query, " ")
 }
 
-func (q *sqlQuery) parseChanges(data base.RecordData) interface{} {
-	changeSet := make([]string, 0)
-	for _, column := range data.GetColumns() {
-		changeSet = append(changeSet, fmt.Sprintf(
-			"%s = %s", column, q.enquoter(data.Get(column))),
-		)
+// paginate generates the LIMIT and OFFSET clause of databases which accept
+// OFFSET without LIMIT.
+func paginate(limit int, offset int) (clause string) {
+	if limit > 0 {
+		clause += fmt.Sprintf("LIMIT %v ", limit)
 	}
 
-	return strings.Join(changeSet, ", ")
+	if offset > 0 {
+		clause += fmt.Sprintf("OFFSET %v ", offset)
+	}
+
+	return strings.TrimRight(clause, " ")
+}
+
+func (q *sqlQuery) parseChanges(data base.RecordData, args *sqlArgs) string {
+	return prepareUpdate(data, args)
+}
+
+// likeEscape is the escape character of LIKE patterns. Backslash is not
+// used since it is an escape character in MySQL string literals too.
+const likeEscape = "!"
+
+// likeEscaper escapes wildcard characters of LIKE patterns, including
+// `[` which is a wildcard in SQL Server.
+var likeEscaper = strings.NewReplacer(
+	likeEscape, likeEscape+likeEscape,
+	"%", likeEscape+"%",
+	"_", likeEscape+"_",
+	"[", likeEscape+"[",
+)
+
+// escapeLike escapes wildcard characters of `value`, so it is matched
+// literally in a LIKE pattern.
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
 }
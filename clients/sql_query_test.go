@@ -34,8 +34,19 @@ var simpleCondition = []base.Condition{
 
 var tableName = "dbo.players"
 
-func initQuery(db base.SQLDatabase, enquoter base.Enquoter) *sqlQuery {
-	return &sqlQuery{session: db, table: tableName, conditions: conditions, enquoter: enquoter}
+var conditionArgs = []interface{}{19, "Manchester City", 8.5, 10, 2, 1, "A", "B"}
+
+func initQuery(db base.SQLDatabase) *sqlQuery {
+	client := new(SQLServer)
+
+	return &sqlQuery{
+		session:     db,
+		table:       tableName,
+		conditions:  conditions,
+		placeholder: client.placeholder,
+		converter:   client.convertValue,
+		matcher:     client.match,
+	}
 }
 
 var teams = []string{"Manchester United", "Chelsea", "Arsenal", "Liverpool"}
@@ -96,7 +107,7 @@ func TestSqlQuery_OrderBy(t *testing.T) {
 	}
 
 	session := new(SQLDatabase)
-	query := initQuery(session, nil)
+	query := initQuery(session)
 	q := query.OrderBy(sorts...)
 
 	assert.IsType(t, query, q)
@@ -108,7 +119,7 @@ func TestSqlQuery_OrderBy(t *testing.T) {
 
 func TestSqlQuery_Limit(t *testing.T) {
 	session := new(SQLDatabase)
-	query := initQuery(session, nil)
+	query := initQuery(session)
 	q := query.Limit(5)
 
 	assert.IsType(t, query, q)
@@ -120,7 +131,7 @@ func TestSqlQuery_Limit(t *testing.T) {
 
 func TestSqlQuery_Skip(t *testing.T) {
 	session := new(SQLDatabase)
-	query := initQuery(session, nil)
+	query := initQuery(session)
 	q := query.Skip(10)
 
 	assert.IsType(t, query, q)
@@ -135,29 +146,33 @@ func TestSqlQuery_Count(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		sqlQuery := "SELECT COUNT(*) AS count FROM dbo.players"
+		sqlQuery := "SELECT COUNT(*) AS count FROM dbo.players WHERE " +
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
+			"banned_date IS NULL AND trophies IS NOT NULL"
 		count := 946
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return([]string{"count"}, nil)
 		rows.On("Scan", mock.Anything).Return(nil).
 			Run(func(args mock.Arguments) {
 				arg := args.Get(0).(*interface{})
-				*arg = count
+				*arg = int64(count)
 			})
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, nil)
+		query := initQuery(session)
 		n, err := query.Count()
 
 		assert.Nil(t, err)
 		assert.Equal(t, count, n)
 	})
 
-	t.Run("notFound", func(t *testing.T) {
+	t.Run("noCondition", func(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
@@ -167,16 +182,18 @@ func TestSqlQuery_Count(t *testing.T) {
 		session := new(SQLDatabase)
 		session.On("Query", sqlQuery).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return([]string{"count"}, nil)
 		rows.On("Scan", mock.Anything).Return(nil).
 			Run(func(args mock.Arguments) {
 				arg := args.Get(0).(*interface{})
-				*arg = count
+				*arg = []byte("0")
 			})
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, nil)
+		query := initQuery(session)
+		query.conditions = nil
 		n, err := query.Count()
 
 		assert.Nil(t, err)
@@ -187,13 +204,15 @@ func TestSqlQuery_Count(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		sqlQuery := "SELECT COUNT(*) AS count FROM dbo.players"
+		sqlQuery := "SELECT COUNT(*) AS count FROM dbo.players WHERE name = @p1"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, errTest)
+		session.On("Query", sqlQuery, "Test").Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, nil)
+		query := initQuery(session)
+		query.conditions = simpleCondition
 		n, err := query.Count()
 
 		assert.NotNil(t, err)
@@ -201,21 +220,263 @@ func TestSqlQuery_Count(t *testing.T) {
 	})
 }
 
+func TestSqlQuery_CountDistinct(t *testing.T) {
+	original := queryDB
+	defer func() { queryDB = original }()
+
+	sqlQuery := "SELECT COUNT(DISTINCT team) AS count FROM dbo.players WHERE name = @p1"
+
+	session := new(SQLDatabase)
+	session.On("Query", sqlQuery, "Test").Return(nil, nil)
+	rows := new(SQLRows)
+	rows.On("Close").Return(nil)
+	rows.On("Next").Return(true)
+	rows.On("Columns").Return([]string{"count"}, nil)
+	rows.On("Scan", mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			arg := args.Get(0).(*interface{})
+			*arg = int64(4)
+		})
+
+	queryDB = queryDBMock(session, sqlQuery, rows)
+	query := initQuery(session)
+	query.conditions = simpleCondition
+	n, err := query.CountDistinct("team")
+
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+}
+
+func TestSqlQuery_Distinct(t *testing.T) {
+	original := queryDB
+	defer func() { queryDB = original }()
+
+	sqlQuery := "SELECT DISTINCT team FROM dbo.players WHERE name = @p1 ORDER BY team ASC"
+	teams := []interface{}{"Chelsea", "Liverpool"}
+
+	session := new(SQLDatabase)
+	session.On("Query", sqlQuery, "Test").Return(nil, nil)
+	rows := new(SQLRows)
+	rows.On("Close").Return(nil)
+	rows.SetLimit(len(teams))
+	rows.On("Err").Return(nil)
+	rows.On("Next").Return(true)
+	rows.On("Columns").Return([]string{"team"}, nil)
+	i := 0
+	rows.On("Scan", mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			arg := args.Get(0).(*interface{})
+			*arg = teams[i]
+			i++
+		})
+
+	queryDB = queryDBMock(session, sqlQuery, rows)
+	query := initQuery(session)
+	query.conditions = simpleCondition
+	values, err := query.OrderBy(base.Sort{Column: "team"}).Distinct("team")
+
+	assert.Nil(t, err)
+	assert.Equal(t, teams, values)
+}
+
+func TestSqlQuery_Aggregate(t *testing.T) {
+	original := queryDB
+	defer func() { queryDB = original }()
+
+	sqlQuery := "SELECT team, SUM(score) AS sum_score, COUNT(*) AS count FROM dbo.players " +
+		"WHERE name = @p1 GROUP BY team HAVING SUM(score) > @p2 AND team != @p3 ORDER BY sum_score DESC"
+
+	session := new(SQLDatabase)
+	session.On("Query", sqlQuery, "Test", 100, "Arsenal").Return(nil, nil)
+	rows := new(SQLRows)
+	rows.On("Close").Return(nil)
+	rows.SetLimit(1)
+	rows.On("Err").Return(nil)
+	rows.On("Next").Return(true)
+	rows.On("Columns").Return([]string{"team", "sum_score", "count"}, nil)
+	rows.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*interface{}) = "Chelsea"
+			*args.Get(1).(*interface{}) = int64(120)
+			*args.Get(2).(*interface{}) = int64(4)
+		})
+
+	queryDB = queryDBMock(session, sqlQuery, rows)
+	query := initQuery(session)
+	query.conditions = simpleCondition
+	results, err := query.GroupBy("team").
+		Having(term.GreaterThan{Field: "sum_score", Value: 100}, term.NotEqual{Field: "team", Value: "Arsenal"}).
+		OrderBy(base.Sort{Column: "sum_score", Descending: true}).
+		Aggregate(
+			base.Aggregation{Func: base.SumFunc, Field: "score", Alias: "sum_score"},
+			base.Aggregation{Func: base.CountFunc, Field: "*", Alias: "count"},
+		)
+
+	assert.Nil(t, err)
+	assert.Equal(t, base.RecordDataSet{*base.NewRecordData(
+		[]string{"team", "sum_score", "count"},
+		base.RecordMap{"team": "Chelsea", "sum_score": int64(120), "count": int64(4)},
+	)}, results)
+}
+
+func TestSqlQuery_Select(t *testing.T) {
+	original := queryDB
+	defer func() { queryDB = original }()
+
+	sqlQuery := "SELECT id, name FROM dbo.players WHERE name = @p1"
+
+	session := new(SQLDatabase)
+	session.On("Query", sqlQuery, "Test").Return(nil, nil)
+	rows := new(SQLRows)
+	rows.On("Close").Return(nil)
+	rows.SetLimit(1)
+	rows.On("Err").Return(nil)
+	rows.On("Next").Return(true)
+	rows.On("Columns").Return([]string{"id", "name"}, nil)
+	rows.On("Scan", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			*args.Get(0).(*interface{}) = 1
+			*args.Get(1).(*interface{}) = "Test"
+		})
+
+	queryDB = queryDBMock(session, sqlQuery, rows)
+	query := initQuery(session)
+	query.conditions = simpleCondition
+	results, err := query.Select("id", "name").All()
+
+	assert.Nil(t, err)
+	assert.Equal(t, *base.NewRecordData([]string{"id", "name"}, base.RecordMap{"id": 1, "name": "Test"}), results[0])
+}
+
+func TestSqlQuery_Omit(t *testing.T) {
+	t.Run("boundColumns", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		sqlQuery := "SELECT id, rate FROM dbo.players WHERE name = @p1 LIMIT 1"
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery, "Test").Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return([]string{"id", "rate"}, nil)
+		rows.On("Scan", mock.Anything, mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*interface{}) = 1
+				*args.Get(1).(*interface{}) = 3.5
+			})
+
+		queryDB = queryDBMock(session, sqlQuery, rows)
+		query := initQuery(session)
+		query.conditions = simpleCondition
+		query.BindColumns([]string{"id", "name", "rate", "data"})
+		data, err := query.Omit("name", "data").First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "rate"}, data.GetColumns())
+		session.AssertCalled(t, "Query", sqlQuery, "Test")
+	})
+
+	t.Run("unboundColumns", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		sqlQuery := "SELECT * FROM dbo.players WHERE name = @p1 LIMIT 1"
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery, "Test").Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return([]string{"id", "name"}, nil)
+		rows.On("Scan", mock.Anything, mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				*args.Get(0).(*interface{}) = 1
+				*args.Get(1).(*interface{}) = "Test"
+			})
+
+		queryDB = queryDBMock(session, sqlQuery, rows)
+		query := initQuery(session)
+		query.conditions = simpleCondition
+		data, err := query.Omit("name").First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id"}, data.GetColumns())
+		assert.Nil(t, data.Get("name"))
+	})
+}
+
+func TestSqlQuery_Exists(t *testing.T) {
+	sqlQuery := "SELECT CASE WHEN EXISTS (SELECT 1 FROM dbo.players WHERE name = @p1) " +
+		"THEN 1 ELSE 0 END AS count"
+
+	for name, found := range map[string]bool{"found": true, "notFound": false} {
+		found := found
+		t.Run(name, func(t *testing.T) {
+			original := queryDB
+			defer func() { queryDB = original }()
+
+			session := new(SQLDatabase)
+			session.On("Query", sqlQuery, "Test").Return(nil, nil)
+			rows := new(SQLRows)
+			rows.On("Close").Return(nil)
+			rows.On("Next").Return(true)
+			rows.On("Columns").Return([]string{"count"}, nil)
+			rows.On("Scan", mock.Anything).Return(nil).
+				Run(func(args mock.Arguments) {
+					arg := args.Get(0).(*interface{})
+					if found {
+						*arg = int64(1)
+					} else {
+						*arg = int64(0)
+					}
+				})
+
+			queryDB = queryDBMock(session, sqlQuery, rows)
+			query := initQuery(session)
+			query.conditions = simpleCondition
+			exists, err := query.Exists()
+
+			assert.Nil(t, err)
+			assert.Equal(t, found, exists)
+		})
+	}
+
+	t.Run("queryError", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		session := new(SQLDatabase)
+		session.On("Query", sqlQuery, "Test").Return(nil, errTest)
+
+		queryDB = queryDBMock(session, sqlQuery, new(SQLRows))
+		query := initQuery(session)
+		query.conditions = simpleCondition
+		exists, err := query.Exists()
+
+		assert.NotNil(t, err)
+		assert.False(t, exists)
+	})
+}
+
 func TestSqlQuery_All(t *testing.T) {
 	t.Run("found", func(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
 		sqlQuery := "SELECT * FROM dbo.players WHERE " +
-			"age = 19 AND team != N'Manchester City' AND rate > 8.5 AND score >= 10 AND " +
-			"yellow_cards < 2 AND red_cards <= 1 AND grade IN (N'A', N'B') AND " +
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
 			"banned_date IS NULL AND trophies IS NOT NULL"
 		limit := 10
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.SetLimit(limit)
+		rows.On("Err").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(columns, nil)
 		args := make([]interface{}, 0, 11)
@@ -225,7 +486,7 @@ func TestSqlQuery_All(t *testing.T) {
 		rows.On("Scan", args...).Return(nil).Run(recordGenerator)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		results, err := query.All()
 
 		assert.Nil(t, err)
@@ -237,10 +498,10 @@ func TestSqlQuery_All(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		sqlQuery := "SELECT * FROM dbo.players WHERE " +
-			"age = 19 AND team != N'Manchester City' AND rate > 8.5 AND score >= 10 AND " +
-			"yellow_cards < 2 AND red_cards <= 1 AND grade IN (N'A', N'B') AND " +
-			"banned_date IS NULL AND trophies IS NOT NULL LIMIT 10 OFFSET 50 " +
-			"ORDER BY score DESC, grade ASC"
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
+			"banned_date IS NULL AND trophies IS NOT NULL " +
+			"ORDER BY score DESC, grade ASC LIMIT 10 OFFSET 50"
 		limit := 10
 		sorts := []base.Sort{
 			{Column: "score", Descending: true},
@@ -248,9 +509,11 @@ func TestSqlQuery_All(t *testing.T) {
 		}
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.SetLimit(limit)
+		rows.On("Err").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(columns, nil)
 		args := make([]interface{}, 0, 11)
@@ -260,7 +523,7 @@ func TestSqlQuery_All(t *testing.T) {
 		rows.On("Scan", args...).Return(nil).Run(recordGenerator)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		results, err := query.Limit(limit).Skip(50).OrderBy(sorts...).All()
 
 		assert.Nil(t, err)
@@ -272,39 +535,67 @@ func TestSqlQuery_All(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		sqlQuery := "SELECT * FROM dbo.players WHERE " +
-			"age = 19 AND team != N'Manchester City' AND rate > 8.5 AND score >= 10 AND " +
-			"yellow_cards < 2 AND red_cards <= 1 AND grade IN (N'A', N'B') AND " +
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
 			"banned_date IS NULL AND trophies IS NOT NULL"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
 		rows.On("Columns").Return(columns, nil)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		results, err := query.All()
 
 		assert.Nil(t, err)
 		assert.Equal(t, 0, len(results))
 	})
 
+	t.Run("rowsError", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		sqlQuery := "SELECT * FROM dbo.players WHERE " +
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
+			"banned_date IS NULL AND trophies IS NOT NULL"
+
+		session := new(SQLDatabase)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(false)
+		rows.On("Err").Return(errTest)
+		rows.On("Columns").Return(columns, nil)
+
+		queryDB = queryDBMock(session, sqlQuery, rows)
+		query := initQuery(session)
+		results, err := query.All()
+
+		assert.Equal(t, errTest, err)
+		assert.Nil(t, results)
+	})
+
 	t.Run("queryError", func(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
 		sqlQuery := "SELECT * FROM dbo.players WHERE " +
-			"age = 19 AND team != N'Manchester City' AND rate > 8.5 AND score >= 10 AND " +
-			"yellow_cards < 2 AND red_cards <= 1 AND grade IN (N'A', N'B') AND " +
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
 			"banned_date IS NULL AND trophies IS NOT NULL"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, errTest)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		results, err := query.All()
 
 		assert.NotNil(t, err)
@@ -317,13 +608,14 @@ func TestSqlQuery_All(t *testing.T) {
 		defer func() { queryDB = original }()
 
 		sqlQuery := "SELECT * FROM dbo.players WHERE " +
-			"age = 19 AND team != N'Manchester City' AND rate > 8.5 AND score >= 10 AND " +
-			"yellow_cards < 2 AND red_cards <= 1 AND grade IN (N'A', N'B') AND " +
+			"age = @p1 AND team != @p2 AND rate > @p3 AND score >= @p4 AND " +
+			"yellow_cards < @p5 AND red_cards <= @p6 AND grade IN (@p7, @p8) AND " +
 			"banned_date IS NULL AND trophies IS NOT NULL"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", append([]interface{}{sqlQuery}, conditionArgs...)...).Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(columns, nil)
 		args := make([]interface{}, 0, 11)
@@ -333,7 +625,7 @@ func TestSqlQuery_All(t *testing.T) {
 		rows.On("Scan", args...).Return(errTest)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		results, err := query.All()
 
 		assert.NotNil(t, err)
@@ -347,11 +639,12 @@ func TestSqlQuery_First(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		sqlQuery := "SELECT * FROM dbo.players WHERE name = N'Test' LIMIT 1"
+		sqlQuery := "SELECT * FROM dbo.players WHERE name = @p1 LIMIT 1"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", sqlQuery, "Test").Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(true)
 		rows.On("Columns").Return(simpleColumns, nil)
 		rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
@@ -365,7 +658,7 @@ func TestSqlQuery_First(t *testing.T) {
 			})
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 		data, err := query.First()
 
@@ -381,50 +674,53 @@ func TestSqlQuery_First(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		sqlQuery := "SELECT * FROM dbo.players WHERE name = N'Test' LIMIT 1"
+		sqlQuery := "SELECT * FROM dbo.players WHERE name = @p1 LIMIT 1"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, nil)
+		session.On("Query", sqlQuery, "Test").Return(nil, nil)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
 		rows.On("Columns").Return(simpleColumns, nil)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 		data, err := query.First()
 
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(data.GetColumns()))
-		assert.Equal(t, 0, len(data.GetValues(query.enquoter)))
+		assert.Equal(t, 0, len(data.GetValues()))
 	})
 
 	t.Run("queryError", func(t *testing.T) {
 		original := queryDB
 		defer func() { queryDB = original }()
 
-		sqlQuery := "SELECT * FROM dbo.players WHERE name = N'Test' LIMIT 1"
+		sqlQuery := "SELECT * FROM dbo.players WHERE name = @p1 LIMIT 1"
 
 		session := new(SQLDatabase)
-		session.On("Query", sqlQuery).Return(nil, errTest)
+		session.On("Query", sqlQuery, "Test").Return(nil, errTest)
 		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
 
 		queryDB = queryDBMock(session, sqlQuery, rows)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 		data, err := query.First()
 
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(data.GetColumns()))
-		assert.Equal(t, 0, len(data.GetValues(query.enquoter)))
+		assert.Equal(t, 0, len(data.GetValues()))
 	})
 }
 
 func TestSqlQuery_Update(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		sqlQuery := "UPDATE dbo.players SET " +
-			"name = N'Updated Test', rate = 5.7 " +
-			"WHERE name = N'Test'"
+			"name = @p1, rate = @p2 " +
+			"WHERE name = @p3"
 		changeDate := *base.NewRecordData(
 			[]string{"name", "rate"},
 			base.RecordMap{"name": "Updated Test", "rate": 5.7},
@@ -432,8 +728,8 @@ func TestSqlQuery_Update(t *testing.T) {
 		res := result{rand.Int63n(100)}
 
 		session := new(SQLDatabase)
-		session.On("Exec", sqlQuery).Return(res, nil)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		session.On("Exec", sqlQuery, "Updated Test", 5.7, "Test").Return(res, nil)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 
 		count, err := query.Update(changeDate)
@@ -444,8 +740,8 @@ func TestSqlQuery_Update(t *testing.T) {
 
 	t.Run("failed", func(t *testing.T) {
 		sqlQuery := "UPDATE dbo.players SET " +
-			"name = N'Updated Test', rate = 5.7 " +
-			"WHERE name = N'Test'"
+			"name = @p1, rate = @p2 " +
+			"WHERE name = @p3"
 		changeDate := *base.NewRecordData(
 			[]string{"name", "rate"},
 			base.RecordMap{"name": "Updated Test", "rate": 5.7},
@@ -453,8 +749,8 @@ func TestSqlQuery_Update(t *testing.T) {
 		res := result{}
 
 		session := new(SQLDatabase)
-		session.On("Exec", sqlQuery).Return(res, errTest)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		session.On("Exec", sqlQuery, "Updated Test", 5.7, "Test").Return(res, errTest)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 
 		count, err := query.Update(changeDate)
@@ -463,32 +759,33 @@ func TestSqlQuery_Update(t *testing.T) {
 		assert.Equal(t, int(res.count), count)
 	})
 
-	t.Run("panic", func(t *testing.T) {
+	t.Run("emptyData", func(t *testing.T) {
 		sqlQuery := "UPDATE dbo.players SET " +
-			"name = N'Updated Test', rate = 5.7 " +
-			"WHERE name = N'Test'"
+			"name = @p1, rate = @p2 " +
+			"WHERE name = @p3"
 		changeDate := *base.ZeroRecordData()
 		res := result{}
 
 		session := new(SQLDatabase)
 		session.On("Exec", sqlQuery).Return(res, errTest)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 
-		assert.Panics(t, func() {
-			_, _ = query.Update(changeDate)
-		})
+		_, err := query.Update(changeDate)
+
+		assert.Equal(t, errEmptyUpdate, err)
+		session.AssertNotCalled(t, "Exec", sqlQuery)
 	})
 }
 
 func TestSqlQuery_Delete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		sqlQuery := "DELETE FROM dbo.players WHERE name = N'Test'"
+		sqlQuery := "DELETE FROM dbo.players WHERE name = @p1"
 		res := result{rand.Int63n(100)}
 
 		session := new(SQLDatabase)
-		session.On("Exec", sqlQuery).Return(res, nil)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		session.On("Exec", sqlQuery, "Test").Return(res, nil)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 
 		count, err := query.Delete()
@@ -498,12 +795,12 @@ func TestSqlQuery_Delete(t *testing.T) {
 	})
 
 	t.Run("failed", func(t *testing.T) {
-		sqlQuery := "DELETE FROM dbo.players WHERE name = N'Test'"
+		sqlQuery := "DELETE FROM dbo.players WHERE name = @p1"
 		res := result{}
 
 		session := new(SQLDatabase)
-		session.On("Exec", sqlQuery).Return(res, errTest)
-		query := initQuery(session, new(SQLServer).enquoteValue)
+		session.On("Exec", sqlQuery, "Test").Return(res, errTest)
+		query := initQuery(session)
 		query.conditions = simpleCondition
 
 		count, err := query.Delete()
@@ -512,3 +809,99 @@ func TestSqlQuery_Delete(t *testing.T) {
 		assert.Equal(t, int(res.count), count)
 	})
 }
+
+func TestSqlQuery_parseWhere(t *testing.T) {
+	t.Run("groups", func(t *testing.T) {
+		query := initQuery(new(SQLDatabase))
+		query.conditions = []base.Condition{
+			term.Or{
+				term.Equal{Field: "status", Value: "active"},
+				term.And{
+					term.Equal{Field: "owner", Value: 10},
+					term.Not{Condition: term.In{Field: "team", Values: []interface{}{"A", "B"}}},
+				},
+			},
+			term.NotNull{Field: "trophies"},
+		}
+
+		args := query.newArgs()
+		where := query.parseWhere(args)
+
+		assert.Equal(t, " WHERE (status = @p1 OR (owner = @p2 AND NOT (team IN (@p3, @p4)))) "+
+			"AND trophies IS NOT NULL", where)
+		assert.Equal(t, []interface{}{"active", 10, "A", "B"}, args.values)
+	})
+
+	t.Run("emptyGroups", func(t *testing.T) {
+		query := initQuery(new(SQLDatabase))
+		query.conditions = []base.Condition{term.And{}, term.Or{}}
+
+		where := query.parseWhere(query.newArgs())
+
+		assert.Equal(t, " WHERE 1 = 1 AND 1 = 0", where)
+	})
+
+	t.Run("patterns", func(t *testing.T) {
+		query := initQuery(new(SQLDatabase))
+		query.conditions = []base.Condition{
+			term.Like{Field: "name", Pattern: "Jo%n"},
+			term.ILike{Field: "team", Pattern: "man%"},
+			term.StartsWith{Field: "nickname", Value: "100%_"},
+			term.EndsWith{Field: "email", Value: "[at]example.com", IgnoreCase: true},
+			term.Contains{Field: "bio", Value: "wow!"},
+		}
+
+		args := query.newArgs()
+		where := query.parseWhere(args)
+
+		assert.Equal(t, " WHERE name LIKE @p1 AND team COLLATE Latin1_General_CI_AS LIKE @p2 "+
+			"AND nickname LIKE @p3 ESCAPE '!' AND email COLLATE Latin1_General_CI_AS LIKE @p4 ESCAPE '!' "+
+			"AND bio LIKE @p5 ESCAPE '!'", where)
+		assert.Equal(t, []interface{}{"Jo%n", "man%", "100!%!_%", "%![at]example.com", "%wow!!%"}, args.values)
+	})
+
+	t.Run("rangesAndSets", func(t *testing.T) {
+		query := initQuery(new(SQLDatabase))
+		query.conditions = []base.Condition{
+			term.Between{Field: "age", From: 18, To: 30},
+			term.NotIn{Field: "team", Values: []interface{}{"A", "B"}},
+			term.Exists{Field: "email"},
+		}
+
+		args := query.newArgs()
+		where := query.parseWhere(args)
+
+		assert.Equal(t, " WHERE age BETWEEN @p1 AND @p2 AND team NOT IN (@p3, @p4) "+
+			"AND email IS NOT NULL", where)
+		assert.Equal(t, []interface{}{18, 30, "A", "B"}, args.values)
+	})
+
+	t.Run("arrays", func(t *testing.T) {
+		client := new(Postgres)
+		query := initQuery(new(SQLDatabase))
+		query.placeholder = client.placeholder
+		query.converter = client.convertValue
+		query.matcher = client.match
+		query.conditions = []base.Condition{
+			term.ArrayContains{Field: "tags", Values: []interface{}{"go", "sql"}},
+			term.ArrayOverlaps{Field: "scores", Values: []interface{}{1, 2}},
+		}
+
+		args := query.newArgs()
+		where := query.parseWhere(args)
+
+		assert.Equal(t, " WHERE tags @> $1 AND scores && $2", where)
+		assert.Equal(t, []interface{}{`{"go","sql"}`, "{1,2}"}, args.values)
+	})
+
+	t.Run("elemMatch", func(t *testing.T) {
+		query := initQuery(new(SQLDatabase))
+		query.conditions = []base.Condition{
+			term.ElemMatch{Field: "items", Conditions: simpleCondition},
+		}
+
+		assert.PanicsWithError(t, "unsupported type: ElemMatch condition is only supported by MongoDB", func() {
+			_ = query.parseWhere(query.newArgs())
+		})
+	})
+}
@@ -0,0 +1,413 @@
+package clients
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/shark"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriver is the name of sqlite3 driver registered with the `regexp`
+// function, since SQLite does not implement REGEXP operator by itself.
+const sqliteDriver = "sqlite3_regexp"
+
+func init() {
+	sql.Register(sqliteDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", sqliteRegexp, true)
+		},
+	})
+}
+
+// memorySessions holds open sessions of in-memory databases. An in-memory
+// database is destroyed as soon as its last connection is closed, so these
+// sessions are kept open for the whole life time of the application.
+var memorySessions = struct {
+	sync.Mutex
+	sessions map[string]base.SQLDatabase
+}{sessions: make(map[string]base.SQLDatabase)}
+
+// SQLite is the SQLite3 client
+type SQLite struct {
+	session base.SQLDatabase
+	tx      base.SQLTx
+	ctx     context.Context
+	memory  bool
+
+	// borrowed is set if session is owned by another client, e.g.
+	// the pool client that this client is acquired from.
+	borrowed bool
+}
+
+// CreateTable creates `tableName` table with field and structure
+// defined in `structure` parameter for each table fields
+func (c *SQLite) CreateTable(tableName string, info base.TableInfo) error {
+	_, err := c.executor().Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s ( %s )",
+		tableName, info.GetInfo().(string),
+	))
+
+	return err
+}
+
+// EnsureIndex ensures that `index` is exists on `tableName` table,
+// if not, it tries to create index with specified condition in
+// `index` on `tableName`.
+func (c *SQLite) EnsureIndex(tableName string, index base.Index) error {
+	columns := strings.Join(index.Columns, ", ")
+
+	var createQuery string
+	if index.Unique {
+		indexName := fmt.Sprintf(
+			"%s_unique_index",
+			strings.Join(index.Columns, "_"),
+		)
+
+		createQuery = fmt.Sprintf(
+			"CREATE UNIQUE INDEX IF NOT EXISTS %s ON %s (%s)",
+			indexName, tableName, columns,
+		)
+	} else {
+		indexName := fmt.Sprintf(
+			"%s_index",
+			strings.Join(index.Columns, "_"),
+		)
+
+		createQuery = fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+			indexName, tableName, columns,
+		)
+	}
+
+	_, err := c.executor().Exec(createQuery)
+
+	return err
+}
+
+// Insert tries to insert `data` into `tableName` and returns error if
+// anything went wrong. `data` should pass by reference to have exact
+// data on `tableName`, otherwise updated record data isn't accessible.
+func (c *SQLite) Insert(tableName string, key base.Key, data *base.RecordData) error {
+	args := c.newArgs()
+	placeholders := args.bindAll(data.GetValues())
+
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) RETURNING *",
+		tableName,
+		strings.Join(data.GetColumns(), ", "),
+		strings.Join(placeholders, ", "),
+	), args.values...)
+
+	if err != nil {
+		return err
+	}
+
+	err = fetchSingleRecord(rows, data)
+	data.PruneData(pruneBytes)
+
+	return err
+}
+
+// FindByID searches through `tableName` records to find a row that its
+// `key` primary key match with `id` and returns it alongside any
+// possible error.
+func (c *SQLite) FindByID(tableName string, key base.Key, id interface{}) (base.RecordData, error) {
+	data := *base.ZeroRecordData()
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return data, err
+	}
+
+	rows, err := queryDB(c.executor(), fmt.Sprintf(
+		"SELECT * FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
+
+	if err != nil {
+		return data, err
+	}
+
+	err = fetchSingleRecord(rows, &data)
+
+	if err != nil {
+		data.Zero()
+		return data, err
+	}
+
+	data.PruneData(pruneBytes)
+
+	return data, err
+}
+
+// UpdateByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and updates it with data. It will return error if
+// anything went wrong.
+func (c *SQLite) UpdateByID(tableName string, key base.Key, id interface{}, data base.RecordData) error {
+	args := c.newArgs()
+	updateQuery := prepareUpdate(data, args)
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		tableName, updateQuery, condition,
+	), args.values...)
+
+	return err
+}
+
+// DeleteByID finds a record in `tableName` that its `key` primary key
+// match with `id`, and remove it entirely. It will return error if
+// anything went wrong.
+func (c *SQLite) DeleteByID(tableName string, key base.Key, id interface{}) error {
+	args := c.newArgs()
+	condition, err := keyCondition(key, id, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.executor().Exec(fmt.Sprintf(
+		"DELETE FROM %s WHERE %s",
+		tableName, condition,
+	), args.values...)
+
+	return err
+}
+
+// Query generates and returns sqlQuery object for further operations
+func (c *SQLite) Query(tableName string, conditions ...base.Condition) base.QueryBuilder {
+	query := newSQLQuery(c.executor(), tableName, conditions, c.placeholder, c.convertValue, c.match)
+	query.paginator = c.paginate
+	query.pruner = pruneBytes
+
+	return query
+}
+
+// Raw returns a query fetching records of raw SQL `query` with `args`.
+// Tables are named by the query, so `tableName` is ignored.
+func (c *SQLite) Raw(tableName string, query interface{}, args ...interface{}) base.RawQuery {
+	rawQuery := newRawSQLQuery(c.executor(), query, args)
+	rawQuery.pruner = pruneBytes
+
+	return rawQuery
+}
+
+// Exec executes raw SQL `command` with `args` and returns number of affected rows
+func (c *SQLite) Exec(command interface{}, args ...interface{}) (int, error) {
+	return execSQL(c.executor(), command, args)
+}
+
+// ConfigurePool applies connection pool settings of `config` on session
+func (c *SQLite) ConfigurePool(config base.PoolConfig) {
+	configureSQLPool(c.session, config)
+}
+
+// Acquire returns a client using connections of the session pool
+func (c *SQLite) Acquire() base.Client {
+	return &SQLite{session: c.session, ctx: c.ctx, memory: c.memory, borrowed: true}
+}
+
+// Begin starts a new transaction and returns a client bound to it,
+// which runs all of its operations in the transaction.
+func (c *SQLite) Begin() (base.TxClient, error) {
+	if c.tx != nil {
+		return nil, errNestedTransaction
+	}
+
+	tx, err := beginTx(c.session, orBackground(c.ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SQLite{session: c.session, tx: tx, ctx: c.ctx, memory: c.memory, borrowed: true}, nil
+}
+
+// Commit commits the transaction that client is bound to
+func (c *SQLite) Commit() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Commit()
+}
+
+// Rollback aborts the transaction that client is bound to
+func (c *SQLite) Rollback() error {
+	if c.tx == nil {
+		return errNoTransaction
+	}
+
+	return c.tx.Rollback()
+}
+
+// Close disconnect session from database and release the taken memory.
+// Sessions of in-memory databases are kept open to preserve their data,
+// and borrowed sessions are left open for the client owning them.
+func (c *SQLite) Close() {
+	if !c.memory && !c.borrowed {
+		_ = c.session.Close()
+	}
+
+	c.session = nil
+	c.tx = nil
+}
+
+// WithContext returns a copy of client which runs all of its statements
+// with `ctx`, so they are stopped when context is canceled or expired.
+func (c *SQLite) WithContext(ctx context.Context) base.Client {
+	client := *c
+	client.ctx = ctx
+
+	return &client
+}
+
+// Get the executor of statements bound to client context, which is the
+// transaction if client is bound to one, or the database session otherwise.
+func (c *SQLite) executor() base.SQLExecutor {
+	if c.tx != nil {
+		return withContext(c.tx, c.ctx)
+	}
+
+	return withContext(c.session, c.ctx)
+}
+
+// Generate the SQLite bind parameter placeholder of nth argument
+func (c *SQLite) placeholder(n int) string {
+	return fmt.Sprintf("?%d", n)
+}
+
+// Create a new argument list for a query using SQLite placeholders
+func (c *SQLite) newArgs() *sqlArgs {
+	return newSQLArgs(c.placeholder, c.convertValue)
+}
+
+// Generate the SQLite expression of matching field with pattern
+func (c *SQLite) match(field string, pattern string, mode base.MatchMode) string {
+	switch mode {
+	case base.ILikeMatch:
+		return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", field, pattern)
+	case base.RegexMatch:
+		return fmt.Sprintf("%s REGEXP %s", field, pattern)
+	case base.ArrayContainsMatch, base.ArrayOverlapsMatch:
+		panic(fmt.Errorf("%w: Array conditions are not supported by SQLite", base.ErrUnsupportedType))
+	}
+
+	return fmt.Sprintf("%s LIKE %s", field, pattern)
+}
+
+// Generate the SQLite LIMIT and OFFSET clause. OFFSET is not accepted without
+// LIMIT, so a negative limit, which means no limit, is set if only `offset`
+// is given.
+func (c *SQLite) paginate(limit int, offset int) string {
+	if limit == 0 && offset > 0 {
+		return fmt.Sprintf("LIMIT -1 OFFSET %v", offset)
+	}
+
+	return paginate(limit, offset)
+}
+
+// Convert values to a proper presentation of their type for sqlite driver
+func (c *SQLite) convertValue(i interface{}) interface{} {
+	i = encodeValue(i)
+	if i == nil {
+		return nil
+	}
+
+	if isDriverValue(i) {
+		return i
+	}
+
+	t := reflect.TypeOf(i)
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return i
+	case reflect.Uint64:
+		// SQLite integers are signed 64 bit, so uint64 values are stored
+		// as text to not lose the values that overflow int64.
+		return strconv.FormatUint(reflect.ValueOf(i).Uint(), 10)
+	case reflect.Map, reflect.Struct:
+		bytes, err := json.Marshal(i)
+		shark.PanicIfError(err)
+		return string(bytes)
+	}
+
+	panic(fmt.Errorf("%w: value with type of %s", base.ErrUnsupportedType, t.Kind().String()))
+}
+
+// NewSQLite instantiate and return a new SQLite session object. `path` is
+// the database file path or `:memory:` for an in-memory database, which
+// is shared between all SQLite clients opened with the same path.
+func NewSQLite(path string) base.Client {
+	if !isMemoryDatabase(path) {
+		session, err := sqlOpen(sqliteDriver, path)
+		shark.PanicIfError(err)
+
+		return &SQLite{session: session}
+	}
+
+	memorySessions.Lock()
+	defer memorySessions.Unlock()
+
+	session, ok := memorySessions.sessions[path]
+	if !ok {
+		var err error
+		session, err = sqlOpen(sqliteDriver, sharedMemoryPath(path))
+		shark.PanicIfError(err)
+
+		memorySessions.sessions[path] = session
+	}
+
+	return &SQLite{session: session, memory: true}
+}
+
+// sqliteRegexp implements `value REGEXP pattern` operator of SQLite, which
+// calls regexp(pattern, value) function. Null values never match.
+func sqliteRegexp(pattern string, value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case string:
+		return regexp.MatchString(pattern, v)
+	case []byte:
+		return regexp.Match(pattern, v)
+	case nil:
+		return false, nil
+	}
+
+	return regexp.MatchString(pattern, fmt.Sprint(value))
+}
+
+// isMemoryDatabase checks whether `path` refers to an in-memory database
+func isMemoryDatabase(path string) bool {
+	return strings.HasPrefix(path, ":memory:") ||
+		strings.HasPrefix(path, "file::memory:") ||
+		strings.Contains(path, "mode=memory")
+}
+
+// sharedMemoryPath enables shared cache on `:memory:` path, so all
+// connections of the session pool see the same in-memory database.
+func sharedMemoryPath(path string) string {
+	if !strings.HasPrefix(path, ":memory:") {
+		return path
+	}
+
+	query := strings.TrimPrefix(strings.TrimPrefix(path, ":memory:"), "?")
+	if query == "" {
+		return "file::memory:?cache=shared"
+	}
+
+	return "file::memory:?cache=shared&" + query
+}
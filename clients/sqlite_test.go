@@ -0,0 +1,445 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	. "github.com/Kamva/octopus/clients/internal"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+func initSQLite(session base.SQLDatabase) *SQLite {
+	return &SQLite{session: session}
+}
+
+func getSQLiteTableStructure() base.TableStructure {
+	return base.TableStructure{
+		{Name: "id", Type: "INTEGER", Options: "PRIMARY KEY AUTOINCREMENT"},
+		{Name: "name", Type: "TEXT", Options: "NOT NULL"},
+		{Name: "age", Type: "INTEGER", Options: "NULL"},
+		{Name: "status", Type: "BOOLEAN", Options: "DEFAULT TRUE"},
+	}
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestNewSQLite(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		original := sqlOpen
+		defer func() { sqlOpen = original }()
+
+		db := new(SQLDatabase)
+		path := "/tmp/test.db"
+		sqlOpen = sqlOpenMock(sqliteDriver, path, db, nil)
+
+		assert.NotPanics(t, func() {
+			client := NewSQLite(path)
+			sql := client.(*SQLite)
+
+			assert.Equal(t, db, sql.session)
+			assert.False(t, sql.memory)
+		})
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		original := sqlOpen
+		defer func() {
+			sqlOpen = original
+			delete(memorySessions.sessions, ":memory:?_foreign_keys=1")
+		}()
+
+		db := new(SQLDatabase)
+		var opened []string
+		sqlOpen = func(d string, u string) (base.SQLDatabase, error) {
+			opened = append(opened, u)
+			return db, nil
+		}
+
+		first := NewSQLite(":memory:?_foreign_keys=1").(*SQLite)
+		second := NewSQLite(":memory:?_foreign_keys=1").(*SQLite)
+
+		assert.Equal(t, []string{"file::memory:?cache=shared&_foreign_keys=1"}, opened)
+		assert.Equal(t, db, first.session)
+		assert.Equal(t, db, second.session)
+		assert.True(t, first.memory)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		original := sqlOpen
+		defer func() { sqlOpen = original }()
+
+		db := new(SQLDatabase)
+		path := "invalid path"
+		sqlOpen = sqlOpenMock(sqliteDriver, path, db, errTest)
+
+		assert.Panics(t, func() {
+			_ = NewSQLite(path)
+		})
+	})
+}
+
+func TestSQLite_CreateTable(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		session := new(SQLDatabase)
+
+		createQuery := "CREATE TABLE IF NOT EXISTS users ( " +
+			"id INTEGER PRIMARY KEY AUTOINCREMENT, " +
+			"name TEXT NOT NULL, " +
+			"age INTEGER NULL, " +
+			"status BOOLEAN DEFAULT TRUE )"
+
+		session.On("ExecContext", mock.Anything, createQuery).Return(nil, nil)
+
+		client := initSQLite(session)
+		err := client.CreateTable("users", getSQLiteTableStructure())
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("dbExecError", func(t *testing.T) {
+		session := new(SQLDatabase)
+
+		session.On("ExecContext", mock.Anything, mock.AnythingOfType("string")).Return(nil, errTest)
+
+		client := initSQLite(session)
+		err := client.CreateTable("users", getSQLiteTableStructure())
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSQLite_EnsureIndex(t *testing.T) {
+	t.Run("unique", func(t *testing.T) {
+		query := "CREATE UNIQUE INDEX IF NOT EXISTS name_email_unique_index ON users (name, email)"
+
+		session := new(SQLDatabase)
+		session.On("ExecContext", mock.Anything, query).Return(nil, nil)
+
+		client := initSQLite(session)
+		err := client.EnsureIndex("users", base.Index{
+			Columns: []string{"name", "email"},
+			Unique:  true,
+		})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("nonUnique", func(t *testing.T) {
+		query := "CREATE INDEX IF NOT EXISTS name_index ON users (name)"
+
+		session := new(SQLDatabase)
+		session.On("ExecContext", mock.Anything, query).Return(nil, errTest)
+
+		client := initSQLite(session)
+		err := client.EnsureIndex("users", base.Index{
+			Columns: []string{"name"},
+		})
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSQLite_Insert(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "INSERT INTO users (name, age, status, worth, meta) VALUES (?1, ?2, ?3, ?4, ?5) RETURNING *"
+
+		session := new(SQLDatabase)
+		session.On(
+			"QueryContext", mock.Anything, query, "Test", 5, true, "7845421000000000000", `{"key":"value"}`,
+		).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return(
+			[]string{"id", "name", "age", "status", "worth", "meta"},
+			nil,
+		)
+
+		rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				values := []interface{}{
+					int64(1), "Test", int64(5), true, "7845421000000000000", []byte(`{"key":"value"}`),
+				}
+				for i, value := range values {
+					arg := args.Get(i).(*interface{})
+					*arg = value
+				}
+			})
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initSQLite(session)
+		data := base.NewRecordData(
+			[]string{"name", "age", "status", "worth", "meta"},
+			base.RecordMap{
+				"name": "Test", "age": 5, "status": true,
+				"worth": uint64(7845421000000000000), "meta": map[string]string{"key": "value"},
+			},
+		)
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.Nil(t, err)
+
+		assert.Equal(t, int64(1), data.Get("id"))
+		assert.Equal(t, "Test", data.Get("name"))
+		assert.Equal(t, int64(5), data.Get("age"))
+		assert.Equal(t, true, data.Get("status"))
+		assert.Equal(t, "7845421000000000000", data.Get("worth"))
+		assert.Equal(t, `{"key":"value"}`, data.Get("meta"))
+	})
+
+	t.Run("unsupportedType", func(t *testing.T) {
+		session := new(SQLDatabase)
+		client := initSQLite(session)
+		data := base.NewRecordData(
+			[]string{"invalidSlice"},
+			base.RecordMap{
+				"invalidSlice": []int{1, 2},
+			},
+		)
+		assert.Panics(t, func() {
+			_ = client.Insert("users", base.Key{"id"}, data)
+		})
+	})
+
+	t.Run("queryError", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "INSERT INTO users (name) VALUES (?1) RETURNING *"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, "Test").Return(nil, errTest)
+
+		queryDB = queryDBMock(session, query, new(SQLRows))
+		client := initSQLite(session)
+		data := base.NewRecordData(
+			[]string{"name"},
+			base.RecordMap{"name": "Test"},
+		)
+		err := client.Insert("users", base.Key{"id"}, data)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestSQLite_FindByID(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "SELECT * FROM users WHERE id = ?1"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(true)
+		rows.On("Columns").Return(
+			[]string{"id", "name", "rate", "available"},
+			nil,
+		)
+		rows.On("Scan", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				values := []interface{}{int64(1), []byte("Test"), 3.5, true}
+				for i, value := range values {
+					arg := args.Get(i).(*interface{})
+					*arg = value
+				}
+			})
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initSQLite(session)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
+
+		assert.Nil(t, err)
+
+		assert.Equal(t, int64(1), data.Get("id"))
+		assert.Equal(t, "Test", data.Get("name"))
+		assert.Equal(t, 3.5, data.Get("rate"))
+		assert.Equal(t, true, data.Get("available"))
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "SELECT * FROM users WHERE id = ?1"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, 1).Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Next").Return(false)
+		rows.On("Err").Return(nil)
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initSQLite(session)
+		data, err := client.FindByID("users", base.Key{"id"}, 1)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, *base.ZeroRecordData(), data)
+	})
+}
+
+func TestSQLite_UpdateByID(t *testing.T) {
+	query := "UPDATE users SET name = ?1, available = ?2 WHERE id = ?3"
+
+	session := new(SQLDatabase)
+	session.On("ExecContext", mock.Anything, query, "Updated Test", false, 1).Return(nil, nil)
+
+	client := initSQLite(session)
+	data := base.NewRecordData(
+		[]string{"name", "available"},
+		base.RecordMap{"name": "Updated Test", "available": false},
+	)
+	err := client.UpdateByID("users", base.Key{"id"}, 1, *data)
+
+	assert.Nil(t, err)
+}
+
+func TestSQLite_DeleteByID(t *testing.T) {
+	query := "DELETE FROM users WHERE id = ?1"
+
+	session := new(SQLDatabase)
+	session.On("ExecContext", mock.Anything, query, 1).Return(nil, errTest)
+
+	client := initSQLite(session)
+	err := client.DeleteByID("users", base.Key{"id"}, 1)
+
+	assert.NotNil(t, err)
+}
+
+func TestSQLite_Query(t *testing.T) {
+	conditions := []base.Condition{
+		term.Equal{Field: "name", Value: "Test"},
+	}
+
+	session := new(SQLDatabase)
+	client := initSQLite(session)
+	r := client.Query("users", conditions...)
+
+	assert.IsType(t, new(sqlQuery), r)
+
+	q := r.(*sqlQuery)
+
+	assert.Equal(t, conditions, q.conditions)
+	assert.Equal(t, "users", q.table)
+	assert.Equal(t, "?3", q.placeholder(3))
+	assert.NotNil(t, q.paginator)
+	assert.NotNil(t, q.pruner)
+}
+
+func TestSQLite_paginate(t *testing.T) {
+	client := initSQLite(new(SQLDatabase))
+
+	assert.Equal(t, "", client.paginate(0, 0))
+	assert.Equal(t, "LIMIT 10", client.paginate(10, 0))
+	assert.Equal(t, "LIMIT 10 OFFSET 5", client.paginate(10, 5))
+	assert.Equal(t, "LIMIT -1 OFFSET 5", client.paginate(0, 5))
+
+	t.Run("skip", func(t *testing.T) {
+		original := queryDB
+		defer func() { queryDB = original }()
+
+		query := "SELECT * FROM users WHERE name = ?1 ORDER BY id ASC LIMIT -1 OFFSET 1"
+
+		session := new(SQLDatabase)
+		session.On("QueryContext", mock.Anything, query, "Test").Return(nil, nil)
+		rows := new(SQLRows)
+		rows.On("Close").Return(nil)
+		rows.On("Err").Return(nil)
+		rows.On("Next").Return(false)
+		rows.On("Columns").Return([]string{"id", "name"}, nil)
+
+		queryDB = queryDBMock(session, query, rows)
+		client := initSQLite(session)
+		_, err := client.Query("users", term.Equal{Field: "name", Value: "Test"}).
+			OrderBy(base.Sort{Column: "id"}).
+			Skip(1).
+			All()
+
+		assert.Nil(t, err)
+		session.AssertCalled(t, "QueryContext", mock.Anything, query, "Test")
+	})
+}
+
+func TestSQLite_match(t *testing.T) {
+	client := initSQLite(new(SQLDatabase))
+
+	assert.Equal(t, "name LIKE ?1", client.match("name", "?1", base.LikeMatch))
+	assert.Equal(t, "LOWER(name) LIKE LOWER(?1)", client.match("name", "?1", base.ILikeMatch))
+	assert.Equal(t, "name REGEXP ?1", client.match("name", "?1", base.RegexMatch))
+	assert.PanicsWithError(t, "unsupported type: Array conditions are not supported by SQLite", func() {
+		_ = client.match("tags", "?1", base.ArrayContainsMatch)
+	})
+	assert.PanicsWithError(t, "unsupported type: Array conditions are not supported by SQLite", func() {
+		_ = client.match("tags", "?1", base.ArrayOverlapsMatch)
+	})
+}
+
+func TestSQLite_Acquire(t *testing.T) {
+	session := new(SQLDatabase)
+	client := &SQLite{session: session, memory: true}
+
+	acquired := client.Acquire().(*SQLite)
+
+	assert.Equal(t, session, acquired.session)
+	assert.True(t, acquired.memory)
+	assert.True(t, acquired.borrowed)
+}
+
+func TestSQLite_Begin(t *testing.T) {
+	original := beginTx
+	defer func() { beginTx = original }()
+
+	session := new(SQLDatabase)
+	tx := new(SQLTx)
+	tx.On("ExecContext", mock.Anything, "DELETE FROM users WHERE id = ?1", 1).Return(nil, nil)
+	beginTx = beginTxMock(tx, nil)
+
+	client := initSQLite(session)
+	txClient, err := client.Begin()
+
+	assert.Nil(t, err)
+	assert.Nil(t, txClient.DeleteByID("users", base.Key{"id"}, 1))
+
+	txClient.Close()
+
+	session.AssertNotCalled(t, "ExecContext")
+	session.AssertNotCalled(t, "Close")
+}
+
+func TestSQLite_Close(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		session := new(SQLDatabase)
+		session.On("Close").Return(nil)
+		client := initSQLite(session)
+		client.Close()
+
+		assert.Nil(t, client.session)
+		session.AssertCalled(t, "Close")
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		session := new(SQLDatabase)
+		client := &SQLite{session: session, memory: true}
+		client.Close()
+
+		assert.Nil(t, client.session)
+		session.AssertNotCalled(t, "Close")
+	})
+}
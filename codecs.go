@@ -0,0 +1,669 @@
+package octopus
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kamva/nautilus/types"
+	"github.com/Kamva/octopus/base"
+	"github.com/globalsign/mgo/bson"
+)
+
+// Codec converts values of a scheme field type to the values stored in
+// database and back, and determines type of columns storing the field.
+type Codec = base.Codec
+
+// RegisterCodec registers `codec` for scheme fields of type `t`, so values
+// of custom types (e.g. decimals, UUIDs or enums) are stored and read by
+// it. Codecs of built-in types, e.g. time.Time, are replaced by registering
+// a codec for the same type.
+func RegisterCodec(t reflect.Type, codec Codec) {
+	base.RegisterCodec(t, codec)
+}
+
+// RegisterKindCodec registers `codec` for scheme fields of kind `k` which
+// have no codec registered for their type. Codecs of built-in types, e.g.
+// strings or structs, are registered by their kind and replaced by it.
+func RegisterKindCodec(k reflect.Kind, codec Codec) {
+	base.RegisterKindCodec(k, codec)
+}
+
+// LookupCodec returns the codec converting fields of type `t`, which is the
+// codec registered for the type or its kind, or nil if type is not supported.
+func LookupCodec(t reflect.Type) Codec {
+	return codecOf(t)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+var timestampsType = reflect.TypeOf(Timestamps{})
+var softDeleteType = reflect.TypeOf(SoftDelete{})
+var nullTimeType = reflect.TypeOf(sql.NullTime{})
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+var setterType = reflect.TypeOf((*bson.Setter)(nil)).Elem()
+
+// nullTypes maps sql.Null* types to type of values they hold
+var nullTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(sql.NullBool{}):    reflect.TypeOf(false),
+	reflect.TypeOf(sql.NullByte{}):    reflect.TypeOf(uint8(0)),
+	reflect.TypeOf(sql.NullInt16{}):   reflect.TypeOf(int16(0)),
+	reflect.TypeOf(sql.NullInt32{}):   reflect.TypeOf(int32(0)),
+	reflect.TypeOf(sql.NullInt64{}):   reflect.TypeOf(int64(0)),
+	reflect.TypeOf(sql.NullFloat64{}): reflect.TypeOf(float64(0)),
+	reflect.TypeOf(sql.NullString{}):  reflect.TypeOf(""),
+	reflect.TypeOf(sql.NullTime{}):    timeType,
+}
+
+// timeLayouts are layouts of time values returned as text by databases
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// columnTypes are column types of built-in kinds in each database
+var columnTypes = map[base.DriverName]map[reflect.Kind]string{
+	base.PG: {
+		reflect.Bool:    "BOOLEAN",
+		reflect.Int8:    "SMALLINT",
+		reflect.Int16:   "SMALLINT",
+		reflect.Uint8:   "SMALLINT",
+		reflect.Int32:   "INT",
+		reflect.Int:     "INT",
+		reflect.Uint16:  "INT",
+		reflect.Int64:   "BIGINT",
+		reflect.Uint32:  "BIGINT",
+		reflect.Uint:    "BIGINT",
+		reflect.Uint64:  "DECIMAL",
+		reflect.Float32: "REAL",
+		reflect.Float64: "FLOAT8",
+		reflect.Map:     "JSON",
+		reflect.Struct:  "JSON",
+		reflect.String:  "TEXT",
+	},
+	base.MSSQL: {
+		reflect.Bool:    "BIT",
+		reflect.Uint8:   "TINYINT",
+		reflect.Int8:    "SMALLINT",
+		reflect.Int16:   "SMALLINT",
+		reflect.Int32:   "INT",
+		reflect.Int:     "INT",
+		reflect.Uint16:  "INT",
+		reflect.Int64:   "BIGINT",
+		reflect.Uint32:  "BIGINT",
+		reflect.Uint:    "BIGINT",
+		reflect.Uint64:  "DECIMAL",
+		reflect.Float32: "REAL",
+		reflect.Float64: "FLOAT",
+		reflect.String:  "NVARCHAR(MAX)",
+	},
+	base.MySQL: {
+		reflect.Bool:    "BOOLEAN",
+		reflect.Int8:    "TINYINT",
+		reflect.Uint8:   "TINYINT UNSIGNED",
+		reflect.Int16:   "SMALLINT",
+		reflect.Uint16:  "SMALLINT UNSIGNED",
+		reflect.Int32:   "INT",
+		reflect.Int:     "INT",
+		reflect.Uint32:  "INT UNSIGNED",
+		reflect.Int64:   "BIGINT",
+		reflect.Uint64:  "BIGINT UNSIGNED",
+		reflect.Uint:    "BIGINT UNSIGNED",
+		reflect.Float32: "FLOAT",
+		reflect.Float64: "DOUBLE",
+		reflect.Map:     "JSON",
+		reflect.Struct:  "JSON",
+		reflect.String:  "VARCHAR(255)",
+	},
+	base.SQLite: {
+		reflect.Bool:    "BOOLEAN",
+		reflect.Int8:    "INTEGER",
+		reflect.Int16:   "INTEGER",
+		reflect.Int32:   "INTEGER",
+		reflect.Int:     "INTEGER",
+		reflect.Int64:   "INTEGER",
+		reflect.Uint8:   "INTEGER",
+		reflect.Uint16:  "INTEGER",
+		reflect.Uint32:  "INTEGER",
+		reflect.Uint:    "INTEGER",
+		reflect.Uint64:  "TEXT",
+		reflect.Float32: "REAL",
+		reflect.Float64: "REAL",
+		reflect.Map:     "TEXT",
+		reflect.Struct:  "TEXT",
+		reflect.String:  "TEXT",
+	},
+}
+
+// serialTypes are auto increment types of PostgreSQL integer types
+var serialTypes = map[string]string{
+	"SMALLINT": "SMALLSERIAL",
+	"INT":      "SERIAL",
+	"BIGINT":   "BIGSERIAL",
+}
+
+// Codecs of built-in types are registered by their kind, which are used for
+// fields with no codec registered for their type.
+func init() {
+	base.RegisterKindCodec(reflect.Bool, boolCodec{})
+	for _, kind := range []reflect.Kind{reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64} {
+		base.RegisterKindCodec(kind, intCodec{})
+	}
+	for _, kind := range []reflect.Kind{reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32} {
+		base.RegisterKindCodec(kind, uintCodec{})
+	}
+	base.RegisterKindCodec(reflect.Uint64, uint64Codec{})
+	base.RegisterKindCodec(reflect.Float32, floatCodec{})
+	base.RegisterKindCodec(reflect.Float64, floatCodec{})
+	base.RegisterKindCodec(reflect.String, stringCodec{})
+	base.RegisterKindCodec(reflect.Map, mapCodec{})
+	base.RegisterKindCodec(reflect.Array, sliceCodec{})
+	base.RegisterKindCodec(reflect.Slice, sliceCodec{})
+	base.RegisterKindCodec(reflect.Struct, structCodec{})
+	base.RegisterKindCodec(reflect.Ptr, ptrCodec{})
+
+	base.RegisterCodec(timeType, timeCodec{})
+	for t, valueType := range nullTypes {
+		base.RegisterCodec(t, nullCodec{valueType: valueType})
+	}
+}
+
+// codecOf returns codec of fields with type `t`, which is the codec registered
+// for the type, or the codec registered for its kind. Types implementing
+// sql.Scanner/driver.Valuer or bson.Setter convert their values themselves.
+// It returns nil if type is not supported.
+func codecOf(t reflect.Type) base.Codec {
+	if codec := base.LookupCodec(t); codec != nil {
+		return codec
+	}
+
+	kind := base.LookupKindCodec(t.Kind())
+	if kind == nil {
+		return nil
+	}
+
+	ptr := reflect.PtrTo(t)
+	if ptr.Implements(scannerType) || t.Implements(valuerType) {
+		return valuerCodec{kind: kind}
+	}
+
+	if ptr.Implements(setterType) {
+		return setterCodec{kind: kind}
+	}
+
+	return kind
+}
+
+// encodeField returns the value of field `value` stored in database
+func encodeField(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	codec := codecOf(reflect.TypeOf(value))
+	if codec == nil {
+		return value, nil
+	}
+
+	return codec.Encode(value)
+}
+
+// decodeField sets `value` returned by database on `field`
+func decodeField(value interface{}, field reflect.Value) error {
+	codec := codecOf(field.Type())
+	if codec == nil {
+		return fmt.Errorf("%w: field type [%s]", base.ErrUnsupportedType, field.Type().String())
+	}
+
+	return codec.Decode(value, field)
+}
+
+// unsupportedFieldType returns the error of field types with no matching column type
+func unsupportedFieldType(t reflect.Type) error {
+	return fmt.Errorf(
+		"%w: field type [%s] is not supported, change type or ignore it with tag",
+		base.ErrUnsupportedType, t.Kind().String(),
+	)
+}
+
+// kindCodec implements column type and encoding of built-in codecs, which
+// determine column type by kind of field type and store values as is, as
+// clients convert them to values accepted by the database.
+type kindCodec struct{}
+
+// ColumnType returns column type of kind of `t` in `driver` database
+func (kindCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	typename, ok := columnTypes[driver][t.Kind()]
+	if !ok {
+		panic(unsupportedFieldType(t))
+	}
+
+	if driver == base.PG && tags["ai"] == "true" {
+		if serial, ok := serialTypes[typename]; ok {
+			return serial
+		}
+	}
+
+	return typename
+}
+
+// Encode returns `value` as is
+func (kindCodec) Encode(value interface{}) (interface{}, error) {
+	return value, nil
+}
+
+type boolCodec struct{ kindCodec }
+
+func (boolCodec) Decode(value interface{}, field reflect.Value) error {
+	// MySQL stores booleans as tiny integers and returns
+	// them as int64 or as string in text protocol.
+	if val, ok := value.(int64); ok {
+		field.SetBool(val != 0)
+	} else if val, ok := value.(string); ok {
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	} else {
+		field.SetBool(value.(bool))
+	}
+
+	return nil
+}
+
+type intCodec struct{ kindCodec }
+
+func (intCodec) Decode(value interface{}, field reflect.Value) error {
+	if val, ok := value.(string); ok {
+		i64, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(i64)
+	} else if val, ok := value.(int); ok {
+		field.SetInt(int64(val))
+	} else if val, ok := value.(int8); ok {
+		field.SetInt(int64(val))
+	} else if val, ok := value.(int16); ok {
+		field.SetInt(int64(val))
+	} else if val, ok := value.(int32); ok {
+		field.SetInt(int64(val))
+	} else {
+		field.SetInt(value.(int64))
+	}
+
+	return nil
+}
+
+type uintCodec struct{ kindCodec }
+
+func (uintCodec) Decode(value interface{}, field reflect.Value) error {
+	if val, ok := value.(string); ok {
+		u64, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(u64)
+	} else if val, ok := value.(uint); ok {
+		field.SetUint(uint64(val))
+	} else if val, ok := value.(uint8); ok {
+		field.SetUint(uint64(val))
+	} else if val, ok := value.(uint16); ok {
+		field.SetUint(uint64(val))
+	} else if val, ok := value.(uint32); ok {
+		field.SetUint(uint64(val))
+	} else if val, ok := value.(uint64); ok {
+		field.SetUint(val)
+	} else {
+		// Most Databases treated uint types as int type
+		if val, ok := value.(int); ok {
+			field.SetUint(uint64(val))
+		} else if val, ok := value.(int8); ok {
+			field.SetUint(uint64(val))
+		} else if val, ok := value.(int16); ok {
+			field.SetUint(uint64(val))
+		} else if val, ok := value.(int32); ok {
+			field.SetUint(uint64(val))
+		} else {
+			field.SetUint(uint64(value.(int64)))
+		}
+	}
+
+	return nil
+}
+
+type uint64Codec struct{ kindCodec }
+
+func (uint64Codec) Decode(value interface{}, field reflect.Value) error {
+	if val, ok := value.(uint64); ok {
+		field.SetUint(val)
+		return nil
+	}
+
+	f64, err := strconv.ParseFloat(value.(string), 64)
+	if err != nil {
+		return err
+	}
+	field.SetUint(uint64(f64))
+
+	return nil
+}
+
+type floatCodec struct{ kindCodec }
+
+func (floatCodec) Decode(value interface{}, field reflect.Value) error {
+	if val, ok := value.(string); ok {
+		f64, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f64)
+	} else if val, ok := value.(float32); ok {
+		field.SetFloat(float64(val))
+	} else {
+		field.SetFloat(value.(float64))
+	}
+
+	return nil
+}
+
+type stringCodec struct{ kindCodec }
+
+func (stringCodec) Decode(value interface{}, field reflect.Value) error {
+	if oid, ok := value.(bson.ObjectId); ok {
+		field.Set(reflect.ValueOf(oid))
+	} else {
+		field.SetString(value.(string))
+	}
+
+	return nil
+}
+
+type mapCodec struct{ kindCodec }
+
+func (mapCodec) Decode(value interface{}, field reflect.Value) error {
+	// If the value is string, it is probably, a serialized format of map.
+	if strVal, ok := value.(string); ok {
+		data := field.Addr().Interface().(*types.JSONMap)
+		return json.Unmarshal([]byte(strVal), data)
+	}
+
+	b, _ := json.Marshal(value)
+	_ = json.Unmarshal(b, field.Addr().Interface())
+
+	return nil
+}
+
+type sliceCodec struct{ kindCodec }
+
+// ColumnType returns array of element column type on PostgreSQL, as other
+// databases have no array types.
+func (c sliceCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	if driver == base.PG {
+		return columnType(driver, t.Elem(), tags) + "[]"
+	}
+
+	return c.kindCodec.ColumnType(driver, t, tags)
+}
+
+func (sliceCodec) Decode(value interface{}, field reflect.Value) error {
+	// If field type is slice or array and returning data is a string, it is
+	// possible that an array data is saved serialized array. (Mostly arrays
+	// in PostgreSQL)
+	if strVal, ok := value.(string); ok {
+		// Remove start and end character from
+		valBytes := []byte(strVal)
+		s := string(valBytes[1 : len(valBytes)-1])
+
+		// if value contains `","` it means that it is array of json
+		// so for preventing conflict in splitting the string we
+		// replace `","` with `"|"` and split string by |
+		values := make([]string, 0)
+		if strings.Contains(s, `","`) {
+			s = strings.Replace(s, `","`, `"|"`, -1)
+			values = strings.Split(s, "|")
+		} else {
+			values = strings.Split(s, ",")
+		}
+
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+
+		for i, value := range values {
+			x := slice.Index(i)
+			x.Set(makeSliceValue(x, value))
+		}
+
+		field.Set(slice)
+	} else {
+		// Here, we assume that the returning data is slice or array.
+		field.Set(reflect.ValueOf(value))
+	}
+
+	return nil
+}
+
+type structCodec struct{ kindCodec }
+
+func (structCodec) Decode(value interface{}, field reflect.Value) error {
+	data := field.Addr().Interface()
+	var b []byte
+	var err error
+
+	// Check if the value is the serialization of field value, convert the
+	// string to bytes (PostgreSQL). If not (struct or map value for MongoDB
+	// sub documents) we serialize the struct or map to json and then in both
+	// situations, deserialize the bytes to defined struct format in field.
+	if strVal, ok := value.(string); ok {
+		b = []byte(strVal)
+	} else if b, err = json.Marshal(value); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, data)
+}
+
+// ptrCodec is the codec of pointer fields, which are nullable fields
+// of the pointed type.
+type ptrCodec struct{}
+
+func (ptrCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return columnType(driver, t.Elem(), tags)
+}
+
+// Encode returns the encoded pointed value, or nil for nil pointers
+func (ptrCodec) Encode(value interface{}) (interface{}, error) {
+	v := reflect.ValueOf(value)
+	if v.IsNil() {
+		return nil, nil
+	}
+
+	return encodeField(v.Elem().Interface())
+}
+
+func (ptrCodec) Decode(value interface{}, field reflect.Value) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if v := reflect.ValueOf(value); v.Type() == field.Type() {
+		field.Set(v)
+		return nil
+	}
+
+	elem := reflect.New(field.Type().Elem())
+	if err := decodeField(value, elem.Elem()); err != nil {
+		return err
+	}
+	field.Set(elem)
+
+	return nil
+}
+
+// timeCodec is the codec of time.Time fields
+type timeCodec struct{}
+
+func (timeCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	switch driver {
+	case base.PG:
+		return "TIMESTAMPTZ"
+	case base.MSSQL:
+		return "DATETIME2"
+	case base.MySQL:
+		return "DATETIME(6)"
+	}
+
+	// SQLite has no time type, but driver parses values of columns
+	// declared as DATETIME to time.Time.
+	return "DATETIME"
+}
+
+// Encode returns time as is, as database drivers support time values
+func (timeCodec) Encode(value interface{}) (interface{}, error) {
+	return value, nil
+}
+
+func (timeCodec) Decode(value interface{}, field reflect.Value) error {
+	t, err := toTime(value)
+	if err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(t))
+
+	return nil
+}
+
+// nullCodec is the codec of sql.Null* types, which are stored in columns
+// of the type of value they hold.
+type nullCodec struct {
+	valueType reflect.Type
+}
+
+func (c nullCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return columnType(driver, c.valueType, tags)
+}
+
+func (nullCodec) Encode(value interface{}) (interface{}, error) {
+	return value.(driver.Valuer).Value()
+}
+
+func (nullCodec) Decode(value interface{}, field reflect.Value) error {
+	// Some drivers (e.g. MySQL) return time values as text
+	if s, ok := value.(string); ok && field.Type() == nullTimeType {
+		t, err := toTime(s)
+		if err != nil {
+			return err
+		}
+		value = t
+	}
+
+	return field.Addr().Interface().(sql.Scanner).Scan(value)
+}
+
+// valuerCodec is the codec of types implementing driver.Valuer and/or
+// sql.Scanner, which are stored in columns of their kind.
+type valuerCodec struct {
+	kind base.Codec
+}
+
+func (c valuerCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return c.kind.ColumnType(driver, t, tags)
+}
+
+// Encode returns the driver value of valuers, and value as is otherwise
+func (c valuerCodec) Encode(value interface{}) (interface{}, error) {
+	valuer, ok := value.(driver.Valuer)
+	if !ok {
+		return c.kind.Encode(value)
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+
+	return valuer.Value()
+}
+
+// Decode scans value by scanners, and decodes it by kind codec otherwise
+func (c valuerCodec) Decode(value interface{}, field reflect.Value) error {
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	return c.kind.Decode(value, field)
+}
+
+// setterCodec is the codec of types implementing bson.Setter, which decode
+// values returned by MongoDB themselves.
+type setterCodec struct {
+	kind base.Codec
+}
+
+func (c setterCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return c.kind.ColumnType(driver, t, tags)
+}
+
+// Encode returns value as is, as bson.Getter values are encoded by MongoDB driver
+func (c setterCodec) Encode(value interface{}) (interface{}, error) {
+	return c.kind.Encode(value)
+}
+
+func (setterCodec) Decode(value interface{}, field reflect.Value) error {
+	raw, err := rawBSON(value)
+	if err != nil {
+		return err
+	}
+
+	return field.Addr().Interface().(bson.Setter).SetBSON(raw)
+}
+
+// columnType returns type of column storing fields of type `t`
+func columnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	codec := codecOf(t)
+	if codec == nil {
+		panic(unsupportedFieldType(t))
+	}
+
+	return codec.ColumnType(driver, t, tags)
+}
+
+// rawBSON returns `value` encoded as raw bson value
+func rawBSON(value interface{}) (bson.Raw, error) {
+	var doc struct {
+		Value bson.Raw `bson:"value"`
+	}
+
+	data, err := bson.Marshal(bson.M{"value": value})
+	if err != nil {
+		return doc.Value, err
+	}
+
+	err = bson.Unmarshal(data, &doc)
+
+	return doc.Value, err
+}
+
+// toTime returns the time of `value`, which is a time.Time or a text
+// representation of time in one of timeLayouts.
+func toTime(value interface{}) (time.Time, error) {
+	if s, ok := value.(string); ok {
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+
+		return time.Time{}, fmt.Errorf("invalid time value [%s]", s)
+	}
+
+	return value.(time.Time), nil
+}
@@ -0,0 +1,176 @@
+package octopus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// money is a custom type which is stored as text by moneyCodec
+type money struct {
+	Cents    int64
+	Currency string
+}
+
+type moneyCodec struct{}
+
+func (moneyCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	if driver == base.PG {
+		return "MONEY_TEXT"
+	}
+
+	return "TEXT"
+}
+
+func (moneyCodec) Encode(value interface{}) (interface{}, error) {
+	m := value.(money)
+	return fmt.Sprintf("%d %s", m.Cents, m.Currency), nil
+}
+
+func (moneyCodec) Decode(value interface{}, field reflect.Value) error {
+	var m money
+	if _, err := fmt.Sscanf(value.(string), "%d %s", &m.Cents, &m.Currency); err != nil {
+		return err
+	}
+	field.Set(reflect.ValueOf(m))
+
+	return nil
+}
+
+// invoice is a scheme with a field of custom type
+type invoice struct {
+	Scheme
+	ID    int
+	Total money
+	Tip   *money
+}
+
+func (i invoice) GetID() interface{} {
+	return i.ID
+}
+
+// timestampCodec stores time values as unix timestamps
+type timestampCodec struct{}
+
+func (timestampCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return "BIGINT"
+}
+
+func (timestampCodec) Encode(value interface{}) (interface{}, error) {
+	return value.(time.Time).Unix(), nil
+}
+
+func (timestampCodec) Decode(value interface{}, field reflect.Value) error {
+	field.Set(reflect.ValueOf(time.Unix(value.(int64), 0).UTC()))
+	return nil
+}
+
+// citextCodec stores strings in case insensitive text columns of PostgreSQL
+type citextCodec struct{ stringCodec }
+
+func (citextCodec) ColumnType(driver base.DriverName, t reflect.Type, tags base.SQLTag) string {
+	return "CITEXT"
+}
+
+func init() {
+	RegisterCodec(reflect.TypeOf(money{}), moneyCodec{})
+}
+
+// ----------------------
+//    Test functions
+// ----------------------
+
+func TestRegisterCodec(t *testing.T) {
+	t.Run("tableStructure", func(t *testing.T) {
+		pgModel := makeModel(&invoice{}, base.DBConfig{Driver: base.PG})
+		mysqlModel := makeModel(&invoice{}, base.DBConfig{Driver: base.MySQL})
+
+		assert.Equal(t, "id SERIAL PRIMARY KEY, total MONEY_TEXT, tip MONEY_TEXT", pgModel.getTableStruct().GetInfo())
+		assert.Equal(t, "total TEXT", mysqlModel.getTableStruct()[1].String())
+	})
+
+	t.Run("encode", func(t *testing.T) {
+		data := generateRecordData(&invoice{Total: money{1250, "USD"}, Tip: &money{100, "USD"}}, true)
+
+		assert.Equal(t, "1250 USD", data.Get("total"))
+		assert.Equal(t, "100 USD", data.Get("tip"))
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		scheme := &invoice{}
+		err := fillScheme(scheme, base.RecordMap{"total": "1250 USD", "tip": "100 EUR"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, money{1250, "USD"}, scheme.Total)
+		assert.Equal(t, &money{100, "EUR"}, scheme.Tip)
+	})
+
+	t.Run("decodeError", func(t *testing.T) {
+		err := fillScheme(&invoice{}, base.RecordMap{"total": "free"})
+
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&invoice{}, config)
+		assert.Nil(t, model.Create(&invoice{Total: money{1250, "USD"}}))
+		assert.Nil(t, model.Create(&invoice{Total: money{900, "EUR"}}))
+
+		res, err := model.Where(term.Equal{Field: "total", Value: money{900, "EUR"}}).First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, &invoice{ID: 2, Total: money{900, "EUR"}}, res)
+	})
+
+	t.Run("builtInType", func(t *testing.T) {
+		defer RegisterCodec(timeType, timeCodec{})
+		RegisterCodec(timeType, timestampCodec{})
+
+		model := makeModel(&event{}, base.DBConfig{Driver: base.PG})
+		startedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		data := generateRecordData(&event{StartedAt: startedAt}, true)
+
+		assert.Equal(t, "started_at BIGINT", model.getTableStruct()[3].String())
+		assert.Equal(t, startedAt.Unix(), data.Get("started_at"))
+	})
+
+	t.Run("builtInKind", func(t *testing.T) {
+		defer RegisterKindCodec(reflect.String, stringCodec{})
+		assert.Equal(t, stringCodec{}, LookupCodec(reflect.TypeOf("")))
+
+		RegisterKindCodec(reflect.String, citextCodec{})
+		model := makeModel(&product{}, base.DBConfig{Driver: base.PG})
+
+		assert.Equal(t, citextCodec{}, LookupCodec(reflect.TypeOf("")))
+		assert.Equal(t, "name CITEXT", model.getTableStruct()[1].String())
+	})
+}
+
+func TestCodecOf(t *testing.T) {
+	assert.Equal(t, timeCodec{}, codecOf(timeType))
+	assert.Equal(t, intCodec{}, codecOf(reflect.TypeOf(0)))
+	assert.Equal(t, valuerCodec{kind: intCodec{}}, codecOf(reflect.TypeOf(statusDraft)))
+	assert.Equal(t, setterCodec{kind: stringCodec{}}, codecOf(reflect.TypeOf(upperString(""))))
+	assert.Nil(t, codecOf(reflect.TypeOf(func() {})))
+}
+
+func TestMakeSliceValue(t *testing.T) {
+	assert.Equal(t, int64(12), makeSliceValue(reflect.New(reflect.TypeOf(int64(0))).Elem(), "12").Interface())
+	assert.PanicsWithError(t, "unsupported type: slice of complex128", func() {
+		makeSliceValue(reflect.New(reflect.TypeOf(complex128(0))).Elem(), "1")
+	})
+}
@@ -0,0 +1,42 @@
+package octopus
+
+import (
+	"fmt"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+)
+
+// Errors returned by model and builder operations. Errors of database
+// drivers are mapped to them, so they could be checked with errors.Is
+// regardless of the database driver.
+var (
+	ErrNotFound            = base.ErrNotFound
+	ErrDuplicateKey        = base.ErrDuplicateKey
+	ErrConstraintViolation = base.ErrConstraintViolation
+	ErrUnsupportedType     = base.ErrUnsupportedType
+	ErrInvalidID           = base.ErrInvalidID
+	ErrInvalidDriver       = base.ErrInvalidDriver
+	ErrInvalidTx           = base.ErrInvalidTx
+	ErrValidation          = base.ErrValidation
+)
+
+// handleError converts panics raised during an operation to an error set
+// on `err`, and maps database driver errors to octopus errors. It should
+// be deferred by operations, so they never crash the application.
+func handleError(err *error) {
+	if r := recover(); r != nil {
+		*err = panicError(r)
+	}
+
+	*err = clients.TranslateError(*err)
+}
+
+// panicError returns the error of recovered panic value `r`
+func panicError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+
+	return fmt.Errorf("%v", r)
+}
@@ -0,0 +1,115 @@
+package octopus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// unknownCondition is a condition type which is not supported by clients
+type unknownCondition struct{}
+
+func (unknownCondition) GetField() string { return "name" }
+
+func (unknownCondition) GetValue() interface{} { return nil }
+
+func initErrorModel(t *testing.T) Model {
+	t.Helper()
+
+	config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+	clients.DropMemoryDatabase(t.Name())
+
+	model := makeModel(&account{}, config)
+	assert.Nil(t, model.EnsureIndex(base.Index{Columns: []string{"name"}, Unique: true}))
+	assert.Nil(t, model.Create(&account{Name: "John", Age: 20}))
+
+	return model
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestHandleError(t *testing.T) {
+	t.Run("panicError", func(t *testing.T) {
+		err := func() (err error) {
+			defer handleError(&err)
+			panic(errTest)
+		}()
+
+		assert.Equal(t, errTest, err)
+	})
+
+	t.Run("panicValue", func(t *testing.T) {
+		err := func() (err error) {
+			defer handleError(&err)
+			panic("something went wrong")
+		}()
+
+		assert.Equal(t, errTest, err)
+	})
+
+	t.Run("driverError", func(t *testing.T) {
+		cause := &pq.Error{Code: "23505"}
+		err := func() (err error) {
+			defer handleError(&err)
+			return cause
+		}()
+
+		assert.True(t, errors.Is(err, ErrDuplicateKey))
+
+		var pqErr *pq.Error
+		assert.True(t, errors.As(err, &pqErr))
+		assert.Equal(t, cause, pqErr)
+	})
+}
+
+func TestModel_errors(t *testing.T) {
+	t.Run("notFound", func(t *testing.T) {
+		model := initErrorModel(t)
+
+		res, err := model.Find(10)
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		res, err = model.Where().Skip(1).First()
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("duplicateKey", func(t *testing.T) {
+		model := initErrorModel(t)
+
+		err := model.Create(&account{Name: "John", Age: 30})
+
+		assert.True(t, errors.Is(err, ErrDuplicateKey))
+	})
+
+	t.Run("invalidDriver", func(t *testing.T) {
+		model := makeModel(&account{}, base.DBConfig{Driver: "invalid"})
+
+		assert.True(t, errors.Is(model.Create(&account{Name: "John"}), ErrInvalidDriver))
+		assert.True(t, errors.Is(model.EnsureIndex(), ErrInvalidDriver))
+
+		_, err := model.Where().Limit(1).All()
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+	})
+
+	t.Run("unsupportedCondition", func(t *testing.T) {
+		model := initErrorModel(t)
+
+		n, err := model.Where(unknownCondition{}).OrderBy(base.Sort{Column: "name"}).Count()
+
+		assert.Equal(t, 0, n)
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+		assert.Nil(t, model.client)
+	})
+}
@@ -2,10 +2,12 @@ package octopus
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Kamva/nautilus"
 	"github.com/Kamva/nautilus/types"
@@ -14,7 +16,7 @@ import (
 	"github.com/globalsign/mgo/bson"
 )
 
-func fillScheme(scheme base.Scheme, data base.RecordMap) {
+func fillScheme(scheme base.Scheme, data base.RecordMap) error {
 	fieldsData := getSchemeData(scheme)
 
 	for _, fieldData := range fieldsData {
@@ -29,16 +31,107 @@ func fillScheme(scheme base.Scheme, data base.RecordMap) {
 			}
 
 			if _, ok := data[fieldName]; ok {
-				setFieldValue(scheme, fieldData.Name, data[fieldName])
+				if err := setField(scheme, fieldData.Name, data[fieldName]); err != nil {
+					return err
+				}
 			}
 		}
 	}
+
+	return nil
+}
+
+// resetScheme sets all fields of scheme to their zero value, so fields which
+// are not fetched are not left with values of a previous fetch.
+func resetScheme(scheme base.Scheme) {
+	v := reflect.ValueOf(scheme).Elem()
+	v.Set(reflect.Zero(v.Type()))
+}
+
+// decodeDataSet decodes `dataSet` into `result`, which is a pointer to a
+// `base.RecordDataSet` or to a slice of schemes. Elements of `[]base.Scheme`
+// are instances of `scheme` type.
+func decodeDataSet(dataSet base.RecordDataSet, result interface{}, scheme base.Scheme) error {
+	if set, ok := result.(*base.RecordDataSet); ok {
+		*set = dataSet
+		return nil
+	}
+
+	value := reflect.ValueOf(result)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("%w: cannot decode documents into %T", base.ErrUnsupportedType, result)
+	}
+
+	slice := value.Elem()
+	elemType := slice.Type().Elem()
+	schemeType := elemType
+	switch {
+	case elemType == reflect.TypeOf((*base.Scheme)(nil)).Elem():
+		schemeType = reflect.TypeOf(scheme).Elem()
+	case elemType.Kind() == reflect.Ptr:
+		schemeType = elemType.Elem()
+	}
+
+	items := reflect.MakeSlice(slice.Type(), 0, len(dataSet))
+	for _, data := range dataSet {
+		item, ok := reflect.New(schemeType).Interface().(base.Scheme)
+		if !ok {
+			return fmt.Errorf("%w: %s is not a scheme", base.ErrUnsupportedType, schemeType)
+		}
+
+		if err := fillScheme(item, *data.GetMap()); err != nil {
+			return err
+		}
+		if err := runHook(item, afterFind); err != nil {
+			return err
+		}
+
+		if elemType.Kind() == reflect.Struct {
+			items = reflect.Append(items, reflect.ValueOf(item).Elem())
+		} else {
+			items = reflect.Append(items, reflect.ValueOf(item))
+		}
+	}
+	slice.Set(items)
+
+	return nil
+}
+
+// setField sets `value` on `name` field of scheme, and returns error if value
+// could not be set on the field, e.g. when the column type is not expected.
+func setField(scheme base.Scheme, name string, value interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: cannot set %s field: %v", base.ErrUnsupportedType, name, r)
+		}
+	}()
+
+	field := reflect.ValueOf(scheme).Elem().FieldByName(name)
+	if err = decodeField(value, field); err != nil && !errors.Is(err, base.ErrUnsupportedType) {
+		return fmt.Errorf("%w: cannot set %s field: %v", base.ErrUnsupportedType, name, err)
+	}
+
+	return err
 }
 
 func getSchemeData(scheme base.Scheme) []nautilus.FieldData {
 	fieldsData, err := nautilus.GetStructFieldsData(scheme)
 	shark.PanicIfErrorWithMessage(err, fmt.Sprintf("Invalid scheme %v", scheme))
-	return fieldsData
+
+	// Fields of embedded Timestamps and SoftDelete are promoted to the
+	// scheme fields.
+	promoted := make([]nautilus.FieldData, 0, len(fieldsData))
+	for _, fieldData := range fieldsData {
+		if fieldData.Anonymous && (fieldData.Type == timestampsType || fieldData.Type == softDeleteType) {
+			embeddedData, err := nautilus.GetStructFieldsData(fieldData.Value)
+			shark.PanicIfError(err)
+			promoted = append(promoted, embeddedData...)
+		} else {
+			promoted = append(promoted, fieldData)
+		}
+	}
+
+	return promoted
 }
 
 func parseTag(data nautilus.FieldData) base.SQLTag {
@@ -61,183 +154,231 @@ func parseTag(data nautilus.FieldData) base.SQLTag {
 		tag["column"] = tagValue
 	}
 
+	// Relation fields hold related records, which are not columns of table
+	if getRelationKind(tag) != "" {
+		tag["ignore"] = "true"
+	}
+
 	return tag
 }
 
-func setFieldValue(scheme base.Scheme, field string, value interface{}) {
-	v := reflect.ValueOf(scheme).Elem()
+func generateRecordData(scheme base.Scheme, insert bool) *base.RecordData {
+	fieldsData := getSchemeData(scheme)
+	data := base.ZeroRecordData()
+	key := getKey(scheme)
 
-	fieldVal := v.FieldByName(field)
+	for _, fieldData := range fieldsData {
+		tagData := parseTag(fieldData)
 
-	switch fieldVal.Kind() {
-	case reflect.Bool:
-		fieldVal.SetBool(value.(bool))
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if val, ok := value.(int); ok {
-			fieldVal.SetInt(int64(val))
-		} else if val, ok := value.(int8); ok {
-			fieldVal.SetInt(int64(val))
-		} else if val, ok := value.(int16); ok {
-			fieldVal.SetInt(int64(val))
-		} else if val, ok := value.(int32); ok {
-			fieldVal.SetInt(int64(val))
-		} else {
-			fieldVal.SetInt(value.(int64))
-		}
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-		if val, ok := value.(uint); ok {
-			fieldVal.SetUint(uint64(val))
-		} else if val, ok := value.(uint8); ok {
-			fieldVal.SetUint(uint64(val))
-		} else if val, ok := value.(uint16); ok {
-			fieldVal.SetUint(uint64(val))
-		} else if val, ok := value.(uint32); ok {
-			fieldVal.SetUint(uint64(val))
-		} else {
-			// Most Databases treated uint types as int type
-			if val, ok := value.(int); ok {
-				fieldVal.SetUint(uint64(val))
-			} else if val, ok := value.(int8); ok {
-				fieldVal.SetUint(uint64(val))
-			} else if val, ok := value.(int16); ok {
-				fieldVal.SetUint(uint64(val))
-			} else if val, ok := value.(int32); ok {
-				fieldVal.SetUint(uint64(val))
+		if _, ok := tagData["ignore"]; !ok && !fieldData.Anonymous && fieldData.Exported {
+			var fieldName string
+			if name, ok := tagData["column"]; ok {
+				fieldName = name
 			} else {
-				fieldVal.SetUint(uint64(value.(int64)))
+				fieldName = nautilus.ToSnake(fieldData.Name)
 			}
+
+			// If we are inserting, new record we should skip empty columns if it
+			// is set as null, or if it is empty ObjectID when driver is set to
+			// mongodb.
+			// If we are updating, we should only skip identifier field, despite
+			// of its value.
+			// Creation time is never updated
+			if _, created := tagData["created_at"]; created && !insert {
+				continue
+			}
+
+			_, nullable := tagData["null"]
+			if shouldSkipField(insert, nullable, fieldData.Value, fieldName, key) {
+				continue
+			}
+
+			value, err := encodeField(fieldData.Value)
+			shark.PanicIfError(err)
+
+			data.Set(fieldName, value)
 		}
-	case reflect.Uint64:
-		f64, err := strconv.ParseFloat(value.(string), 64)
-		shark.PanicIfError(err)
-		fieldVal.SetUint(uint64(f64))
-	case reflect.Float32, reflect.Float64:
-		if val, ok := value.(float32); ok {
-			fieldVal.SetFloat(float64(val))
-		} else {
-			fieldVal.SetFloat(value.(float64))
-		}
-	case reflect.String:
-		if oid, ok := value.(bson.ObjectId); ok {
-			fieldVal.Set(reflect.ValueOf(oid))
-		} else {
-			fieldVal.SetString(value.(string))
+	}
+
+	return data
+}
+
+func shouldSkipField(insert bool, nullable bool, value interface{}, fieldName string, key base.Key) bool {
+	// Primary key is generated by database if it's not set on insert
+	// (e.g. natural keys like codes), and it is never updated. Columns
+	// of composite keys are never generated, so they're always inserted.
+	if hasColumn(key, fieldName) {
+		return !insert || (!key.IsComposite() && isZero(value))
+	} else if insert {
+		return (nullable && isZero(value)) || (isObjectID(value) && isZero(value))
+	}
+
+	return false
+}
+
+// setTimestamps sets fields of scheme tagged with `updated_at` to current
+// time. Fields tagged with `created_at` are set too on create, unless they
+// are already set.
+func setTimestamps(scheme base.Scheme, create bool) {
+	now := currentTime()
+
+	for _, fieldData := range getSchemeData(scheme) {
+		tagData := parseTag(fieldData)
+		_, created := tagData["created_at"]
+		_, updated := tagData["updated_at"]
+
+		if _, ok := tagData["ignore"]; ok || !fieldData.Exported {
+			continue
 		}
-	case reflect.Map:
-		// If the value is string, it is probably, a serialized format of map.
-		if strVal, ok := value.(string); ok {
-			data := fieldVal.Addr().Interface().(*types.JSONMap)
-			err := json.Unmarshal([]byte(strVal), data)
-			shark.PanicIfError(err)
-		} else {
-			b, _ := json.Marshal(value)
-			field := fieldVal.Addr().Interface()
-			_ = json.Unmarshal(b, field)
+
+		if updated || (created && create && isZeroTime(fieldData.Value)) {
+			setTime(reflect.ValueOf(scheme).Elem().FieldByName(fieldData.Name), fieldData.Name, now)
 		}
-	case reflect.Array, reflect.Slice:
-		// If field type is slice or array and returning data is a string, it is
-		// possible that an array data is saved serialized array. (Mostly arrays
-		// in PostgreSQL)
-		if strVal, ok := value.(string); ok {
-			// Remove start and end character from
-			valBytes := []byte(strVal)
-			s := string(valBytes[1 : len(valBytes)-1])
-
-			// if value contains `","` it means that it is array of json
-			// so for preventing conflict in splitting the string we
-			// replace `","` with `"|"` and split string by |
-			values := make([]string, 0)
-			if strings.Contains(s, `","`) {
-				s = strings.Replace(s, `","`, `"|"`, -1)
-				values = strings.Split(s, "|")
+	}
+}
+
+// currentTime returns current time in UTC, truncated to microseconds which
+// is the precision of most databases.
+func currentTime() time.Time {
+	return timeNow().UTC().Truncate(time.Microsecond)
+}
+
+// setTime sets time `t` on `field` which should be of type time.Time or
+// *time.Time.
+func setTime(field reflect.Value, name string, t time.Time) {
+	switch field.Type() {
+	case timeType:
+		field.Set(reflect.ValueOf(t))
+	case reflect.PtrTo(timeType):
+		field.Set(reflect.ValueOf(&t))
+	default:
+		panic(fmt.Errorf("%w: timestamp field %s should be time.Time", base.ErrUnsupportedType, name))
+	}
+}
+
+// isZeroTime checks whether time `value`, or the time it points to, is zero
+func isZeroTime(value interface{}) bool {
+	switch t := value.(type) {
+	case time.Time:
+		return t.IsZero()
+	case *time.Time:
+		return t == nil || t.IsZero()
+	}
+
+	return true
+}
+
+// getColumns returns columns of scheme fields
+func getColumns(scheme base.Scheme) []string {
+	columns := make([]string, 0)
+
+	for _, fieldData := range getSchemeData(scheme) {
+		tagData := parseTag(fieldData)
+
+		if _, ok := tagData["ignore"]; !ok && !fieldData.Anonymous && fieldData.Exported {
+			if name, ok := tagData["column"]; ok {
+				columns = append(columns, name)
 			} else {
-				values = strings.Split(s, ",")
+				columns = append(columns, nautilus.ToSnake(fieldData.Name))
 			}
+		}
+	}
 
-			slice := reflect.MakeSlice(fieldVal.Type(), len(values), len(values))
+	return columns
+}
+
+// getDeletedAtField returns name and column of scheme field tagged with
+// `deleted_at`, or empty strings if scheme is not soft deleted.
+func getDeletedAtField(scheme base.Scheme) (name string, column string) {
+	for _, fieldData := range getSchemeData(scheme) {
+		tagData := parseTag(fieldData)
 
-			for i, value := range values {
-				x := slice.Index(i)
-				x.Set(makeSliceValue(x, value))
+		_, deletedAt := tagData["deleted_at"]
+		if _, ok := tagData["ignore"]; !ok && deletedAt && fieldData.Exported {
+			if column, ok := tagData["column"]; ok {
+				return fieldData.Name, column
 			}
 
-			fieldVal.Set(slice)
-		} else {
-			// Here, we assume that the returning data is slice or array.
-			fieldVal.Set(reflect.ValueOf(value))
-		}
-	case reflect.Struct:
-		data := fieldVal.Addr().Interface()
-		var b []byte
-		var err error
-
-		// Check if the value is the serialization of field value, convert the
-		// string to bytes (PostgreSQL). If not (struct or map value for MongoDB
-		// sub documents) we serialize the struct or map to json and then in both
-		// situations, deserialize the bytes to defined struct format in field.
-		if strVal, ok := value.(string); ok {
-			b = []byte(strVal)
-		} else {
-			b, err = json.Marshal(value)
+			return fieldData.Name, nautilus.ToSnake(fieldData.Name)
 		}
+	}
+
+	return "", ""
+}
 
-		err = json.Unmarshal(b, data)
+// deletedAtData returns record data setting `column` to deletion time `t`,
+// which is nil for restoring the record.
+func deletedAtData(column string, t *time.Time) base.RecordData {
+	var value interface{}
+	if t != nil {
+		encoded, err := encodeField(*t)
 		shark.PanicIfError(err)
-	case reflect.Ptr:
-		if value != nil {
-			v := reflect.ValueOf(value)
-
-			// if the value is not a pointer vale
-			if v.Kind() != reflect.Ptr {
-				rv := reflect.New(reflect.TypeOf(value))
-				rv.Elem().Set(v)
-				v = rv
-			}
+		value = encoded
+	}
 
-			fieldVal.Set(v)
-		}
-	default:
-		panic(fmt.Sprintf("Unsupported type [%s]", fieldVal.Type().String()))
+	return *base.NewRecordData([]string{column}, base.RecordMap{column: value})
+}
+
+// setDeletedAt sets deletion time `t` on `name` field of scheme, which is
+// nil for restoring the record. Field should be of type *time.Time.
+func setDeletedAt(scheme base.Scheme, name string, t *time.Time) {
+	field := reflect.ValueOf(scheme).Elem().FieldByName(name)
+	if field.Type() != reflect.PtrTo(timeType) {
+		panic(fmt.Errorf("%w: deleted_at field %s should be *time.Time", base.ErrUnsupportedType, name))
 	}
+
+	field.Set(reflect.ValueOf(t))
 }
 
-func generateRecordData(scheme base.Scheme, insert bool) *base.RecordData {
+// getKey returns primary key of scheme, which consists of columns of fields
+// tagged with `pk` if scheme has more than one of them (composite keys),
+// and is the column returned by `GetKeyName` otherwise.
+func getKey(scheme base.Scheme) base.Key {
 	fieldsData := getSchemeData(scheme)
-	data := base.ZeroRecordData()
+	key := make(base.Key, 0)
 
 	for _, fieldData := range fieldsData {
 		tagData := parseTag(fieldData)
 
-		if _, ok := tagData["ignore"]; !ok && !fieldData.Anonymous && fieldData.Exported {
-			var fieldName string
+		_, pk := tagData["pk"]
+		if _, ok := tagData["ignore"]; !ok && pk && !fieldData.Anonymous && fieldData.Exported {
 			if name, ok := tagData["column"]; ok {
-				fieldName = name
+				key = append(key, name)
 			} else {
-				fieldName = nautilus.ToSnake(fieldData.Name)
+				key = append(key, nautilus.ToSnake(fieldData.Name))
 			}
+		}
+	}
 
-			// If we are inserting, new record we should skip empty columns if it
-			// is set as null, or if it is empty ObjectID when driver is set to
-			// mongodb.
-			// If we are updating, we should only skip identifier field, despite
-			// of its value.
-			_, nullable := tagData["null"]
-			if shouldSkipField(insert, nullable, fieldData.Value, fieldName, scheme) {
-				continue
-			}
+	if key.IsComposite() {
+		return key
+	}
 
-			data.Set(fieldName, fieldData.Value)
-		}
+	return base.Key{scheme.GetKeyName()}
+}
+
+// getID returns the ID of scheme record, which is the tuple of values of key
+// columns for composite keys, and the value returned by `GetID` otherwise.
+func getID(scheme base.Scheme, key base.Key) interface{} {
+	if !key.IsComposite() {
+		return scheme.GetID()
 	}
 
-	return data
+	data := generateRecordData(scheme, true)
+	id := make([]interface{}, 0, len(key))
+	for _, column := range key {
+		id = append(id, data.Get(column))
+	}
+
+	return id
 }
 
-func shouldSkipField(insert bool, nullable bool, value interface{}, fieldName string, scheme base.Scheme) bool {
-	if fieldName == scheme.GetKeyName() {
-		return true
-	} else if insert {
-		return (nullable && isZero(value)) || (isObjectID(value) && isZero(value))
+// hasColumn checks whether `column` is one of `key` columns
+func hasColumn(key base.Key, column string) bool {
+	for _, keyColumn := range key {
+		if keyColumn == column {
+			return true
+		}
 	}
 
 	return false
@@ -296,13 +437,24 @@ func makeSliceValue(elem reflect.Value, value string) reflect.Value {
 		shark.PanicIfError(err)
 		return reflect.ValueOf(data).Elem()
 	default:
-		panic(fmt.Sprintf("Unsupported type [[]%s]", elem.Type().String()))
+		panic(fmt.Errorf("%w: slice of %s", base.ErrUnsupportedType, elem.Type().String()))
 	}
 
 	shark.PanicIfError(err)
 	return reflect.ValueOf(cVal)
 }
 
+// isIntegerKind checks whether `kind` is a signed or unsigned integer kind
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
 func isZero(value interface{}) bool {
 	t := reflect.TypeOf(value)
 	if !t.Comparable() {
@@ -0,0 +1,94 @@
+package octopus
+
+import "github.com/Kamva/octopus/base"
+
+// BeforeCreateHook is implemented by schemes that should be prepared before
+// they are created, e.g. hashing passwords. Returned error aborts creation.
+type BeforeCreateHook interface {
+	BeforeCreate() error
+}
+
+// AfterCreateHook is implemented by schemes that should be notified after
+// they are created.
+type AfterCreateHook interface {
+	AfterCreate() error
+}
+
+// BeforeUpdateHook is implemented by schemes that should be prepared before
+// they are updated. Returned error aborts the update.
+type BeforeUpdateHook interface {
+	BeforeUpdate() error
+}
+
+// AfterUpdateHook is implemented by schemes that should be notified after
+// they are updated, e.g. for invalidating caches.
+type AfterUpdateHook interface {
+	AfterUpdate() error
+}
+
+// BeforeDeleteHook is implemented by schemes that should be checked before
+// they are deleted. Returned error aborts the deletion.
+type BeforeDeleteHook interface {
+	BeforeDelete() error
+}
+
+// AfterDeleteHook is implemented by schemes that should be notified after
+// they are deleted.
+type AfterDeleteHook interface {
+	AfterDelete() error
+}
+
+// AfterFindHook is implemented by schemes that should be prepared after they
+// are fetched from database, e.g. for computing fields.
+type AfterFindHook interface {
+	AfterFind() error
+}
+
+// hook is a point in lifecycle of schemes where hooks are run
+type hook int
+
+const (
+	beforeCreate hook = iota
+	afterCreate
+	beforeUpdate
+	afterUpdate
+	beforeDelete
+	afterDelete
+	afterFind
+)
+
+// runHook runs `h` hook of scheme if it's implemented by scheme
+func runHook(scheme base.Scheme, h hook) error {
+	switch h {
+	case beforeCreate:
+		if s, ok := scheme.(BeforeCreateHook); ok {
+			return s.BeforeCreate()
+		}
+	case afterCreate:
+		if s, ok := scheme.(AfterCreateHook); ok {
+			return s.AfterCreate()
+		}
+	case beforeUpdate:
+		if s, ok := scheme.(BeforeUpdateHook); ok {
+			return s.BeforeUpdate()
+		}
+	case afterUpdate:
+		if s, ok := scheme.(AfterUpdateHook); ok {
+			return s.AfterUpdate()
+		}
+	case beforeDelete:
+		if s, ok := scheme.(BeforeDeleteHook); ok {
+			return s.BeforeDelete()
+		}
+	case afterDelete:
+		if s, ok := scheme.(AfterDeleteHook); ok {
+			return s.AfterDelete()
+		}
+	case afterFind:
+		if s, ok := scheme.(AfterFindHook); ok {
+			return s.AfterFind()
+		}
+	}
+
+	return nil
+}
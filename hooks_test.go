@@ -0,0 +1,183 @@
+package octopus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	. "github.com/Kamva/octopus/internal"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+var errEmptyName = errors.New("empty name")
+var errLocked = errors.New("locked author")
+
+// deletedAuthors are names of authors which AfterDelete hook is run on
+var deletedAuthors []string
+
+// author is a scheme implementing all hooks, which records the hooks run
+type author struct {
+	Scheme
+	ID       int
+	Name     string
+	Password string
+	Slug     string
+	hooks    []string
+}
+
+func (a author) GetID() interface{} {
+	return a.ID
+}
+
+func (a *author) BeforeCreate() error {
+	a.hooks = append(a.hooks, "BeforeCreate")
+	if a.Name == "" {
+		return errEmptyName
+	}
+	a.Password = strings.Repeat("*", len(a.Password))
+
+	return nil
+}
+
+func (a *author) AfterCreate() error {
+	a.hooks = append(a.hooks, "AfterCreate")
+	return nil
+}
+
+func (a *author) BeforeUpdate() error {
+	a.hooks = append(a.hooks, "BeforeUpdate")
+	a.Slug = strings.ToLower(a.Name)
+
+	return nil
+}
+
+func (a *author) AfterUpdate() error {
+	a.hooks = append(a.hooks, "AfterUpdate")
+	return nil
+}
+
+func (a *author) BeforeDelete() error {
+	a.hooks = append(a.hooks, "BeforeDelete")
+	if a.Name == "Locked" {
+		return errLocked
+	}
+
+	return nil
+}
+
+func (a *author) AfterDelete() error {
+	a.hooks = append(a.hooks, "AfterDelete")
+	deletedAuthors = append(deletedAuthors, a.Name)
+	return nil
+}
+
+func (a *author) AfterFind() error {
+	a.hooks = append(a.hooks, "AfterFind")
+	return nil
+}
+
+// ----------------------
+//    Test functions
+// ----------------------
+
+func TestHooks(t *testing.T) {
+	t.Run("model", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&author{}, config)
+		a := &author{Name: "John", Password: "secret"}
+
+		assert.Nil(t, model.Create(a))
+		assert.Equal(t, "******", a.Password)
+
+		assert.Nil(t, model.Update(a))
+		assert.Equal(t, "john", a.Slug)
+
+		assert.Nil(t, model.Delete(a))
+		assert.Equal(
+			t,
+			[]string{"BeforeCreate", "AfterCreate", "BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete"},
+			a.hooks,
+		)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&author{}, config)
+		assert.Nil(t, model.Create(&author{Name: "John"}))
+		assert.Nil(t, model.Create(&author{Name: "Jane"}))
+
+		res, err := model.Find(1)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"AfterFind"}, res.(*author).hooks)
+
+		all, err := model.Where(term.Equal{Field: "name", Value: "Jane"}).All()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"AfterFind"}, all[0].(*author).hooks)
+	})
+
+	t.Run("builderUpdate", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&author{}, config)
+		assert.Nil(t, model.Create(&author{Name: "John"}))
+
+		a := &author{Name: "Johnny"}
+		n, err := model.Where(term.Equal{Field: "id", Value: 1}).Update(a)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{"BeforeUpdate", "AfterUpdate"}, a.hooks)
+		assert.Equal(t, "johnny", a.Slug)
+	})
+
+	t.Run("builderDelete", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+		defer func() { deletedAuthors = nil }()
+
+		model := makeModel(&author{}, config)
+		assert.Nil(t, model.Create(&author{Name: "John"}))
+		assert.Nil(t, model.Create(&author{Name: "Jane"}))
+		assert.Nil(t, model.Create(&author{Name: "Locked"}))
+		deletedAuthors = nil
+
+		n, err := model.Where(term.In{Field: "name", Values: []interface{}{"John", "Jane"}}).Delete()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{"John", "Jane"}, deletedAuthors)
+
+		n, err = model.Where().ForceDelete()
+		assert.Equal(t, errLocked, err)
+		assert.Equal(t, 0, n)
+
+		count, err := model.Where().Count()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		model := makeModel(&author{}, base.DBConfig{Driver: base.PG})
+
+		client := new(Client)
+		model.client = client
+
+		a := &author{}
+		err := model.Create(a)
+
+		assert.Equal(t, errEmptyName, err)
+		assert.Equal(t, []string{"BeforeCreate"}, a.hooks)
+		client.AssertNotCalled(t, "Insert")
+	})
+}
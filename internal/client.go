@@ -29,13 +29,13 @@ func (_m *Client) CreateTable(tableName string, info base.TableInfo) error {
 	return r0
 }
 
-// DeleteByID provides a mock function with given fields: tableName, id
-func (_m *Client) DeleteByID(tableName string, id interface{}) error {
-	ret := _m.Called(tableName, id)
+// DeleteByID provides a mock function with given fields: tableName, key, id
+func (_m *Client) DeleteByID(tableName string, key base.Key, id interface{}) error {
+	ret := _m.Called(tableName, key, id)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}) error); ok {
-		r0 = rf(tableName, id)
+	if rf, ok := ret.Get(0).(func(string, base.Key, interface{}) error); ok {
+		r0 = rf(tableName, key, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -57,20 +57,20 @@ func (_m *Client) EnsureIndex(tableName string, index base.Index) error {
 	return r0
 }
 
-// FindByID provides a mock function with given fields: tableName, id
-func (_m *Client) FindByID(tableName string, id interface{}) (base.RecordData, error) {
-	ret := _m.Called(tableName, id)
+// FindByID provides a mock function with given fields: tableName, key, id
+func (_m *Client) FindByID(tableName string, key base.Key, id interface{}) (base.RecordData, error) {
+	ret := _m.Called(tableName, key, id)
 
 	var r0 base.RecordData
-	if rf, ok := ret.Get(0).(func(string, interface{}) base.RecordData); ok {
-		r0 = rf(tableName, id)
+	if rf, ok := ret.Get(0).(func(string, base.Key, interface{}) base.RecordData); ok {
+		r0 = rf(tableName, key, id)
 	} else {
 		r0 = ret.Get(0).(base.RecordData)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, interface{}) error); ok {
-		r1 = rf(tableName, id)
+	if rf, ok := ret.Get(1).(func(string, base.Key, interface{}) error); ok {
+		r1 = rf(tableName, key, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -78,13 +78,13 @@ func (_m *Client) FindByID(tableName string, id interface{}) (base.RecordData, e
 	return r0, r1
 }
 
-// Insert provides a mock function with given fields: tableName, data
-func (_m *Client) Insert(tableName string, data *base.RecordData) error {
-	ret := _m.Called(tableName, data)
+// Insert provides a mock function with given fields: tableName, key, data
+func (_m *Client) Insert(tableName string, key base.Key, data *base.RecordData) error {
+	ret := _m.Called(tableName, key, data)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *base.RecordData) error); ok {
-		r0 = rf(tableName, data)
+	if rf, ok := ret.Get(0).(func(string, base.Key, *base.RecordData) error); ok {
+		r0 = rf(tableName, key, data)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -115,13 +115,13 @@ func (_m *Client) Query(tableName string, conditions ...base.Condition) base.Que
 	return r0
 }
 
-// UpdateByID provides a mock function with given fields: tableName, id, data
-func (_m *Client) UpdateByID(tableName string, id interface{}, data base.RecordData) error {
-	ret := _m.Called(tableName, id, data)
+// UpdateByID provides a mock function with given fields: tableName, key, id, data
+func (_m *Client) UpdateByID(tableName string, key base.Key, id interface{}, data base.RecordData) error {
+	ret := _m.Called(tableName, key, id, data)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, interface{}, base.RecordData) error); ok {
-		r0 = rf(tableName, id, data)
+	if rf, ok := ret.Get(0).(func(string, base.Key, interface{}, base.RecordData) error); ok {
+		r0 = rf(tableName, key, id, data)
 	} else {
 		r0 = ret.Error(0)
 	}
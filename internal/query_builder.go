@@ -10,6 +10,35 @@ type QueryBuilder struct {
 	mock.Mock
 }
 
+// Aggregate provides a mock function with given fields: aggregations
+func (_m *QueryBuilder) Aggregate(aggregations ...base.Aggregation) (base.RecordDataSet, error) {
+	_va := make([]interface{}, len(aggregations))
+	for _i := range aggregations {
+		_va[_i] = aggregations[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 base.RecordDataSet
+	if rf, ok := ret.Get(0).(func(...base.Aggregation) base.RecordDataSet); ok {
+		r0 = rf(aggregations...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(base.RecordDataSet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(...base.Aggregation) error); ok {
+		r1 = rf(aggregations...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // All provides a mock function with given fields:
 func (_m *QueryBuilder) All() (base.RecordDataSet, error) {
 	ret := _m.Called()
@@ -54,6 +83,27 @@ func (_m *QueryBuilder) Count() (int, error) {
 	return r0, r1
 }
 
+// CountDistinct provides a mock function with given fields: field
+func (_m *QueryBuilder) CountDistinct(field string) (int, error) {
+	ret := _m.Called(field)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(field)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(field)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields:
 func (_m *QueryBuilder) Delete() (int, error) {
 	ret := _m.Called()
@@ -75,6 +125,50 @@ func (_m *QueryBuilder) Delete() (int, error) {
 	return r0, r1
 }
 
+// Distinct provides a mock function with given fields: field
+func (_m *QueryBuilder) Distinct(field string) ([]interface{}, error) {
+	ret := _m.Called(field)
+
+	var r0 []interface{}
+	if rf, ok := ret.Get(0).(func(string) []interface{}); ok {
+		r0 = rf(field)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(field)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exists provides a mock function with given fields:
+func (_m *QueryBuilder) Exists() (bool, error) {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // First provides a mock function with given fields:
 func (_m *QueryBuilder) First() (base.RecordData, error) {
 	ret := _m.Called()
@@ -96,6 +190,50 @@ func (_m *QueryBuilder) First() (base.RecordData, error) {
 	return r0, r1
 }
 
+// GroupBy provides a mock function with given fields: fields
+func (_m *QueryBuilder) GroupBy(fields ...string) base.QueryBuilder {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 base.QueryBuilder
+	if rf, ok := ret.Get(0).(func(...string) base.QueryBuilder); ok {
+		r0 = rf(fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(base.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
+// Having provides a mock function with given fields: conditions
+func (_m *QueryBuilder) Having(conditions ...base.Condition) base.QueryBuilder {
+	_va := make([]interface{}, len(conditions))
+	for _i := range conditions {
+		_va[_i] = conditions[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 base.QueryBuilder
+	if rf, ok := ret.Get(0).(func(...base.Condition) base.QueryBuilder); ok {
+		r0 = rf(conditions...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(base.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
 // Limit provides a mock function with given fields: n
 func (_m *QueryBuilder) Limit(n int) base.QueryBuilder {
 	ret := _m.Called(n)
@@ -112,6 +250,28 @@ func (_m *QueryBuilder) Limit(n int) base.QueryBuilder {
 	return r0
 }
 
+// Omit provides a mock function with given fields: fields
+func (_m *QueryBuilder) Omit(fields ...string) base.QueryBuilder {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 base.QueryBuilder
+	if rf, ok := ret.Get(0).(func(...string) base.QueryBuilder); ok {
+		r0 = rf(fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(base.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
 // OrderBy provides a mock function with given fields: sorts
 func (_m *QueryBuilder) OrderBy(sorts ...base.Sort) base.QueryBuilder {
 	_va := make([]interface{}, len(sorts))
@@ -134,6 +294,28 @@ func (_m *QueryBuilder) OrderBy(sorts ...base.Sort) base.QueryBuilder {
 	return r0
 }
 
+// Select provides a mock function with given fields: fields
+func (_m *QueryBuilder) Select(fields ...string) base.QueryBuilder {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 base.QueryBuilder
+	if rf, ok := ret.Get(0).(func(...string) base.QueryBuilder); ok {
+		r0 = rf(fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(base.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
 // Skip provides a mock function with given fields: n
 func (_m *QueryBuilder) Skip(n int) base.QueryBuilder {
 	ret := _m.Called(n)
@@ -1,33 +1,62 @@
 package octopus
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/Kamva/nautilus"
 	"github.com/Kamva/nautilus/url"
 	"github.com/Kamva/octopus/base"
 	"github.com/Kamva/octopus/clients"
-	"github.com/Kamva/shark"
+	"github.com/Kamva/octopus/term"
+	"github.com/globalsign/mgo/bson"
 )
 
 var newMongo = clients.NewMongoDB
 var newSQLServer = clients.NewSQLServer
 var newPostgres = clients.NewPostgres
+var newMySQL = clients.NewMySQL
+var newSQLite = clients.NewSQLite
+var newMemory = clients.NewMemory
+var timeNow = time.Now
 
 // Configurator is a function for configuring Model attributes.
 // Usually it is used for adding indices or configure table
 // name, or even configuring drivers with custom drivers
 type Configurator func(*Model)
 
+// trashScope determines which soft deleted records are queried by model
+type trashScope int
+
+const (
+	withoutTrashed trashScope = iota
+	withTrashed
+	onlyTrashed
+)
+
+// pipelineRunner is a client running aggregation pipelines, which are
+// MongoDB clients in or out of a transaction.
+type pipelineRunner interface {
+	Aggregate(collectionName string, pipeline *clients.Pipeline) (base.RecordDataSet, error)
+}
+
 // Model is an object that responsible for interacting
 type Model struct {
 	scheme    base.Scheme
 	tableName string
 	config    base.DBConfig
 	client    base.Client
+	tx        *Tx
+	ctx       context.Context
+	trashed   trashScope
+
+	// err is the error of binding the model, e.g. to a transaction on
+	// another database, which is returned by all of its operations.
+	err error
 }
 
 // Initiate initialize the model and prepare it for interacting with database
@@ -54,95 +83,452 @@ func (m *Model) Initiate(scheme base.Scheme, config base.DBConfig, configurators
 
 // EnsureIndex checks for table/collection existence in database, if not found tries
 // to create it. Then it ensures that given indices are exists on table/collection.
-func (m *Model) EnsureIndex(indices ...base.Index) {
-	m.PrepareClient()
+func (m *Model) EnsureIndex(indices ...base.Index) (err error) {
+	defer handleError(&err)
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
 	defer m.CloseClient()
 
-	if m.config.Driver != base.Mongo {
-		err := m.client.CreateTable(m.tableName, m.getTableStruct())
-		shark.PanicIfError(err)
+	// Collections of mongo and tables of in-memory database have no
+	// structure and they're created on first insertion.
+	if m.config.Driver != base.Mongo && m.config.Driver != base.Memory {
+		if err = m.client.CreateTable(m.tableName, m.getTableStruct()); err != nil {
+			return err
+		}
 	}
 
 	for _, index := range indices {
-		err := m.client.EnsureIndex(m.tableName, index)
-		shark.PanicIfError(err)
+		if err = m.client.EnsureIndex(m.tableName, index); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
-// Find search for a record/document in model table/collection match with given ID
-func (m *Model) Find(id interface{}) (base.Scheme, error) {
-	m.PrepareClient()
+// Find search for a record/document in model table/collection match with given ID.
+// Records of tables with composite primary key are found by values of all key
+// columns in order of scheme fields, e.g. `Find(userID, groupID)`.
+func (m *Model) Find(id ...interface{}) (scheme base.Scheme, err error) {
+	defer handleError(&err)
+
+	key := getKey(m.scheme)
+	if !key.IsComposite() && len(id) != 1 {
+		return nil, fmt.Errorf("%w: expected a single id, got %d values", base.ErrInvalidID, len(id))
+	}
+
+	if err = m.PrepareClient(); err != nil {
+		return nil, err
+	}
 	defer m.CloseClient()
 
-	result, err := m.client.FindByID(m.tableName, id)
+	var recordID interface{} = id
+	if !key.IsComposite() {
+		recordID = id[0]
+	}
+
+	result, err := m.client.FindByID(m.tableName, key, recordID)
 
 	if result.Length() == 0 {
 		return nil, err
 	}
 
-	fillScheme(m.scheme, *result.GetMap())
+	if _, column := getDeletedAtField(m.scheme); column != "" && !m.inTrashScope(result.Get(column)) {
+		return nil, base.ErrNotFound
+	}
+
+	resetScheme(m.scheme)
+	if fillErr := fillScheme(m.scheme, *result.GetMap()); fillErr != nil {
+		return nil, fillErr
+	}
+
+	if hookErr := runHook(m.scheme, afterFind); hookErr != nil {
+		return nil, hookErr
+	}
 
 	return m.scheme, err
 }
 
 // Where returns a Query Builder based on given conditions on model table/collection
 // that you can fetch, update or delete records/document match the query.
-func (m *Model) Where(query ...base.Condition) base.Builder {
-	m.PrepareClient()
+// Errors of preparing the query are returned by the fetch, update or delete.
+func (m *Model) Where(query ...base.Condition) (builder base.Builder) {
+	if err := m.PrepareClient(); err != nil {
+		return newErrorBuilder(err, m)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m.CloseClient()
+			builder = newErrorBuilder(panicError(r), m)
+		}
+	}()
+
+	if condition := m.trashCondition(); condition != nil {
+		// Slice is limited to its length, so appending never modifies
+		// the conditions array of caller.
+		query = append(query[:len(query):len(query)], condition)
+	}
 
 	queryBuilder := m.client.Query(m.tableName, query...)
 	return NewBuilder(queryBuilder, m)
 }
 
+// Load fetches records related to `scheme` by `relations`, which are names
+// of relation fields of scheme, and sets them on the relation fields. SQL
+// and in-memory databases run a query for each relation, and MongoDB runs
+// a `$lookup` aggregation. Soft deleted related records are not loaded.
+func (m *Model) Load(scheme base.Scheme, relations ...string) (err error) {
+	defer handleError(&err)
+
+	for _, name := range relations {
+		rel, err := getRelation(scheme, name)
+		if err != nil {
+			return err
+		}
+
+		if err = m.loadRelation(scheme, rel); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadRelation fetches and sets records related to `scheme` by `rel`
+func (m *Model) loadRelation(scheme base.Scheme, rel relation) error {
+	related := &Model{tx: m.tx, ctx: m.ctx, err: m.err}
+	related.Initiate(reflect.New(rel.related).Interface().(base.Scheme), m.config)
+
+	// Belongs-to relations match the foreign key of scheme with the key
+	// of related records, and others match the key of scheme with the
+	// foreign key of related records.
+	localKey, foreignKey := getKey(scheme), base.Key{rel.fk}
+	if rel.kind == belongsTo {
+		localKey, foreignKey = base.Key{rel.fk}, getKey(related.scheme)
+	}
+	if localKey.IsComposite() || foreignKey.IsComposite() {
+		return fmt.Errorf("%w: relations of composite keys", base.ErrUnsupportedType)
+	}
+
+	value := scheme.GetID()
+	if rel.kind == belongsTo {
+		value = generateRecordData(scheme, true).Get(rel.fk)
+	}
+
+	var schemes []base.Scheme
+	var err error
+	switch {
+	case value == nil || isZero(value):
+		// Records with no foreign key value have no related records
+	case m.config.Driver == base.Mongo:
+		schemes, err = m.lookup(scheme, rel, related, localKey[0], foreignKey[0])
+	default:
+		builder := related.Where(term.Equal{Field: foreignKey[0], Value: value})
+		if rel.kind != hasMany {
+			builder = builder.Limit(1)
+		}
+		schemes, err = builder.All()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	setRelation(scheme, rel, schemes)
+
+	return nil
+}
+
+// lookup fetches records of `related` model related to `scheme` by `rel`,
+// by running a `$lookup` aggregation on the scheme document.
+func (m *Model) lookup(scheme base.Scheme, rel relation, related *Model, localKey string, foreignKey string) ([]base.Scheme, error) {
+	pipeline := clients.NewPipeline().
+		Match(term.Equal{Field: scheme.GetKeyName(), Value: scheme.GetID()}).
+		Lookup(related.tableName, localKey, foreignKey, "related").
+		Project(bson.M{"related": 1})
+
+	// Aggregation runs on a copy of model, as it closes the client of
+	// model, which may be prepared by the caller.
+	parent := *m
+	parent.client = nil
+
+	var documents base.RecordDataSet
+	if err := parent.Aggregate(pipeline, &documents); err != nil || len(documents) == 0 {
+		return nil, err
+	}
+
+	_, deletedAt := getDeletedAtField(related.scheme)
+	items, _ := documents[0].Get("related").([]interface{})
+	dataSet := make(base.RecordDataSet, 0, len(items))
+	for _, item := range items {
+		document, _ := item.(bson.M)
+		if deletedAt != "" && !related.inTrashScope(document[deletedAt]) {
+			continue
+		}
+
+		data := base.ZeroRecordData()
+		for key, value := range document {
+			data.Set(key, value)
+		}
+		dataSet = append(dataSet, *data)
+	}
+
+	if rel.kind != hasMany && len(dataSet) > 1 {
+		dataSet = dataSet[:1]
+	}
+
+	var schemes []base.Scheme
+	err := decodeDataSet(dataSet, &schemes, related.scheme)
+
+	return schemes, err
+}
+
+// Raw returns a builder fetching records of raw `query` with `args`, which
+// are decoded into model scheme. SQL queries are strings with placeholders
+// of the database driver, and MongoDB queries are `bson.M` filters of
+// model collection. Soft deleted records are not excluded from raw queries.
+func (m *Model) Raw(query interface{}, args ...interface{}) (builder *RawBuilder) {
+	if err := m.PrepareClient(); err != nil {
+		return &RawBuilder{model: m, err: err}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m.CloseClient()
+			builder = &RawBuilder{model: m, err: panicError(r)}
+		}
+	}()
+
+	client, ok := m.client.(base.RawQuerier)
+	if !ok {
+		panic(fmt.Errorf("%w: raw queries are not supported by %s driver", base.ErrInvalidDriver, m.config.Driver))
+	}
+
+	return &RawBuilder{query: client.Raw(m.tableName, query, args...), model: m}
+}
+
+// Exec executes raw `command` with `args` and returns number of affected
+// records. SQL commands are strings with placeholders of the database
+// driver, and MongoDB commands are database commands, e.g. a `bson.D`.
+func (m *Model) Exec(command interface{}, args ...interface{}) (n int, err error) {
+	defer handleError(&err)
+
+	if err = m.PrepareClient(); err != nil {
+		return 0, err
+	}
+	defer m.CloseClient()
+
+	client, ok := m.client.(base.RawQuerier)
+	if !ok {
+		return 0, fmt.Errorf("%w: raw queries are not supported by %s driver", base.ErrInvalidDriver, m.config.Driver)
+	}
+
+	return client.Exec(command, args...)
+}
+
 // Create inserts the given filled scheme into model table/collection and return
 // inserted record/document or error if there was any fault in data insertion.
-func (m *Model) Create(data base.Scheme) error {
-	m.PrepareClient()
+func (m *Model) Create(data base.Scheme) (err error) {
+	defer handleError(&err)
+
+	if err = runHook(data, beforeCreate); err != nil {
+		return err
+	}
+
+	if err = validate(data); err != nil {
+		return err
+	}
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
 	defer m.CloseClient()
 
+	setTimestamps(data, true)
 	recordData := generateRecordData(data, true)
-	err := m.client.Insert(m.tableName, recordData)
-
-	if err != nil {
+	if err = m.client.Insert(m.tableName, getKey(data), recordData); err != nil {
 		return err
 	}
 
-	fillScheme(data, *recordData.GetMap())
+	if err = fillScheme(data, *recordData.GetMap()); err != nil {
+		return err
+	}
 
-	return nil
+	return runHook(data, afterCreate)
 }
 
 // Update find a record/document that match with data ID and updates its field
 // with data values. It'll return error if anything went wrong during update
-func (m *Model) Update(data base.Scheme) error {
-	m.PrepareClient()
+func (m *Model) Update(data base.Scheme) (err error) {
+	defer handleError(&err)
+
+	if err = runHook(data, beforeUpdate); err != nil {
+		return err
+	}
+
+	if err = validate(data); err != nil {
+		return err
+	}
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
 	defer m.CloseClient()
 
+	setTimestamps(data, false)
+	key := getKey(data)
 	recordData := generateRecordData(data, false)
 
-	return m.client.UpdateByID(m.tableName, data.GetID(), *recordData)
+	if err = m.client.UpdateByID(m.tableName, key, getID(data, key), *recordData); err != nil {
+		return err
+	}
+
+	return runHook(data, afterUpdate)
 }
 
 // Delete find a record/document that match with data ID and remove it from
-// related table/collection. It will return error if anything went wrong
-func (m *Model) Delete(data base.Scheme) error {
-	m.PrepareClient()
+// related table/collection. Records of soft deleted schemes are not removed,
+// and their deletion time is set instead. It will return error if anything
+// went wrong
+func (m *Model) Delete(data base.Scheme) (err error) {
+	defer handleError(&err)
+
+	if err = runHook(data, beforeDelete); err != nil {
+		return err
+	}
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
+	defer m.CloseClient()
+
+	key := getKey(data)
+	if name, column := getDeletedAtField(data); column != "" {
+		now := currentTime()
+		setDeletedAt(data, name, &now)
+		err = m.client.UpdateByID(m.tableName, key, getID(data, key), deletedAtData(column, &now))
+	} else {
+		err = m.client.DeleteByID(m.tableName, key, getID(data, key))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return runHook(data, afterDelete)
+}
+
+// ForceDelete find a record/document that match with data ID and remove it
+// from related table/collection, even if the scheme is soft deleted.
+func (m *Model) ForceDelete(data base.Scheme) (err error) {
+	defer handleError(&err)
+
+	if err = runHook(data, beforeDelete); err != nil {
+		return err
+	}
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
 	defer m.CloseClient()
 
-	return m.client.DeleteByID(m.tableName, data.GetID())
+	key := getKey(data)
+	if err = m.client.DeleteByID(m.tableName, key, getID(data, key)); err != nil {
+		return err
+	}
+
+	return runHook(data, afterDelete)
 }
 
-// GetClient returns database client.
+// Restore find a soft deleted record/document that match with data ID and
+// clears its deletion time. It returns error if scheme is not soft deleted.
+func (m *Model) Restore(data base.Scheme) (err error) {
+	defer handleError(&err)
+
+	name, column := getDeletedAtField(data)
+	if column == "" {
+		return fmt.Errorf("%w: %T is not soft deleted", base.ErrUnsupportedType, data)
+	}
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
+	defer m.CloseClient()
+
+	key := getKey(data)
+	setDeletedAt(data, name, nil)
+
+	return m.client.UpdateByID(m.tableName, key, getID(data, key), deletedAtData(column, nil))
+}
+
+// WithTrashed returns a copy of model whose queries include soft deleted
+// records too.
+func (m *Model) WithTrashed() *Model {
+	model := *m
+	model.client = nil
+	model.trashed = withTrashed
+
+	return &model
+}
+
+// OnlyTrashed returns a copy of model whose queries only include soft
+// deleted records.
+func (m *Model) OnlyTrashed() *Model {
+	model := *m
+	model.client = nil
+	model.trashed = onlyTrashed
+
+	return &model
+}
+
+// trashCondition returns the condition limiting queries to the trash scope
+// of model, or nil if scheme is not soft deleted or trash is not scoped.
+func (m *Model) trashCondition() base.Condition {
+	_, column := getDeletedAtField(m.scheme)
+	if column == "" {
+		return nil
+	}
+
+	switch m.trashed {
+	case withoutTrashed:
+		return term.IsNull{Field: column}
+	case onlyTrashed:
+		return term.NotNull{Field: column}
+	}
+
+	return nil
+}
+
+// inTrashScope checks whether record with `deletedAt` deletion time is in
+// the trash scope of model.
+func (m *Model) inTrashScope(deletedAt interface{}) bool {
+	switch m.trashed {
+	case withoutTrashed:
+		return deletedAt == nil
+	case onlyTrashed:
+		return deletedAt != nil
+	}
+
+	return true
+}
+
+// GetClient returns database client, or nil if client could not be prepared.
 // Note that client should be closed after use.
 func (m *Model) GetClient() base.Client {
-	m.PrepareClient()
+	_ = m.PrepareClient()
 	return m.client
 }
 
 // GetCollection returns collection object for mongo db.
 func (m *Model) GetCollection() (base.MongoCollection, error) {
-	c := m.GetClient()
-	client, ok := c.(*clients.MongoDB)
+	if err := m.PrepareClient(); err != nil {
+		return nil, err
+	}
+
+	client, ok := m.client.(*clients.MongoDB)
 	if !ok {
 		return nil, errors.New("cannot call GetCollection on a non-mongodb model")
 	}
@@ -150,6 +536,32 @@ func (m *Model) GetCollection() (base.MongoCollection, error) {
 	return client.GetCollection(m.tableName), nil
 }
 
+// Aggregate runs aggregation `pipeline` on model collection and decodes the
+// resulted documents into `result`, which could be a pointer to a
+// `base.RecordDataSet`, to a `[]base.Scheme` of model scheme, or to a slice
+// of any scheme (or pointer to scheme) type. Aggregation pipelines are only
+// supported by MongoDB.
+func (m *Model) Aggregate(pipeline *clients.Pipeline, result interface{}) (err error) {
+	defer handleError(&err)
+
+	if err = m.PrepareClient(); err != nil {
+		return err
+	}
+	defer m.CloseClient()
+
+	client, ok := m.client.(pipelineRunner)
+	if !ok {
+		return fmt.Errorf("%w: aggregation pipelines are only supported by mongodb", base.ErrInvalidDriver)
+	}
+
+	dataSet, err := client.Aggregate(m.tableName, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return decodeDataSet(dataSet, result, m.scheme)
+}
+
 // Guess the table name based on scheme name
 func (m *Model) guessTableName(scheme base.Scheme) string {
 	table := nautilus.Plural(nautilus.ToSnake(nautilus.GetType(scheme)))
@@ -169,64 +581,160 @@ func (m *Model) guessTableName(scheme base.Scheme) string {
 	return table
 }
 
-// PrepareClient Prepare client for further actions
-func (m *Model) PrepareClient() {
+// PrepareClient Prepare client for further actions. It returns error if
+// the database driver is invalid or connecting to database fails.
+func (m *Model) PrepareClient() error {
+	if m.err != nil {
+		return m.err
+	}
+
 	if m.client == nil {
-		userInfo := url.NewUserInfo(m.config.Username, m.config.Password)
-
-		switch m.config.Driver {
-		case base.Mongo:
-			i := &url.URL{
-				Scheme:   "mongodb",
-				UserInfo: userInfo,
-				Host:     m.config.Host,
-				Port:     m.config.Port,
-				Path:     m.config.Database,
-				Query:    m.config.GetOptions(),
-			}
-			con := i.String()
-			m.client = newMongo(con, m.config.Database)
-			break
-		case base.MSSQL:
-			m.config.AddOption("database", m.config.Database)
-			i := &url.URL{
-				Scheme:   "sqlserver",
-				UserInfo: userInfo,
-				Host:     m.config.Host,
-				Port:     m.config.Port,
-				Query:    m.config.GetOptions(),
-			}
-			con := i.String()
-			m.client = newSQLServer(con)
-			break
-		case base.PG:
-			i := &url.URL{
-				Scheme:   "postgres",
-				UserInfo: userInfo,
-				Host:     m.config.Host,
-				Port:     m.config.Port,
-				Path:     m.config.Database,
-				Query:    m.config.GetOptions(),
-			}
-			con := i.String()
-			m.client = newPostgres(con)
-			break
-		default:
-			panic("Invalid database driver")
+		// Models bound to a transaction use the transaction client
+		if m.tx != nil {
+			m.client = m.bindContext(m.tx.client)
+			return nil
+		}
+
+		client, err := acquireClient(&m.config)
+		if err != nil {
+			return err
+		}
+
+		m.client = m.bindContext(client)
+		if m.client != client {
+			client.Close()
 		}
 	}
+
+	return nil
+}
+
+// WithContext returns a copy of model which runs its operations with `ctx`,
+// so cancellation and deadline of context are propagated to the database.
+func (m *Model) WithContext(ctx context.Context) *Model {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	model := *m
+	model.client = nil
+	model.ctx = ctx
+
+	return &model
 }
 
-// CloseClient close and destroy client connection
+// bindContext binds `client` to model context if client supports context
+func (m *Model) bindContext(client base.Client) base.Client {
+	if binder, ok := client.(base.ContextBinder); ok && m.ctx != nil {
+		return binder.WithContext(m.ctx)
+	}
+
+	return client
+}
+
+// openClient opens a new client connected to the database of `config`. Panics
+// of client constructors, e.g. on connection failure, are returned as error.
+func openClient(config *base.DBConfig) (client base.Client, err error) {
+	defer handleError(&err)
+
+	userInfo := url.NewUserInfo(config.Username, config.Password)
+
+	switch config.Driver {
+	case base.Mongo:
+		i := &url.URL{
+			Scheme:   "mongodb",
+			UserInfo: userInfo,
+			Host:     config.Host,
+			Port:     config.Port,
+			Path:     config.Database,
+			Query:    config.GetOptions(),
+		}
+		con := i.String()
+		client = newMongo(con, config.Database)
+		break
+	case base.MSSQL:
+		// Database is passed as an option on a copy of config, since
+		// options map is shared with the config of model.
+		mssqlConfig := base.DBConfig{Options: map[string]string{"database": config.Database}}
+		for key, value := range config.Options {
+			mssqlConfig.AddOption(key, value)
+		}
+		i := &url.URL{
+			Scheme:   "sqlserver",
+			UserInfo: userInfo,
+			Host:     config.Host,
+			Port:     config.Port,
+			Query:    mssqlConfig.GetOptions(),
+		}
+		con := i.String()
+		client = newSQLServer(con)
+		break
+	case base.PG:
+		i := &url.URL{
+			Scheme:   "postgres",
+			UserInfo: userInfo,
+			Host:     config.Host,
+			Port:     config.Port,
+			Path:     config.Database,
+			Query:    config.GetOptions(),
+		}
+		con := i.String()
+		client = newPostgres(con)
+		break
+	case base.MySQL:
+		// MySQL driver does not accept URL as data source name
+		// and uses its own DSN format.
+		con := fmt.Sprintf(
+			"%s@tcp(%s:%s)/%s",
+			getMySQLUserInfo(config), config.Host, config.Port, config.Database,
+		)
+		if options := config.GetOptions(); options != "" {
+			con += "?" + options
+		}
+		client = newMySQL(con)
+		break
+	case base.SQLite:
+		// SQLite database is a file, so `Database` is used as
+		// the file path, or `:memory:` for in-memory database.
+		con := config.Database
+		if options := config.GetOptions(); options != "" {
+			con += "?" + options
+		}
+		client = newSQLite(con)
+		break
+	case base.Memory:
+		client = newMemory(config.Database)
+		break
+	default:
+		return nil, fmt.Errorf("%w: %s", base.ErrInvalidDriver, config.Driver)
+	}
+
+	return client, nil
+}
+
+// getMySQLUserInfo returns the user info part of MySQL DSN
+func getMySQLUserInfo(config *base.DBConfig) string {
+	if config.Password == "" {
+		return config.Username
+	}
+
+	return config.Username + ":" + config.Password
+}
+
+// CloseClient close and destroy client connection. The client of a
+// model bound to a transaction is kept open until transaction ends.
 func (m *Model) CloseClient() {
 	if m.client != nil {
-		m.client.Close()
+		if m.tx == nil {
+			m.client.Close()
+		}
 		m.client = nil
 	}
 }
 
 func (m *Model) getTableStruct() base.TableStructure {
 	fieldsData := getSchemeData(m.scheme)
+	key := getKey(m.scheme)
 
 	tableStructure := make([]base.FieldStructure, 0)
 	for _, fieldData := range fieldsData {
@@ -240,10 +748,20 @@ func (m *Model) getTableStruct() base.TableStructure {
 				fieldName = nautilus.ToSnake(fieldData.Name)
 			}
 
-			if fieldName == m.scheme.GetKeyName() {
-				tagData["ai"] = "true"
-				tagData["id"] = "true"
+			if key.IsComposite() {
+				// Composite keys are declared as a table constraint
+				if _, ok := tagData["pk"]; ok {
+					delete(tagData, "pk")
+					tagData["notnull"] = "true"
+				}
+			} else if fieldName == key[0] {
 				tagData["pk"] = "true"
+
+				// Only integer keys are generated by database
+				if isIntegerKind(fieldData.Type.Kind()) {
+					tagData["ai"] = "true"
+					tagData["id"] = "true"
+				}
 			}
 
 			fieldStructure := base.FieldStructure{
@@ -256,6 +774,11 @@ func (m *Model) getTableStruct() base.TableStructure {
 		}
 	}
 
+	if key.IsComposite() {
+		constraint := fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(key, ", "))
+		tableStructure = append(tableStructure, base.NewConstraint(constraint))
+	}
+
 	return tableStructure
 }
 
@@ -265,75 +788,11 @@ func (m *Model) getMatchingType(t reflect.Type, tags base.SQLTag) string {
 	}
 
 	switch m.config.Driver {
-	case base.PG:
-		return m.getPostgresMatchingType(t, tags)
-	case base.MSSQL:
-		return m.getMSSQLMatchingType(t)
+	case base.PG, base.MSSQL, base.MySQL, base.SQLite:
+		return columnType(m.config.Driver, t, tags)
 	}
 
-	panic("Invalid database driver")
-}
-
-func (m *Model) getPostgresMatchingType(t reflect.Type, tags base.SQLTag) string {
-	switch t.Kind() {
-	case reflect.Bool:
-		return "BOOLEAN"
-	case reflect.Int8, reflect.Int16, reflect.Uint8:
-		if tags["ai"] == "true" {
-			return "SMALLSERIAL"
-		}
-
-		return "SMALLINT"
-	case reflect.Int32, reflect.Int, reflect.Uint16:
-		if tags["ai"] == "true" {
-			return "SERIAL"
-		}
-		return "INT"
-	case reflect.Int64, reflect.Uint32, reflect.Uint:
-		if tags["ai"] == "true" {
-			return "BIGSERIAL"
-		}
-		return "BIGINT"
-	case reflect.Float32:
-		return "REAL"
-	case reflect.Float64:
-		return "FLOAT8"
-	case reflect.Uint64:
-		return "DECIMAL"
-	case reflect.Array, reflect.Slice:
-		return m.getPostgresMatchingType(t.Elem(), tags) + "[]"
-	case reflect.Map, reflect.Struct:
-		return "JSON"
-	case reflect.String:
-		return "TEXT"
-	}
-
-	panic(fmt.Sprintf("Field Type [%s] is not supported. Change type or ignore it with tag", t.Kind().String()))
-}
-
-func (m *Model) getMSSQLMatchingType(t reflect.Type) string {
-	switch t.Kind() {
-	case reflect.Bool:
-		return "BIT"
-	case reflect.Uint8:
-		return "TINYINT"
-	case reflect.Int8, reflect.Int16:
-		return "SMALLINT"
-	case reflect.Int32, reflect.Int, reflect.Uint16:
-		return "INT"
-	case reflect.Int64, reflect.Uint32, reflect.Uint:
-		return "BIGINT"
-	case reflect.Float32:
-		return "REAL"
-	case reflect.Float64:
-		return "FLOAT"
-	case reflect.Uint64:
-		return "DECIMAL"
-	case reflect.String:
-		return "NVARCHAR(MAX)"
-	}
-
-	panic(fmt.Sprintf("Field Type [%s] is not supported. Change type or ignore it with tag", t.Kind().String()))
+	panic(base.ErrInvalidDriver)
 }
 
 func (m *Model) getFieldOptions(tags base.SQLTag) string {
@@ -342,9 +801,13 @@ func (m *Model) getFieldOptions(tags base.SQLTag) string {
 		return m.getPostgresFieldOptions(tags)
 	case base.MSSQL:
 		return m.getMSSQLFieldOptions(tags)
+	case base.MySQL:
+		return m.getMySQLFieldOptions(tags)
+	case base.SQLite:
+		return m.getSQLiteFieldOptions(tags)
 	}
 
-	panic("Invalid database driver")
+	panic(base.ErrInvalidDriver)
 }
 
 func (m *Model) getPostgresFieldOptions(tags base.SQLTag) (options string) {
@@ -415,3 +878,58 @@ func (m *Model) getMSSQLFieldOptions(tags base.SQLTag) (options string) {
 
 	return options
 }
+
+func (m *Model) getMySQLFieldOptions(tags base.SQLTag) (options string) {
+	if _, ok := tags["notnull"]; ok {
+		options += "NOT NULL "
+	} else if _, ok := tags["null"]; ok {
+		options += "NULL "
+	}
+
+	if def, ok := tags["default"]; ok {
+		options += fmt.Sprintf("DEFAULT %s ", def)
+	}
+
+	if _, ok := tags["ai"]; ok {
+		options += "AUTO_INCREMENT "
+	}
+
+	if _, ok := tags["pk"]; ok {
+		options += "PRIMARY KEY "
+	} else if _, ok := tags["unique"]; ok {
+		options += "UNIQUE "
+	}
+
+	if check, ok := tags["check"]; ok {
+		options += fmt.Sprintf("CHECK (%s) ", check)
+	}
+
+	return strings.TrimRight(options, " ")
+}
+
+func (m *Model) getSQLiteFieldOptions(tags base.SQLTag) (options string) {
+	if _, ok := tags["pk"]; ok {
+		options += "PRIMARY KEY "
+		if _, ok := tags["ai"]; ok {
+			options += "AUTOINCREMENT "
+		}
+	} else if _, ok := tags["notnull"]; ok {
+		options += "NOT NULL "
+	} else if _, ok := tags["null"]; ok {
+		options += "NULL "
+	}
+
+	if def, ok := tags["default"]; ok {
+		options += fmt.Sprintf("DEFAULT %s ", def)
+	}
+
+	if _, ok := tags["unique"]; ok {
+		options += "UNIQUE "
+	}
+
+	if check, ok := tags["check"]; ok {
+		options += fmt.Sprintf("CHECK (%s) ", check)
+	}
+
+	return strings.TrimRight(options, " ")
+}
@@ -1,16 +1,22 @@
 package octopus
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Kamva/nautilus/types"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
 	. "github.com/Kamva/octopus/internal"
 	"github.com/Kamva/octopus/term"
 	"github.com/globalsign/mgo/bson"
@@ -33,8 +39,151 @@ var newSQLClientMock = func(url string) base.Client {
 	return mock
 }
 
+// product is a scheme with a natural primary key
+type product struct {
+	Scheme
+	Code string
+	Name string
+}
+
+func (p product) GetID() interface{} {
+	return p.Code
+}
+
+func (p product) GetKeyName() string {
+	return "code"
+}
+
+// membership is a scheme of join table with composite primary key
+type membership struct {
+	Scheme
+	UserID  int `sql:"pk"`
+	GroupID int `sql:"pk"`
+	Role    string
+}
+
+func (m membership) GetID() interface{} {
+	return nil
+}
+
+// event is a scheme with time and nullable fields
+type event struct {
+	Scheme
+	ID        int
+	Title     sql.NullString
+	Level     sql.NullInt64
+	StartedAt time.Time
+	EndedAt   *time.Time
+	Deadline  sql.NullTime
+	Status    status `sql:"type:TEXT"`
+	Code      upperString
+}
+
+func (e event) GetID() interface{} {
+	return e.ID
+}
+
+// post is a scheme with timestamps
+type post struct {
+	Scheme
+	Timestamps
+	ID    int
+	Title string
+}
+
+func (p post) GetID() interface{} {
+	return p.ID
+}
+
+// comment is a scheme with timestamp fields tagged by itself
+type comment struct {
+	Scheme
+	ID       int
+	Body     string
+	PostedAt time.Time  `sql:"created_at"`
+	EditedAt *time.Time `sql:"updated_at;null"`
+}
+
+func (c comment) GetID() interface{} {
+	return c.ID
+}
+
+// customer is a soft deleted scheme
+type customer struct {
+	Scheme
+	SoftDelete
+	ID   int
+	Name string
+}
+
+func (c customer) GetID() interface{} {
+	return c.ID
+}
+
+// invalidTimestamp is a scheme with a timestamp field of invalid type
+type invalidTimestamp struct {
+	Scheme
+	ID        int
+	CreatedAt string `sql:"created_at"`
+}
+
+func (i invalidTimestamp) GetID() interface{} {
+	return i.ID
+}
+
+// status is stored as text by implementing driver.Valuer and sql.Scanner
+type status int
+
+const (
+	statusDraft status = iota
+	statusPublished
+)
+
+func (s status) Value() (driver.Value, error) {
+	if s == statusPublished {
+		return "published", nil
+	}
+
+	return "draft", nil
+}
+
+func (s *status) Scan(src interface{}) error {
+	*s = statusDraft
+	if src == "published" {
+		*s = statusPublished
+	}
+
+	return nil
+}
+
+// upperString is decoded in upper case by implementing bson.Setter
+type upperString string
+
+func (u *upperString) SetBSON(raw bson.Raw) error {
+	var s string
+	if err := raw.Unmarshal(&s); err != nil {
+		return err
+	}
+
+	*u = upperString(strings.ToUpper(s))
+	return nil
+}
+
 var errTest = errors.New("something went wrong")
 
+// contextClientMock is a client supporting context based on Client mock,
+// which returns `bound` client when it is bound to a context.
+type contextClientMock struct {
+	*Client
+	bound *Client
+	ctx   context.Context
+}
+
+func (c *contextClientMock) WithContext(ctx context.Context) base.Client {
+	c.ctx = ctx
+	return c.bound
+}
+
 func makeModel(s base.Scheme, c base.DBConfig, cn ...Configurator) Model {
 	model := Model{}
 	model.Initiate(s, c, cn...)
@@ -88,6 +237,17 @@ func (p Profile) GetSchema() string {
 	return "acc"
 }
 
+type account struct {
+	Scheme
+	ID   int
+	Name string
+	Age  int
+}
+
+func (a account) GetID() interface{} {
+	return a.ID
+}
+
 type pg struct {
 	scheme
 	ID          int
@@ -149,6 +309,58 @@ var mssqlStructure = base.TableStructure{
 	{Name: "text", Type: "NVARCHAR(MAX)", Options: "NOT NULL UNIQUE NONCLUSTERED"},
 }
 
+type mysql struct {
+	scheme
+	ID       int
+	Bool     bool    `sql:"notnull"`
+	TinyInt  int8    `sql:"default:0"`
+	SmallInt uint16  `sql:"unique"`
+	BigInt   int64   `sql:"null"`
+	Unsigned uint64  `sql:"check:unsigned > 0"`
+	Float    float32 `sql:"null"`
+	Double   float64
+	JSON     map[string]interface{}
+	Varchar  string
+	Text     string `sql:"type:TEXT"`
+}
+
+var mysqlStructure = base.TableStructure{
+	{Name: "id", Type: "INT", Options: "AUTO_INCREMENT PRIMARY KEY"},
+	{Name: "bool", Type: "BOOLEAN", Options: "NOT NULL"},
+	{Name: "tiny_int", Type: "TINYINT", Options: "DEFAULT 0"},
+	{Name: "small_int", Type: "SMALLINT UNSIGNED", Options: "UNIQUE"},
+	{Name: "big_int", Type: "BIGINT", Options: "NULL"},
+	{Name: "unsigned", Type: "BIGINT UNSIGNED", Options: "CHECK (unsigned > 0)"},
+	{Name: "float", Type: "FLOAT", Options: "NULL"},
+	{Name: "double", Type: "DOUBLE"},
+	{Name: "json", Type: "JSON"},
+	{Name: "varchar", Type: "VARCHAR(255)"},
+	{Name: "text", Type: "TEXT"},
+}
+
+type sqlite struct {
+	scheme
+	ID       int
+	Bool     bool    `sql:"notnull"`
+	SmallInt int16   `sql:"default:0"`
+	Unsigned uint64  `sql:"unique"`
+	Float    float32 `sql:"null"`
+	Double   float64 `sql:"check:double > 0"`
+	JSON     map[string]interface{}
+	Text     string
+}
+
+var sqliteStructure = base.TableStructure{
+	{Name: "id", Type: "INTEGER", Options: "PRIMARY KEY AUTOINCREMENT"},
+	{Name: "bool", Type: "BOOLEAN", Options: "NOT NULL"},
+	{Name: "small_int", Type: "INTEGER", Options: "DEFAULT 0"},
+	{Name: "unsigned", Type: "TEXT", Options: "UNIQUE"},
+	{Name: "float", Type: "REAL", Options: "NULL"},
+	{Name: "double", Type: "REAL", Options: "CHECK (double > 0)"},
+	{Name: "json", Type: "TEXT"},
+	{Name: "text", Type: "TEXT"},
+}
+
 type pgInvalid struct {
 	scheme
 	Func func()
@@ -364,10 +576,90 @@ func TestModel_EnsureIndex(t *testing.T) {
 			client.On("Close").Return()
 			model.client = client
 
-			assert.Panics(t, func() {
+			err := model.EnsureIndex(index)
+
+			assert.True(t, errors.Is(err, ErrUnsupportedType))
+		})
+	})
+
+	t.Run("mysql", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.MySQL}
+		model := makeModel(&mysql{}, config)
+
+		t.Run("singleIndex", func(t *testing.T) {
+			index := base.Index{Columns: []string{"varchar"}}
+
+			client := new(Client)
+			client.On("Close").Return()
+			client.On("CreateTable", "mysqls", mysqlStructure).Return(nil)
+			client.On("EnsureIndex", "mysqls", index).Return(nil)
+			model.client = client
+
+			assert.NotPanics(t, func() {
 				model.EnsureIndex(index)
 			})
 		})
+
+		t.Run("typePanic", func(t *testing.T) {
+			model := makeModel(&mssqlInvalid{}, config)
+			index := base.Index{Columns: []string{"age"}}
+
+			client := new(Client)
+			client.On("Close").Return()
+			model.client = client
+
+			err := model.EnsureIndex(index)
+
+			assert.True(t, errors.Is(err, ErrUnsupportedType))
+		})
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory}
+		model := makeModel(&Profile{}, config)
+		index := base.Index{Columns: []string{"name"}, Unique: true}
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("EnsureIndex", "profiles", index).Return(nil)
+		model.client = client
+
+		assert.NotPanics(t, func() {
+			model.EnsureIndex(index)
+		})
+		client.AssertNotCalled(t, "CreateTable", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.SQLite}
+		model := makeModel(&sqlite{}, config)
+
+		t.Run("singleIndex", func(t *testing.T) {
+			index := base.Index{Columns: []string{"text"}}
+
+			client := new(Client)
+			client.On("Close").Return()
+			client.On("CreateTable", "sqlites", sqliteStructure).Return(nil)
+			client.On("EnsureIndex", "sqlites", index).Return(nil)
+			model.client = client
+
+			assert.NotPanics(t, func() {
+				model.EnsureIndex(index)
+			})
+		})
+
+		t.Run("typePanic", func(t *testing.T) {
+			model := makeModel(&mssqlInvalid{}, config)
+			index := base.Index{Columns: []string{"age"}}
+
+			client := new(Client)
+			client.On("Close").Return()
+			model.client = client
+
+			err := model.EnsureIndex(index)
+
+			assert.True(t, errors.Is(err, ErrUnsupportedType))
+		})
 	})
 
 	t.Run("sqlServer", func(t *testing.T) {
@@ -412,9 +704,9 @@ func TestModel_EnsureIndex(t *testing.T) {
 			client.On("Close").Return()
 			model.client = client
 
-			assert.Panics(t, func() {
-				model.EnsureIndex(index)
-			})
+			err := model.EnsureIndex(index)
+
+			assert.True(t, errors.Is(err, ErrUnsupportedType))
 		})
 	})
 }
@@ -433,7 +725,7 @@ func TestModel_Find(t *testing.T) {
 
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("FindByID", "profiles", 1).Return(*u, nil)
+		client.On("FindByID", "profiles", mock.Anything, 1).Return(*u, nil)
 		model.client = client
 
 		res, err := model.Find(1)
@@ -449,6 +741,36 @@ func TestModel_Find(t *testing.T) {
 		assert.Equal(t, false, p.Status)
 	})
 
+	t.Run("mysqlTypes", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.MySQL}
+		model := makeModel(&Profile{}, config)
+		u := base.NewRecordData(
+			[]string{"id", "name", "age", "status", "rate", "score", "worth"},
+			base.RecordMap{
+				"id": "1", "name": "Test", "age": int64(21), "status": int64(1),
+				"rate": "8.5", "score": uint64(56), "worth": uint64(7845421000000000000),
+			},
+		)
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("FindByID", "profiles", mock.Anything, 1).Return(*u, nil)
+		model.client = client
+
+		res, err := model.Find(1)
+
+		assert.Nil(t, err)
+
+		p := res.(*Profile)
+
+		assert.Equal(t, 1, p.ID)
+		assert.Equal(t, 21, p.Age)
+		assert.Equal(t, true, p.Status)
+		assert.Equal(t, float32(8.5), p.Rate)
+		assert.Equal(t, uint(56), p.Score)
+		assert.Equal(t, uint64(7845421000000000000), p.Worth)
+	})
+
 	t.Run("mongodbSpecialTypes", func(t *testing.T) {
 		config := base.DBConfig{Driver: base.Mongo}
 		model := makeModel(&MongoSpecial{}, config)
@@ -475,7 +797,7 @@ func TestModel_Find(t *testing.T) {
 		objectID := bson.NewObjectId()
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("FindByID", "mongo_specials", objectID).Return(*u, nil)
+		client.On("FindByID", "mongo_specials", mock.Anything, objectID).Return(*u, nil)
 		model.client = client
 
 		res, err := model.Find(objectID)
@@ -528,7 +850,7 @@ func TestModel_Find(t *testing.T) {
 
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("FindByID", "pg_specials", 1).Return(*u, nil)
+		client.On("FindByID", "pg_specials", mock.Anything, 1).Return(*u, nil)
 		model.client = client
 
 		res, err := model.Find(1)
@@ -621,12 +943,13 @@ func TestModel_Find(t *testing.T) {
 
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("FindByID", "invalids", 1).Return(*u, nil)
+		client.On("FindByID", "invalids", mock.Anything, 1).Return(*u, nil)
 		model.client = client
 
-		assert.Panics(t, func() {
-			_, _ = model.Find(1)
-		})
+		res, err := model.Find(1)
+
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
 	})
 
 	t.Run("unsupportedSliceForSetField", func(t *testing.T) {
@@ -641,12 +964,13 @@ func TestModel_Find(t *testing.T) {
 
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("FindByID", "invalid_2", 1).Return(*u, nil)
+		client.On("FindByID", "invalid_2s", mock.Anything, 1).Return(*u, nil)
 		model.client = client
 
-		assert.Panics(t, func() {
-			_, _ = model.Find(1)
-		})
+		res, err := model.Find(1)
+
+		assert.Nil(t, res)
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
 	})
 
 	t.Run("notFound", func(t *testing.T) {
@@ -655,7 +979,7 @@ func TestModel_Find(t *testing.T) {
 
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("FindByID", "profiles", 98).Return(
+		client.On("FindByID", "profiles", mock.Anything, 98).Return(
 			*base.ZeroRecordData(), errors.New("not found"),
 		)
 		model.client = client
@@ -698,9 +1022,9 @@ func TestModel_Create(t *testing.T) {
 			objectID := bson.NewObjectId()
 			client := new(Client)
 			client.On("Close").Return()
-			client.On("Insert", "users", rData).Return(nil).
+			client.On("Insert", "users", mock.Anything, rData).Return(nil).
 				Run(func(args mock.Arguments) {
-					rd := args.Get(1).(*base.RecordData)
+					rd := args.Get(2).(*base.RecordData)
 					rd.Set("_id", objectID)
 					rd.Set("age", 18)
 				})
@@ -729,9 +1053,9 @@ func TestModel_Create(t *testing.T) {
 			id := rand.Int()
 			client := new(Client)
 			client.On("Close").Return()
-			client.On("Insert", "profiles", rData).Return(nil).
+			client.On("Insert", "profiles", mock.Anything, rData).Return(nil).
 				Run(func(args mock.Arguments) {
-					rd := args.Get(1).(*base.RecordData)
+					rd := args.Get(2).(*base.RecordData)
 					rd.Set("id", id)
 				})
 			model.client = client
@@ -759,9 +1083,9 @@ func TestModel_Create(t *testing.T) {
 			id := rand.Int()
 			client := new(Client)
 			client.On("Close").Return()
-			client.On("Insert", "acc.profiles", rData).Return(nil).
+			client.On("Insert", "acc.profiles", mock.Anything, rData).Return(nil).
 				Run(func(args mock.Arguments) {
-					rd := args.Get(1).(*base.RecordData)
+					rd := args.Get(2).(*base.RecordData)
 					rd.Set("id", id)
 				})
 			model.client = client
@@ -790,7 +1114,7 @@ func TestModel_Create(t *testing.T) {
 
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("Insert", "users", rData).Return(errTest)
+		client.On("Insert", "users", mock.Anything, rData).Return(errTest)
 		model.client = client
 
 		user := User{Name: "Test", Age: 18, Status: false}
@@ -814,7 +1138,7 @@ func TestModel_Update(t *testing.T) {
 		objectID := bson.NewObjectId()
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("UpdateByID", "users", objectID, *rData).Return(nil)
+		client.On("UpdateByID", "users", mock.Anything, objectID, *rData).Return(nil)
 		model.client = client
 
 		user := User{ID: objectID, Name: "Test", Age: 18, Status: false}
@@ -836,7 +1160,7 @@ func TestModel_Update(t *testing.T) {
 		objectID := bson.NewObjectId()
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("UpdateByID", "users", objectID, *rData).Return(errTest)
+		client.On("UpdateByID", "users", mock.Anything, objectID, *rData).Return(errTest)
 		model.client = client
 
 		user := User{ID: objectID, Name: "Test", Age: 18, Status: false}
@@ -855,7 +1179,7 @@ func TestModel_Delete(t *testing.T) {
 		objectID := bson.NewObjectId()
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("DeleteByID", "users", objectID).Return(nil)
+		client.On("DeleteByID", "users", mock.Anything, objectID).Return(nil)
 		model.client = client
 
 		user := User{ID: objectID, Name: "Test", Age: 18, Status: false}
@@ -872,7 +1196,7 @@ func TestModel_Delete(t *testing.T) {
 		objectID := bson.NewObjectId()
 		client := new(Client)
 		client.On("Close").Return()
-		client.On("DeleteByID", "users", objectID).Return(errTest)
+		client.On("DeleteByID", "users", mock.Anything, objectID).Return(errTest)
 		model.client = client
 
 		user := User{ID: objectID, Name: "Test", Age: 18, Status: false}
@@ -932,11 +1256,688 @@ func TestModel_PrepareClient(t *testing.T) {
 		assert.Implements(t, (*base.Client)(nil), model.client)
 	})
 
+	t.Run("mysql", func(t *testing.T) {
+		original := newMySQL
+		defer func() { newMySQL = original }()
+
+		config := base.DBConfig{
+			Driver: base.MySQL, Host: "localhost", Port: "3306", Database: "test",
+			Username: "root", Password: "secret", Options: map[string]string{"charset": "utf8"},
+		}
+		model := makeModel(&User{}, config)
+
+		var dsn string
+		newMySQL = func(url string) base.Client {
+			dsn = url
+			return newSQLClientMock(url)
+		}
+
+		model.PrepareClient()
+
+		assert.Equal(t, "root:secret@tcp(localhost:3306)/test?charset=utf8", dsn)
+		assert.Implements(t, (*base.Client)(nil), model.client)
+	})
+
+	t.Run("sqlite", func(t *testing.T) {
+		original := newSQLite
+		defer func() { newSQLite = original }()
+
+		config := base.DBConfig{
+			Driver: base.SQLite, Database: "/tmp/test.db",
+			Options: map[string]string{"_foreign_keys": "1"},
+		}
+		model := makeModel(&User{}, config)
+
+		var dsn string
+		newSQLite = func(path string) base.Client {
+			dsn = path
+			return newSQLClientMock(path)
+		}
+
+		model.PrepareClient()
+
+		assert.Equal(t, "/tmp/test.db?_foreign_keys=1", dsn)
+		assert.Implements(t, (*base.Client)(nil), model.client)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		original := newMemory
+		defer func() { newMemory = original }()
+
+		config := base.DBConfig{Driver: base.Memory, Database: "test"}
+		model := makeModel(&User{}, config)
+
+		var database string
+		newMemory = func(name string) base.Client {
+			database = name
+			return newSQLClientMock(name)
+		}
+
+		model.PrepareClient()
+
+		assert.Equal(t, "test", database)
+		assert.Implements(t, (*base.Client)(nil), model.client)
+	})
+
 	t.Run("invalidDriver", func(t *testing.T) {
 		config := base.DBConfig{Driver: "invalid"}
 		model := makeModel(&User{}, config)
 
-		assert.Panics(t, model.PrepareClient)
+		err := model.PrepareClient()
+
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
 		assert.Nil(t, model.client)
 	})
 }
+
+func TestModel_customKey(t *testing.T) {
+	t.Run("tableStructure", func(t *testing.T) {
+		pgModel := makeModel(&product{}, base.DBConfig{Driver: base.PG})
+		mysqlModel := makeModel(&product{}, base.DBConfig{Driver: base.MySQL})
+
+		assert.Equal(t, "code TEXT PRIMARY KEY", pgModel.getTableStruct()[0].String())
+		assert.Equal(t, "code VARCHAR(255) PRIMARY KEY", mysqlModel.getTableStruct()[0].String())
+	})
+
+	t.Run("keyName", func(t *testing.T) {
+		model := makeModel(&product{}, base.DBConfig{Driver: base.PG})
+		data := base.NewRecordData([]string{"code", "name"}, base.RecordMap{"code": "P-100", "name": "Phone"})
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("Insert", "products", base.Key{"code"}, data).Return(nil)
+		client.On("DeleteByID", "products", base.Key{"code"}, "P-100").Return(nil)
+		model.client = client
+
+		assert.Nil(t, model.Create(&product{Code: "P-100", Name: "Phone"}))
+
+		model.client = client
+		assert.Nil(t, model.Delete(&product{Code: "P-100"}))
+
+		client.AssertExpectations(t)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&product{}, config)
+		assert.Nil(t, model.Create(&product{Code: "P-100", Name: "Phone"}))
+		assert.Nil(t, model.Update(&product{Code: "P-100", Name: "Smart Phone"}))
+
+		res, err := model.Find("P-100")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &product{Code: "P-100", Name: "Smart Phone"}, res)
+	})
+}
+
+func TestModel_compositeKey(t *testing.T) {
+	t.Run("tableStructure", func(t *testing.T) {
+		model := makeModel(&membership{}, base.DBConfig{Driver: base.PG})
+
+		assert.Equal(
+			t,
+			"user_id INT NOT NULL, group_id INT NOT NULL, role TEXT, PRIMARY KEY (user_id, group_id)",
+			model.getTableStruct().GetInfo(),
+		)
+	})
+
+	t.Run("key", func(t *testing.T) {
+		model := makeModel(&membership{}, base.DBConfig{Driver: base.PG})
+		key := base.Key{"user_id", "group_id"}
+		data := base.NewRecordData([]string{"role"}, base.RecordMap{"role": "admin"})
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("FindByID", "memberships", key, []interface{}{1, 2}).Return(*base.ZeroRecordData(), ErrNotFound)
+		client.On("UpdateByID", "memberships", key, []interface{}{1, 2}, *data).Return(nil)
+		client.On("DeleteByID", "memberships", key, []interface{}{1, 2}).Return(nil)
+
+		model.client = client
+		_, err := model.Find(1, 2)
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		model.client = client
+		assert.Nil(t, model.Update(&membership{UserID: 1, GroupID: 2, Role: "admin"}))
+
+		model.client = client
+		assert.Nil(t, model.Delete(&membership{UserID: 1, GroupID: 2}))
+
+		client.AssertExpectations(t)
+	})
+
+	t.Run("invalidID", func(t *testing.T) {
+		model := makeModel(&User{}, base.DBConfig{Driver: base.PG})
+
+		_, err := model.Find(1, 2)
+
+		assert.True(t, errors.Is(err, ErrInvalidID))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&membership{}, config)
+		assert.Nil(t, model.Create(&membership{UserID: 1, GroupID: 1, Role: "member"}))
+		assert.Nil(t, model.Create(&membership{UserID: 1, GroupID: 2, Role: "member"}))
+		assert.Nil(t, model.Update(&membership{UserID: 1, GroupID: 2, Role: "admin"}))
+
+		err := model.Create(&membership{UserID: 1, GroupID: 2})
+		assert.True(t, errors.Is(err, ErrDuplicateKey))
+
+		res, err := model.Find(1, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &membership{UserID: 1, GroupID: 2, Role: "admin"}, res)
+
+		assert.Nil(t, model.Delete(&membership{UserID: 1, GroupID: 1}))
+		_, err = model.Find(1, 1)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestModel_valueTypes(t *testing.T) {
+	t.Run("tableStructure", func(t *testing.T) {
+		pgModel := makeModel(&event{}, base.DBConfig{Driver: base.PG})
+		mssqlModel := makeModel(&event{}, base.DBConfig{Driver: base.MSSQL})
+		mysqlModel := makeModel(&event{}, base.DBConfig{Driver: base.MySQL})
+		sqliteModel := makeModel(&event{}, base.DBConfig{Driver: base.SQLite})
+
+		assert.Equal(
+			t,
+			"id SERIAL PRIMARY KEY, title TEXT, level BIGINT, started_at TIMESTAMPTZ, ended_at TIMESTAMPTZ, "+
+				"deadline TIMESTAMPTZ, status TEXT, code TEXT",
+			pgModel.getTableStruct().GetInfo(),
+		)
+		assert.Equal(t, "started_at DATETIME2", mssqlModel.getTableStruct()[3].String())
+		assert.Equal(t, "started_at DATETIME(6)", mysqlModel.getTableStruct()[3].String())
+		assert.Equal(t, "started_at DATETIME", sqliteModel.getTableStruct()[3].String())
+	})
+
+	t.Run("recordData", func(t *testing.T) {
+		startedAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+		data := generateRecordData(&event{
+			Title:     sql.NullString{String: "Launch", Valid: true},
+			StartedAt: startedAt,
+			EndedAt:   &startedAt,
+			Status:    statusPublished,
+		}, true)
+
+		assert.Equal(t, "Launch", data.Get("title"))
+		assert.Nil(t, data.Get("level"))
+		assert.Equal(t, startedAt, data.Get("started_at"))
+		assert.Equal(t, startedAt, data.Get("ended_at"))
+		assert.Nil(t, data.Get("deadline"))
+		assert.Equal(t, "published", data.Get("status"))
+	})
+
+	t.Run("fillScheme", func(t *testing.T) {
+		scheme := &event{}
+		err := fillScheme(scheme, base.RecordMap{
+			"title":      "Launch",
+			"level":      int64(2),
+			"started_at": "2020-01-02 03:04:05.000000",
+			"deadline":   "2020-02-01 00:00:00",
+			"status":     "published",
+			"code":       "ev-1",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, sql.NullString{String: "Launch", Valid: true}, scheme.Title)
+		assert.Equal(t, sql.NullInt64{Int64: 2, Valid: true}, scheme.Level)
+		assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), scheme.StartedAt)
+		assert.Equal(t, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), scheme.Deadline.Time)
+		assert.Equal(t, statusPublished, scheme.Status)
+		assert.Equal(t, upperString("EV-1"), scheme.Code)
+	})
+
+	t.Run("invalidTime", func(t *testing.T) {
+		err := fillScheme(&event{}, base.RecordMap{"started_at": "yesterday"})
+
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		endedAt := time.Date(2020, 1, 2, 5, 0, 0, 0, time.UTC)
+		data := &event{
+			Title:     sql.NullString{String: "Launch", Valid: true},
+			StartedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			EndedAt:   &endedAt,
+			Status:    statusPublished,
+			Code:      "EV-1",
+		}
+
+		model := makeModel(&event{}, config)
+		assert.Nil(t, model.Create(data))
+
+		res, err := model.Find(data.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, data, res)
+	})
+}
+
+func TestModel_timestamps(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated := created.Add(time.Hour)
+
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return created }
+
+	t.Run("tableStructure", func(t *testing.T) {
+		pgModel := makeModel(&post{}, base.DBConfig{Driver: base.PG})
+		mssqlModel := makeModel(&comment{}, base.DBConfig{Driver: base.MSSQL})
+
+		assert.Equal(
+			t,
+			"created_at TIMESTAMPTZ, updated_at TIMESTAMPTZ, id SERIAL PRIMARY KEY, title TEXT",
+			pgModel.getTableStruct().GetInfo(),
+		)
+		assert.Equal(t, "posted_at DATETIME2", mssqlModel.getTableStruct()[2].String())
+		assert.Equal(t, "edited_at DATETIME2 NULL", mssqlModel.getTableStruct()[3].String())
+	})
+
+	t.Run("create", func(t *testing.T) {
+		model := makeModel(&post{}, base.DBConfig{Driver: base.PG})
+		data := base.NewRecordData(
+			[]string{"created_at", "updated_at", "title"},
+			base.RecordMap{"created_at": created, "updated_at": created, "title": "Hello"},
+		)
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("Insert", "posts", base.Key{"id"}, data).Return(nil)
+
+		model.client = client
+		p := &post{Title: "Hello"}
+
+		assert.Nil(t, model.Create(p))
+		assert.Equal(t, Timestamps{CreatedAt: created, UpdatedAt: created}, p.Timestamps)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		model := makeModel(&post{}, base.DBConfig{Driver: base.PG})
+		data := base.NewRecordData(
+			[]string{"updated_at", "title"},
+			base.RecordMap{"updated_at": updated, "title": "Hello"},
+		)
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("UpdateByID", "posts", base.Key{"id"}, 1, *data).Return(nil)
+
+		defer func() { timeNow = func() time.Time { return created } }()
+		timeNow = func() time.Time { return updated }
+
+		model.client = client
+		p := &post{ID: 1, Title: "Hello", Timestamps: Timestamps{CreatedAt: created, UpdatedAt: created}}
+
+		assert.Nil(t, model.Update(p))
+		assert.Equal(t, Timestamps{CreatedAt: created, UpdatedAt: updated}, p.Timestamps)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&comment{}, config)
+		assert.Nil(t, model.Create(&comment{Body: "first"}))
+
+		defer func() { timeNow = func() time.Time { return created } }()
+		timeNow = func() time.Time { return updated }
+
+		n, err := model.Where(term.Equal{Field: "id", Value: 1}).Update(&comment{Body: "edited"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		res, err := model.Find(1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &comment{ID: 1, Body: "edited", PostedAt: created, EditedAt: &updated}, res)
+	})
+
+	t.Run("invalidType", func(t *testing.T) {
+		model := makeModel(&invalidTimestamp{}, base.DBConfig{Driver: base.Memory, Database: t.Name()})
+		defer clients.DropMemoryDatabase(t.Name())
+
+		err := model.Create(&invalidTimestamp{})
+
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	})
+}
+
+func TestModel_softDelete(t *testing.T) {
+	deleted := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return deleted }
+
+	t.Run("tableStructure", func(t *testing.T) {
+		pgModel := makeModel(&customer{}, base.DBConfig{Driver: base.PG})
+		mssqlModel := makeModel(&customer{}, base.DBConfig{Driver: base.MSSQL})
+
+		assert.Equal(t, "deleted_at TIMESTAMPTZ NULL, id SERIAL PRIMARY KEY, name TEXT", pgModel.getTableStruct().GetInfo())
+		assert.Equal(t, "deleted_at DATETIME2 NULL", mssqlModel.getTableStruct()[0].String())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		model := makeModel(&customer{}, base.DBConfig{Driver: base.PG})
+		data := base.NewRecordData([]string{"deleted_at"}, base.RecordMap{"deleted_at": deleted})
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("UpdateByID", "customers", base.Key{"id"}, 1, *data).Return(nil)
+		client.On("DeleteByID", "customers", base.Key{"id"}, 1).Return(nil)
+
+		c := &customer{ID: 1, Name: "John"}
+
+		model.client = client
+		assert.Nil(t, model.Delete(c))
+		assert.Equal(t, &deleted, c.DeletedAt)
+
+		model.client = client
+		assert.Nil(t, model.ForceDelete(c))
+
+		client.AssertExpectations(t)
+	})
+
+	t.Run("where", func(t *testing.T) {
+		model := makeModel(&customer{}, base.DBConfig{Driver: base.PG})
+		condition := term.Equal{Field: "name", Value: "John"}
+
+		client := new(Client)
+		queryBuilder := new(QueryBuilder)
+		client.On("Query", "customers", condition, term.IsNull{Field: "deleted_at"}).Return(queryBuilder)
+		client.On("Query", "customers", condition, term.NotNull{Field: "deleted_at"}).Return(queryBuilder)
+		client.On("Query", "customers", condition).Return(queryBuilder)
+
+		model.client = client
+		model.Where(condition)
+
+		trashedModel := model.OnlyTrashed()
+		trashedModel.client = client
+		trashedModel.Where(condition)
+
+		trashedModel = model.WithTrashed()
+		trashedModel.client = client
+		trashedModel.Where(condition)
+
+		client.AssertExpectations(t)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		model := makeModel(&customer{}, base.DBConfig{Driver: base.PG})
+		data := base.NewRecordData(
+			[]string{"id", "name", "deleted_at"},
+			base.RecordMap{"id": 1, "name": "John", "deleted_at": deleted},
+		)
+
+		client := new(Client)
+		client.On("Close").Return()
+		client.On("FindByID", "customers", base.Key{"id"}, 1).Return(*data, nil)
+
+		model.client = client
+		_, err := model.Find(1)
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		trashedModel := model.WithTrashed()
+		trashedModel.client = client
+		res, err := trashedModel.Find(1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &customer{ID: 1, Name: "John", SoftDelete: SoftDelete{DeletedAt: &deleted}}, res)
+	})
+
+	t.Run("notSoftDeleted", func(t *testing.T) {
+		model := makeModel(&post{}, base.DBConfig{Driver: base.Memory, Database: t.Name()})
+		defer clients.DropMemoryDatabase(t.Name())
+
+		assert.True(t, errors.Is(model.Restore(&post{ID: 1}), ErrUnsupportedType))
+
+		_, err := model.Where().Restore()
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&customer{}, config)
+		john, jane, joe := &customer{Name: "John"}, &customer{Name: "Jane"}, &customer{Name: "Joe"}
+		assert.Nil(t, model.Create(john))
+		assert.Nil(t, model.Create(jane))
+		assert.Nil(t, model.Create(joe))
+
+		assert.Nil(t, model.Delete(john))
+		n, err := model.Where(term.Equal{Field: "name", Value: "Jane"}).Delete()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		n, _ = model.Where().Count()
+		assert.Equal(t, 1, n)
+		n, _ = model.WithTrashed().Where().Count()
+		assert.Equal(t, 3, n)
+
+		trashed, err := model.OnlyTrashed().Where().All()
+		assert.Nil(t, err)
+		assert.Len(t, trashed, 2)
+
+		assert.Nil(t, model.Restore(john))
+		n, err = model.OnlyTrashed().Where(term.Equal{Field: "name", Value: "Jane"}).Restore()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		res, err := model.Find(john.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, &customer{ID: 1, Name: "John"}, res)
+
+		n, err = model.Where(term.Equal{Field: "name", Value: "Joe"}).ForceDelete()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+
+		n, _ = model.WithTrashed().Where().Count()
+		assert.Equal(t, 2, n)
+	})
+}
+
+// columnsQueryBuilder is a QueryBuilder mock which columns of table are
+// bound to, like SQL queries.
+type columnsQueryBuilder struct {
+	*QueryBuilder
+	columns []string
+}
+
+func (q *columnsQueryBuilder) BindColumns(columns []string) {
+	q.columns = columns
+}
+
+func TestModel_projection(t *testing.T) {
+	t.Run("omit", func(t *testing.T) {
+		model := makeModel(&post{}, base.DBConfig{Driver: base.PG})
+
+		queryBuilder := new(QueryBuilder)
+		queryBuilder.On("Omit", "title").Return(queryBuilder)
+
+		NewBuilder(queryBuilder, &model).Omit("title")
+
+		queryBuilder.AssertExpectations(t)
+	})
+
+	t.Run("omitBindsColumns", func(t *testing.T) {
+		model := makeModel(&post{}, base.DBConfig{Driver: base.PG})
+
+		queryBuilder := &columnsQueryBuilder{QueryBuilder: new(QueryBuilder)}
+		queryBuilder.On("Omit", "title").Return(queryBuilder)
+
+		NewBuilder(queryBuilder, &model).Omit("title")
+
+		queryBuilder.AssertExpectations(t)
+		assert.Equal(t, []string{"created_at", "updated_at", "id", "title"}, queryBuilder.columns)
+	})
+
+	t.Run("memory", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&product{}, config)
+		assert.Nil(t, model.Create(&product{Code: "A1", Name: "Apple"}))
+		assert.Nil(t, model.Create(&product{Code: "B1", Name: "Apple"}))
+
+		res, err := model.Where().Select("code").First()
+		assert.Nil(t, err)
+		assert.Equal(t, &product{Code: "A1"}, res)
+
+		res, err = model.Where().Omit("code").First()
+		assert.Nil(t, err)
+		assert.Equal(t, &product{Name: "Apple"}, res)
+
+		values, err := model.Where().Distinct("name")
+		assert.Nil(t, err)
+		assert.Equal(t, []interface{}{"Apple"}, values)
+	})
+}
+
+func TestModel_Aggregate(t *testing.T) {
+	t.Run("invalidDriver", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&product{}, config)
+		var results base.RecordDataSet
+		err := model.Aggregate(clients.NewPipeline(), &results)
+
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		dataSet := base.RecordDataSet{
+			*base.NewRecordData([]string{"code", "name"}, base.RecordMap{"code": "A1", "name": "Apple"}),
+			*base.NewRecordData([]string{"code", "name"}, base.RecordMap{"code": "B1", "name": "Banana"}),
+		}
+
+		var records base.RecordDataSet
+		assert.Nil(t, decodeDataSet(dataSet, &records, &product{}))
+		assert.Equal(t, dataSet, records)
+
+		var schemes []base.Scheme
+		assert.Nil(t, decodeDataSet(dataSet, &schemes, &product{}))
+		assert.Equal(t, []base.Scheme{&product{Code: "A1", Name: "Apple"}, &product{Code: "B1", Name: "Banana"}}, schemes)
+
+		var products []product
+		assert.Nil(t, decodeDataSet(dataSet, &products, nil))
+		assert.Equal(t, []product{{Code: "A1", Name: "Apple"}, {Code: "B1", Name: "Banana"}}, products)
+
+		var pointers []*product
+		assert.Nil(t, decodeDataSet(dataSet, &pointers, nil))
+		assert.Equal(t, []*product{{Code: "A1", Name: "Apple"}, {Code: "B1", Name: "Banana"}}, pointers)
+	})
+
+	t.Run("decodeError", func(t *testing.T) {
+		dataSet := base.RecordDataSet{*base.NewRecordData([]string{"code"}, base.RecordMap{"code": "A1"})}
+
+		var names []string
+		assert.True(t, errors.Is(decodeDataSet(dataSet, &names, nil), ErrUnsupportedType))
+		assert.True(t, errors.Is(decodeDataSet(dataSet, product{}, nil), ErrUnsupportedType))
+	})
+}
+
+func TestModel_WithContext(t *testing.T) {
+	t.Run("bound", func(t *testing.T) {
+		original := newPostgres
+		defer func() { newPostgres = original }()
+
+		client := &contextClientMock{Client: new(Client), bound: new(Client)}
+		client.On("Close").Return()
+		client.bound.On("Close").Return()
+		newPostgres = func(url string) base.Client {
+			return client
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		model := makeModel(&User{}, base.DBConfig{Driver: base.PG})
+		ctxModel := model.WithContext(ctx)
+		ctxModel.PrepareClient()
+
+		assert.Equal(t, ctx, client.ctx)
+		assert.Equal(t, client.bound, ctxModel.client)
+		assert.Nil(t, model.ctx)
+		client.AssertCalled(t, "Close")
+
+		ctxModel.CloseClient()
+
+		client.bound.AssertCalled(t, "Close")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&account{}, config)
+		ctxModel := model.WithContext(context.Background())
+
+		assert.Nil(t, ctxModel.Create(&account{Name: "John", Age: 20}))
+		assert.Equal(t, 1, countAccounts(t, model))
+	})
+
+	t.Run("nilContext", func(t *testing.T) {
+		model := makeModel(&User{}, base.DBConfig{Driver: base.PG})
+
+		assert.Panics(t, func() {
+			_ = model.WithContext(nil)
+		})
+	})
+}
+
+func TestModel_memoryDriver(t *testing.T) {
+	defer clients.DropMemoryDatabase("model_test")
+
+	model := makeModel(&account{}, base.DBConfig{Driver: base.Memory, Database: "model_test"})
+	model.EnsureIndex(base.Index{Columns: []string{"name"}, Unique: true})
+
+	for i, name := range []string{"John", "Jane", "Jack"} {
+		a := &account{Name: name, Age: 20 + i}
+		assert.Nil(t, model.Create(a))
+		assert.Equal(t, i+1, a.ID)
+	}
+
+	assert.NotNil(t, model.Create(&account{Name: "Jane"}))
+
+	res, err := model.Find(2)
+	assert.Nil(t, err)
+	assert.Equal(t, &account{ID: 2, Name: "Jane", Age: 21}, res)
+
+	results, err := model.Where(term.GreaterThan{Field: "age", Value: 20}).
+		OrderBy(base.Sort{Column: "age", Descending: true}).All()
+	assert.Nil(t, err)
+	assert.Equal(t, []base.Scheme{
+		&account{ID: 3, Name: "Jack", Age: 22},
+		&account{ID: 2, Name: "Jane", Age: 21},
+	}, results)
+
+	assert.Nil(t, model.Update(&account{ID: 1, Name: "John", Age: 30}))
+	count, err := model.Where(term.GreaterThanEqual{Field: "age", Value: 22}).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = model.Where().CountDistinct("age")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+
+	exists, err := model.Where(term.Equal{Field: "name", Value: "Jack"}).Exists()
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	assert.Nil(t, model.Delete(&account{ID: 1}))
+	count, err = model.Where().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,90 @@
+package octopus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Kamva/octopus/base"
+)
+
+// pools holds connection pools of databases by their config key, so all
+// models connecting to the same database share the same connections.
+var pools = struct {
+	sync.Mutex
+	clients map[string]base.ConnectionPool
+}{clients: make(map[string]base.ConnectionPool)}
+
+// acquireClient returns a client using connection pool of the database of
+// `config`, and opens the pool on first use. Clients that do not support
+// pooling are opened for every call and closed by their callers. Clients are
+// opened without holding the lock, so the pool opened by another goroutine
+// in the meantime is used and the new one is closed.
+func acquireClient(config *base.DBConfig) (base.Client, error) {
+	key := poolKey(config)
+	if pool := getPool(key); pool != nil {
+		return pool.Acquire(), nil
+	}
+
+	client, err := openClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, ok := client.(base.ConnectionPool)
+	if !ok {
+		return client, nil
+	}
+
+	pools.Lock()
+	defer pools.Unlock()
+
+	if opened, ok := pools.clients[key]; ok {
+		pool.Close()
+		return opened.Acquire(), nil
+	}
+
+	pool.ConfigurePool(config.Pool)
+	pools.clients[key] = pool
+
+	return pool.Acquire(), nil
+}
+
+// getPool returns the opened connection pool of `key`, or nil if it's not
+// opened yet.
+func getPool(key string) base.ConnectionPool {
+	pools.Lock()
+	defer pools.Unlock()
+
+	return pools.clients[key]
+}
+
+// CloseAll closes connection pools of all databases. It should be called on
+// application shutdown. A new pool is opened if a model is used afterward.
+func CloseAll() {
+	pools.Lock()
+	defer pools.Unlock()
+
+	for key, pool := range pools.clients {
+		pool.Close()
+		delete(pools.clients, key)
+	}
+}
+
+// poolKey generates the key of connection pool from connection settings of
+// `config`. Table prefix and pool settings does not change the connection,
+// so models with different values of them share the same pool.
+func poolKey(config *base.DBConfig) string {
+	options := make([]string, 0, len(config.Options))
+	for key, value := range config.Options {
+		options = append(options, key+"="+value)
+	}
+	sort.Strings(options)
+
+	return fmt.Sprintf(
+		"%s://%s:%s@%s:%s/%s?%s",
+		config.Driver, config.Username, config.Password,
+		config.Host, config.Port, config.Database, strings.Join(options, "&"),
+	)
+}
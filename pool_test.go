@@ -0,0 +1,201 @@
+package octopus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kamva/octopus/base"
+	. "github.com/Kamva/octopus/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// poolClientMock is a connection pool client based on Client mock
+type poolClientMock struct {
+	*Client
+	config   base.PoolConfig
+	acquired []*Client
+}
+
+func (p *poolClientMock) ConfigurePool(config base.PoolConfig) {
+	p.config = config
+}
+
+func (p *poolClientMock) Acquire() base.Client {
+	client := new(Client)
+	client.On("Close").Return()
+	p.acquired = append(p.acquired, client)
+
+	return client
+}
+
+func mockPostgresPool() (*[]*poolClientMock, func()) {
+	original := newPostgres
+	restore := func() {
+		CloseAll()
+		newPostgres = original
+	}
+
+	opened := make([]*poolClientMock, 0)
+	newPostgres = func(url string) base.Client {
+		client := new(Client)
+		client.On("Close").Return()
+		pool := &poolClientMock{Client: client}
+		opened = append(opened, pool)
+
+		return pool
+	}
+
+	return &opened, restore
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestAcquireClient(t *testing.T) {
+	t.Run("shared", func(t *testing.T) {
+		opened, restore := mockPostgresPool()
+		defer restore()
+
+		pool := base.PoolConfig{MaxOpenConns: 10, ConnMaxLifetime: time.Minute}
+		config := base.DBConfig{Driver: base.PG, Host: "localhost", Database: "test", Pool: pool}
+
+		first, _ := acquireClient(&config)
+		second, _ := acquireClient(&base.DBConfig{Driver: base.PG, Host: "localhost", Database: "test"})
+		other, _ := acquireClient(&base.DBConfig{Driver: base.PG, Host: "localhost", Database: "other"})
+
+		assert.Len(t, *opened, 2)
+		assert.Equal(t, pool, (*opened)[0].config)
+		assert.Equal(t, []*Client{first.(*Client), second.(*Client)}, (*opened)[0].acquired)
+		assert.Equal(t, []*Client{other.(*Client)}, (*opened)[1].acquired)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		original := newPostgres
+		defer func() {
+			CloseAll()
+			newPostgres = original
+		}()
+
+		// Each dial waits for the other one, which only returns in time if
+		// pools lock is not held while dialing.
+		var mu sync.Mutex
+		var dials sync.WaitGroup
+		dials.Add(2)
+		opened := make([]*poolClientMock, 0, 2)
+		newPostgres = func(url string) base.Client {
+			client := new(Client)
+			client.On("Close").Return()
+			pool := &poolClientMock{Client: client}
+
+			mu.Lock()
+			opened = append(opened, pool)
+			mu.Unlock()
+
+			dials.Done()
+			done := make(chan struct{})
+			go func() { dials.Wait(); close(done) }()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+			}
+
+			return pool
+		}
+
+		config := base.DBConfig{Driver: base.PG, Host: "localhost", Database: "test"}
+		clients := make([]base.Client, 2)
+		var wg sync.WaitGroup
+		for i := range clients {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				clients[i], _ = acquireClient(&config)
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Len(t, opened, 2)
+		assert.Len(t, pools.clients, 1)
+
+		shared := pools.clients[poolKey(&config)].(*poolClientMock)
+		assert.Len(t, shared.acquired, 2)
+		for _, pool := range opened {
+			if pool == shared {
+				pool.AssertNotCalled(t, "Close")
+			} else {
+				pool.AssertCalled(t, "Close")
+			}
+		}
+	})
+
+	t.Run("notPooled", func(t *testing.T) {
+		original := newMongo
+		defer func() { newMongo = original }()
+
+		opened := 0
+		newMongo = func(url string, dbName string) base.Client {
+			opened++
+			return newMongoMock(url, dbName)
+		}
+
+		config := base.DBConfig{Driver: base.Mongo}
+		_, _ = acquireClient(&config)
+		_, _ = acquireClient(&config)
+
+		assert.Equal(t, 2, opened)
+	})
+}
+
+func TestCloseAll(t *testing.T) {
+	opened, restore := mockPostgresPool()
+	defer restore()
+
+	config := base.DBConfig{Driver: base.PG, Database: "test"}
+
+	_, _ = acquireClient(&config)
+	CloseAll()
+
+	(*opened)[0].AssertCalled(t, "Close")
+	assert.Empty(t, pools.clients)
+
+	_, _ = acquireClient(&config)
+
+	assert.Len(t, *opened, 2)
+}
+
+func TestPoolKey(t *testing.T) {
+	config := base.DBConfig{
+		Driver: base.PG, Host: "localhost", Port: "5432", Database: "test",
+		Username: "user", Password: "secret", Prefix: "app",
+		Options: map[string]string{"sslmode": "disable", "connect_timeout": "10"},
+	}
+
+	assert.Equal(
+		t,
+		"pg://user:secret@localhost:5432/test?connect_timeout=10&sslmode=disable",
+		poolKey(&config),
+	)
+}
+
+func TestModel_sharedPool(t *testing.T) {
+	opened, restore := mockPostgresPool()
+	defer restore()
+
+	config := base.DBConfig{Driver: base.PG, Database: "test"}
+	model := makeModel(&User{}, config)
+
+	model.PrepareClient()
+	model.CloseClient()
+	model.PrepareClient()
+
+	assert.Len(t, *opened, 1)
+	assert.Len(t, (*opened)[0].acquired, 2)
+	(*opened)[0].acquired[0].AssertCalled(t, "Close")
+	(*opened)[0].AssertNotCalled(t, "Close")
+}
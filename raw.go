@@ -0,0 +1,63 @@
+package octopus
+
+import (
+	"github.com/Kamva/octopus/base"
+)
+
+// RawBuilder is a wrapper around RawQuery that convert RecordData object to
+// model's related scheme.
+type RawBuilder struct {
+	query base.RawQuery
+	model *Model
+
+	// err is the error of preparing the query, which is returned by
+	// the following fetch command.
+	err error
+}
+
+// First fetch data of the first record resulted from the query
+func (b *RawBuilder) First() (scheme base.Scheme, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	data, err := b.query.First()
+	if err != nil {
+		return nil, err
+	}
+
+	resetScheme(b.model.scheme)
+	if err = fillScheme(b.model.scheme, *data.GetMap()); err != nil {
+		return nil, err
+	}
+
+	if err = runHook(b.model.scheme, afterFind); err != nil {
+		return nil, err
+	}
+
+	return b.model.scheme, nil
+}
+
+// All returns all records resulted from the query as model schemes
+func (b *RawBuilder) All() (schemeSet []base.Scheme, err error) {
+	defer handleError(&err)
+	defer b.model.CloseClient()
+
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	dataSet, err := b.query.All()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = decodeDataSet(dataSet, &schemeSet, b.model.scheme); err != nil {
+		return nil, err
+	}
+
+	return schemeSet, nil
+}
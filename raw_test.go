@@ -0,0 +1,133 @@
+package octopus
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	. "github.com/Kamva/octopus/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// rawClientMock is a client supporting raw queries based on Client mock
+type rawClientMock struct {
+	*Client
+	*RawQuerier
+}
+
+func makeRawModel(scheme base.Scheme) (Model, *rawClientMock) {
+	client := &rawClientMock{Client: new(Client), RawQuerier: new(RawQuerier)}
+	client.Client.On("Close").Return()
+
+	model := makeModel(scheme, base.DBConfig{Driver: base.PG})
+	model.client = client
+
+	return model, client
+}
+
+// ----------------------
+//    Test functions
+// ----------------------
+
+func TestModel_Raw(t *testing.T) {
+	sqlQuery := "SELECT * FROM products WHERE name LIKE $1"
+
+	t.Run("first", func(t *testing.T) {
+		model, client := makeRawModel(&product{})
+		query := new(RawQuery)
+		query.On("First").Return(*base.NewRecordData([]string{"code", "name"}, base.RecordMap{"code": "A1", "name": "Apple"}), nil)
+		client.RawQuerier.On("Raw", "products", sqlQuery, "A%").Return(query)
+
+		res, err := model.Raw(sqlQuery, "A%").First()
+
+		assert.Nil(t, err)
+		assert.Equal(t, &product{Code: "A1", Name: "Apple"}, res)
+		client.Client.AssertCalled(t, "Close")
+	})
+
+	t.Run("all", func(t *testing.T) {
+		model, client := makeRawModel(&author{})
+		query := new(RawQuery)
+		query.On("All").Return(base.RecordDataSet{
+			*base.NewRecordData([]string{"id", "name"}, base.RecordMap{"id": 1, "name": "John Doe"}),
+			*base.NewRecordData([]string{"id", "name"}, base.RecordMap{"id": 2, "name": "Jane Doe"}),
+		}, nil)
+		client.RawQuerier.On("Raw", "authors", sqlQuery, "J%").Return(query)
+
+		res, err := model.Raw(sqlQuery, "J%").All()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []base.Scheme{
+			&author{ID: 1, Name: "John Doe", hooks: []string{"AfterFind"}},
+			&author{ID: 2, Name: "Jane Doe", hooks: []string{"AfterFind"}},
+		}, res)
+	})
+
+	t.Run("queryError", func(t *testing.T) {
+		query := new(RawQuery)
+		query.On("First").Return(base.RecordData{}, base.ErrNotFound)
+		query.On("All").Return(nil, errTest)
+
+		model, client := makeRawModel(&product{})
+		client.RawQuerier.On("Raw", "products", sqlQuery).Return(query)
+		_, err := model.Raw(sqlQuery).First()
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		model, client = makeRawModel(&product{})
+		client.RawQuerier.On("Raw", "products", sqlQuery).Return(query)
+		_, err = model.Raw(sqlQuery).All()
+		assert.Equal(t, errTest, err)
+	})
+
+	t.Run("invalidDriver", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&product{}, config)
+
+		_, err := model.Raw(sqlQuery).First()
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+
+		_, err = model.Raw(sqlQuery).All()
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+	})
+}
+
+func TestModel_Exec(t *testing.T) {
+	command := "UPDATE products SET name = $1 WHERE code = $2"
+
+	t.Run("success", func(t *testing.T) {
+		model, client := makeRawModel(&product{})
+		client.RawQuerier.On("Exec", command, "Apple", "A1").Return(1, nil)
+
+		n, err := model.Exec(command, "Apple", "A1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		client.Client.AssertCalled(t, "Close")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		model, client := makeRawModel(&product{})
+		client.RawQuerier.On("Exec", command).Return(0, errTest)
+
+		_, err := model.Exec(command)
+
+		assert.Equal(t, errTest, err)
+	})
+
+	t.Run("invalidDriver", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&product{}, config)
+		_, err := model.Exec(command)
+
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+	})
+}
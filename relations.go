@@ -0,0 +1,125 @@
+package octopus
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Kamva/nautilus"
+	"github.com/Kamva/octopus/base"
+)
+
+// relationKind is the kind of relation between schemes, which is the tag
+// declaring the relation on scheme field.
+type relationKind string
+
+const (
+	belongsTo relationKind = "belongs_to"
+	hasOne    relationKind = "has_one"
+	hasMany   relationKind = "has_many"
+)
+
+var relationKinds = []relationKind{belongsTo, hasOne, hasMany}
+
+// relation is a relation of scheme to records of another scheme, which is
+// declared by tags of the scheme field holding the related records.
+type relation struct {
+	field string
+	kind  relationKind
+
+	// fk is the foreign key column, which is a column of scheme table in
+	// belongs-to relations, and a column of related table otherwise.
+	fk string
+
+	// related is the type of related scheme
+	related reflect.Type
+}
+
+// getRelationKind returns the kind of relation declared by `tags`, or an
+// empty kind if tags declare no relation.
+func getRelationKind(tags base.SQLTag) relationKind {
+	for _, kind := range relationKinds {
+		if _, ok := tags[string(kind)]; ok {
+			return kind
+		}
+	}
+
+	return ""
+}
+
+// getRelation returns the relation declared on `name` field of scheme.
+// Foreign key is `<field>_id` for belongs-to relations, and `<scheme>_id`
+// otherwise, unless it is set by `fk` tag. Value of relation tag is optional,
+// and if it's set, it should be the type name of related scheme, regardless of
+// case.
+func getRelation(scheme base.Scheme, name string) (relation, error) {
+	for _, fieldData := range getSchemeData(scheme) {
+		if fieldData.Name != name {
+			continue
+		}
+
+		tags := parseTag(fieldData)
+		rel := relation{field: name, kind: getRelationKind(tags), fk: tags["fk"], related: fieldData.Type}
+		if rel.kind == "" {
+			return rel, fmt.Errorf("%w: %s field is not a relation", base.ErrUnsupportedType, name)
+		}
+
+		if rel.fk == "" && rel.kind == belongsTo {
+			rel.fk = nautilus.ToSnake(name) + "_id"
+		} else if rel.fk == "" {
+			rel.fk = nautilus.ToSnake(nautilus.GetType(scheme)) + "_id"
+		}
+
+		// Has-many relations are held by slices, and others by a scheme
+		// or a pointer to scheme.
+		if (rel.kind == hasMany) != (rel.related.Kind() == reflect.Slice) {
+			return rel, fmt.Errorf("%w: %s relation cannot be held by %s", base.ErrUnsupportedType, rel.kind, rel.related)
+		}
+		if rel.related.Kind() == reflect.Slice {
+			rel.related = rel.related.Elem()
+		}
+		if rel.related.Kind() == reflect.Ptr {
+			rel.related = rel.related.Elem()
+		}
+
+		if !reflect.PtrTo(rel.related).Implements(reflect.TypeOf((*base.Scheme)(nil)).Elem()) {
+			return rel, fmt.Errorf("%w: %s is not a scheme", base.ErrUnsupportedType, rel.related)
+		}
+
+		// Relation tag may name the related scheme, which should be the
+		// scheme of field.
+		if name := tags[string(rel.kind)]; name != "true" && !strings.EqualFold(name, rel.related.Name()) {
+			return rel, fmt.Errorf("%w: %s field holds %s, not %s", base.ErrUnsupportedType, rel.field, rel.related.Name(), name)
+		}
+
+		return rel, nil
+	}
+
+	return relation{}, fmt.Errorf("%w: %s has no %s field", base.ErrUnsupportedType, nautilus.GetType(scheme), name)
+}
+
+// setRelation sets `schemes` on relation field of `scheme`. Fields of
+// single records are set to the first scheme, or to zero value if no
+// related record is found.
+func setRelation(scheme base.Scheme, rel relation, schemes []base.Scheme) {
+	field := reflect.ValueOf(scheme).Elem().FieldByName(rel.field)
+
+	switch {
+	case field.Kind() == reflect.Slice:
+		items := reflect.MakeSlice(field.Type(), 0, len(schemes))
+		for _, related := range schemes {
+			item := reflect.ValueOf(related)
+			if field.Type().Elem().Kind() == reflect.Struct {
+				item = item.Elem()
+			}
+			items = reflect.Append(items, item)
+		}
+		field.Set(items)
+	case len(schemes) == 0:
+		field.Set(reflect.Zero(field.Type()))
+	case field.Kind() == reflect.Ptr:
+		field.Set(reflect.ValueOf(schemes[0]))
+	default:
+		field.Set(reflect.ValueOf(schemes[0]).Elem())
+	}
+}
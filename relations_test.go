@@ -0,0 +1,203 @@
+package octopus
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	. "github.com/Kamva/octopus/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// owner is a scheme having has-one and has-many relations
+type owner struct {
+	Scheme
+	ID      int
+	Name    string
+	Pets    []pet    `sql:"has_many:Pet"`
+	License *license `sql:"has_one:License;fk:holder_id"`
+}
+
+func (o owner) GetID() interface{} {
+	return o.ID
+}
+
+// pet is a soft deletable scheme belonging to an owner
+type pet struct {
+	Scheme
+	SoftDelete
+	ID      int
+	OwnerID int
+	Name    string
+	Owner   *owner `sql:"belongs_to:owner"`
+}
+
+func (p pet) GetID() interface{} {
+	return p.ID
+}
+
+// license is a scheme with a custom foreign key of its owner
+type license struct {
+	Scheme
+	ID       int
+	HolderID int
+	Number   string
+}
+
+func (l license) GetID() interface{} {
+	return l.ID
+}
+
+// invalidRelation is a scheme with relations held by invalid types
+type invalidRelation struct {
+	Scheme
+	ID      int
+	Pet     pet      `sql:"has_many:Pet"`
+	Owners  []int    `sql:"has_many:Owner"`
+	License *license `sql:"has_one:Owner"`
+}
+
+func (i invalidRelation) GetID() interface{} {
+	return i.ID
+}
+
+// keeper is a scheme with a relation tag not naming the related scheme
+type keeper struct {
+	Scheme
+	ID   int
+	Pets []pet `sql:"has_many;fk:keeper_id"`
+}
+
+func (k keeper) GetID() interface{} {
+	return k.ID
+}
+
+// ----------------------
+//    Test functions
+// ----------------------
+
+func TestGetRelation(t *testing.T) {
+	t.Run("defaultKey", func(t *testing.T) {
+		rel, err := getRelation(&owner{}, "Pets")
+		assert.Nil(t, err)
+		assert.Equal(t, relation{field: "Pets", kind: hasMany, fk: "owner_id", related: reflect.TypeOf(pet{})}, rel)
+
+		rel, err = getRelation(&pet{}, "Owner")
+		assert.Nil(t, err)
+		assert.Equal(t, relation{field: "Owner", kind: belongsTo, fk: "owner_id", related: reflect.TypeOf(owner{})}, rel)
+	})
+
+	t.Run("customKey", func(t *testing.T) {
+		rel, err := getRelation(&owner{}, "License")
+
+		assert.Nil(t, err)
+		assert.Equal(t, relation{field: "License", kind: hasOne, fk: "holder_id", related: reflect.TypeOf(license{})}, rel)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, name := range []string{"Name", "Unknown"} {
+			_, err := getRelation(&owner{}, name)
+			assert.True(t, errors.Is(err, ErrUnsupportedType), name)
+		}
+
+		for _, name := range []string{"Pet", "Owners"} {
+			_, err := getRelation(&invalidRelation{}, name)
+			assert.True(t, errors.Is(err, ErrUnsupportedType), name)
+		}
+
+		_, err := getRelation(&invalidRelation{}, "License")
+		assert.EqualError(t, err, "unsupported type: License field holds license, not Owner")
+	})
+
+	t.Run("untypedTag", func(t *testing.T) {
+		rel, err := getRelation(&keeper{}, "Pets")
+		assert.Nil(t, err)
+		assert.Equal(t, relation{field: "Pets", kind: hasMany, fk: "keeper_id", related: reflect.TypeOf(pet{})}, rel)
+	})
+
+	t.Run("notColumn", func(t *testing.T) {
+		data := generateRecordData(&pet{OwnerID: 1, Name: "Rex", Owner: &owner{ID: 1}}, true)
+		assert.Nil(t, data.Get("owner"))
+
+		model := makeModel(&owner{}, base.DBConfig{Driver: base.PG})
+		assert.Equal(t, "id SERIAL PRIMARY KEY, name TEXT", model.getTableStruct().GetInfo())
+	})
+}
+
+func TestModel_Load(t *testing.T) {
+	config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+	defer clients.DropMemoryDatabase(t.Name())
+
+	owners := makeModel(&owner{}, config)
+	pets := makeModel(&pet{}, config)
+	licenses := makeModel(&license{}, config)
+
+	john := &owner{Name: "John"}
+	assert.Nil(t, owners.Create(john))
+	assert.Nil(t, owners.Create(&owner{Name: "Jane"}))
+	assert.Nil(t, licenses.Create(&license{HolderID: john.ID, Number: "L-1"}))
+
+	rex := &pet{OwnerID: john.ID, Name: "Rex"}
+	assert.Nil(t, pets.Create(rex))
+	assert.Nil(t, pets.Create(&pet{OwnerID: john.ID, Name: "Tom"}))
+	assert.Nil(t, pets.Create(&pet{OwnerID: 2, Name: "Max"}))
+
+	deleted := &pet{OwnerID: john.ID, Name: "Old"}
+	assert.Nil(t, pets.Create(deleted))
+	assert.Nil(t, pets.Delete(deleted))
+
+	t.Run("hasMany", func(t *testing.T) {
+		assert.Nil(t, owners.Load(john, "Pets", "License"))
+
+		assert.Len(t, john.Pets, 2)
+		assert.Equal(t, pet{ID: 1, OwnerID: 1, Name: "Rex"}, john.Pets[0])
+		assert.Equal(t, pet{ID: 2, OwnerID: 1, Name: "Tom"}, john.Pets[1])
+		assert.Equal(t, &license{ID: 1, HolderID: 1, Number: "L-1"}, john.License)
+	})
+
+	t.Run("belongsTo", func(t *testing.T) {
+		assert.Nil(t, pets.Load(rex, "Owner"))
+
+		assert.Equal(t, &owner{ID: 1, Name: "John"}, rex.Owner)
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		jane := &owner{ID: 2, License: &license{}}
+		assert.Nil(t, owners.Load(jane, "License"))
+		assert.Nil(t, jane.License)
+
+		stray := &pet{Name: "Stray", Owner: &owner{}}
+		assert.Nil(t, pets.Load(stray, "Owner"))
+		assert.Nil(t, stray.Owner)
+	})
+
+	t.Run("invalidRelation", func(t *testing.T) {
+		err := owners.Load(john, "Name")
+
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+	})
+	t.Run("openClient", func(t *testing.T) {
+		original := newMongo
+		defer func() { newMongo = original }()
+		newMongo = newMongoMock
+
+		// Lookup should not close the client prepared on model with context
+		mongoOwners := makeModel(&owner{}, base.DBConfig{Driver: base.Mongo})
+		model := mongoOwners.WithContext(context.Background())
+		assert.Nil(t, model.PrepareClient())
+		client := model.client.(*Client)
+
+		err := model.Load(&owner{ID: 1}, "Pets")
+
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+		assert.Equal(t, client, model.client)
+		client.AssertNotCalled(t, "Close")
+	})
+}
@@ -0,0 +1,18 @@
+package term
+
+import "github.com/Kamva/octopus/base"
+
+// And is a condition group using for checking that all
+// of the grouped conditions are satisfied. It is useful
+// for nesting conditions inside an Or or Not group.
+type And []base.Condition
+
+// GetField returns the field name, which is empty for condition groups
+func (c And) GetField() string {
+	return ""
+}
+
+// GetValue return the grouped conditions
+func (c And) GetValue() interface{} {
+	return []base.Condition(c)
+}
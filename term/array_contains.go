@@ -0,0 +1,18 @@
+package term
+
+// ArrayContains is a condition struct using for checking
+// array field in database contains all of given values
+type ArrayContains struct {
+	Field  string
+	Values []interface{}
+}
+
+// GetField returns the field name
+func (c ArrayContains) GetField() string {
+	return c.Field
+}
+
+// GetValue return the values to look for
+func (c ArrayContains) GetValue() interface{} {
+	return c.Values
+}
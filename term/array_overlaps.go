@@ -0,0 +1,18 @@
+package term
+
+// ArrayOverlaps is a condition struct using for checking
+// array field in database contains any of given values
+type ArrayOverlaps struct {
+	Field  string
+	Values []interface{}
+}
+
+// GetField returns the field name
+func (c ArrayOverlaps) GetField() string {
+	return c.Field
+}
+
+// GetValue return the values to look for
+func (c ArrayOverlaps) GetValue() interface{} {
+	return c.Values
+}
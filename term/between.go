@@ -0,0 +1,19 @@
+package term
+
+// Between is a condition struct using for checking field value
+// in database is in the given range, including its boundaries
+type Between struct {
+	Field string
+	From  interface{}
+	To    interface{}
+}
+
+// GetField returns the field name
+func (c Between) GetField() string {
+	return c.Field
+}
+
+// GetValue return the range boundaries
+func (c Between) GetValue() interface{} {
+	return []interface{}{c.From, c.To}
+}
@@ -0,0 +1,20 @@
+package term
+
+// Contains is a condition struct using for checking field value
+// in database contains the given value. Wildcard characters
+// in value are escaped, so it is safe to pass user input.
+type Contains struct {
+	Field      string
+	Value      string
+	IgnoreCase bool
+}
+
+// GetField returns the field name
+func (c Contains) GetField() string {
+	return c.Field
+}
+
+// GetValue return the value to search
+func (c Contains) GetValue() interface{} {
+	return c.Value
+}
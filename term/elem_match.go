@@ -0,0 +1,22 @@
+package term
+
+import "github.com/Kamva/octopus/base"
+
+// ElemMatch is a condition struct using for checking an
+// array of sub-documents in database has at least one
+// element matching all of given conditions. Condition
+// fields are relative to the array elements.
+type ElemMatch struct {
+	Field      string
+	Conditions []base.Condition
+}
+
+// GetField returns the field name
+func (c ElemMatch) GetField() string {
+	return c.Field
+}
+
+// GetValue return the conditions of array elements
+func (c ElemMatch) GetValue() interface{} {
+	return c.Conditions
+}
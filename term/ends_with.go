@@ -0,0 +1,20 @@
+package term
+
+// EndsWith is a condition struct using for checking field value
+// in database ends with the given value. Wildcard characters
+// in value are escaped, so it is safe to pass user input.
+type EndsWith struct {
+	Field      string
+	Value      string
+	IgnoreCase bool
+}
+
+// GetField returns the field name
+func (c EndsWith) GetField() string {
+	return c.Field
+}
+
+// GetValue return the value to search
+func (c EndsWith) GetValue() interface{} {
+	return c.Value
+}
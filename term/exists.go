@@ -0,0 +1,18 @@
+package term
+
+// Exists is a condition struct using for checking field
+// is present in document on MongoDB, or the field value
+// is not null on other databases
+type Exists struct {
+	Field string
+}
+
+// GetField returns the field name
+func (c Exists) GetField() string {
+	return c.Field
+}
+
+// GetValue return the value to compare
+func (c Exists) GetValue() interface{} {
+	return nil
+}
@@ -0,0 +1,18 @@
+package term
+
+// ILike is a condition struct using for matching field
+// value in database with a LIKE pattern case insensitively
+type ILike struct {
+	Field   string
+	Pattern string
+}
+
+// GetField returns the field name
+func (c ILike) GetField() string {
+	return c.Field
+}
+
+// GetValue return the pattern to match
+func (c ILike) GetValue() interface{} {
+	return c.Pattern
+}
@@ -0,0 +1,20 @@
+package term
+
+// Like is a condition struct using for matching field value
+// in database with a LIKE pattern, in which `%` matches any
+// sequence of characters and `_` matches a single character.
+// Its case sensitivity depends on the database collation.
+type Like struct {
+	Field   string
+	Pattern string
+}
+
+// GetField returns the field name
+func (c Like) GetField() string {
+	return c.Field
+}
+
+// GetValue return the pattern to match
+func (c Like) GetValue() interface{} {
+	return c.Pattern
+}
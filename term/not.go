@@ -0,0 +1,19 @@
+package term
+
+import "github.com/Kamva/octopus/base"
+
+// Not is a condition struct using for negating the
+// given condition, which could be a condition group
+type Not struct {
+	Condition base.Condition
+}
+
+// GetField returns the field name of negated condition
+func (c Not) GetField() string {
+	return c.Condition.GetField()
+}
+
+// GetValue return the negated condition
+func (c Not) GetValue() interface{} {
+	return c.Condition
+}
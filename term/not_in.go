@@ -0,0 +1,18 @@
+package term
+
+// NotIn is a condition struct using for checking field
+// value in database is not in any of given values
+type NotIn struct {
+	Field  string
+	Values []interface{}
+}
+
+// GetField returns the field name
+func (c NotIn) GetField() string {
+	return c.Field
+}
+
+// GetValue return the value to compare
+func (c NotIn) GetValue() interface{} {
+	return c.Values
+}
@@ -0,0 +1,17 @@
+package term
+
+import "github.com/Kamva/octopus/base"
+
+// Or is a condition group using for checking that at
+// least one of the grouped conditions is satisfied
+type Or []base.Condition
+
+// GetField returns the field name, which is empty for condition groups
+func (c Or) GetField() string {
+	return ""
+}
+
+// GetValue return the grouped conditions
+func (c Or) GetValue() interface{} {
+	return []base.Condition(c)
+}
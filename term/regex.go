@@ -0,0 +1,18 @@
+package term
+
+// Regex is a condition struct using for matching field
+// value in database with a regular expression pattern
+type Regex struct {
+	Field   string
+	Pattern string
+}
+
+// GetField returns the field name
+func (c Regex) GetField() string {
+	return c.Field
+}
+
+// GetValue return the pattern to match
+func (c Regex) GetValue() interface{} {
+	return c.Pattern
+}
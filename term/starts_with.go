@@ -0,0 +1,20 @@
+package term
+
+// StartsWith is a condition struct using for checking field value
+// in database starts with the given value. Wildcard characters
+// in value are escaped, so it is safe to pass user input.
+type StartsWith struct {
+	Field      string
+	Value      string
+	IgnoreCase bool
+}
+
+// GetField returns the field name
+func (c StartsWith) GetField() string {
+	return c.Field
+}
+
+// GetValue return the value to search
+func (c StartsWith) GetValue() interface{} {
+	return c.Value
+}
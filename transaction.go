@@ -0,0 +1,90 @@
+package octopus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+)
+
+// Tx is a database transaction. Models bound to a transaction by WithTx
+// run all of their operations in the transaction.
+type Tx struct {
+	config base.DBConfig
+	client base.TxClient
+}
+
+// Transaction starts a transaction on database of `config` and runs `fn` in
+// it. The transaction is rolled back if `fn` returns an error or panics and
+// committed otherwise. Panics are re-raised after rolling back. MongoDB
+// transactions run in a session of the official MongoDB driver, as the mgo
+// driver has no support for transactions.
+func Transaction(config base.DBConfig, fn func(tx *Tx) error) error {
+	return TransactionContext(context.Background(), config, fn)
+}
+
+// TransactionContext is like Transaction but starts the transaction with
+// `ctx`. The transaction is rolled back by database if context is canceled
+// before commit, and all statements of the transaction run with `ctx`.
+func TransactionContext(ctx context.Context, config base.DBConfig, fn func(tx *Tx) error) (err error) {
+	client, err := acquireClient(&config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if binder, ok := client.(base.ContextBinder); ok {
+		client = binder.WithContext(ctx)
+		defer client.Close()
+	}
+
+	transactional, ok := client.(base.Transactional)
+	if !ok {
+		return fmt.Errorf("%w: %s driver does not support transactions", base.ErrInvalidDriver, config.Driver)
+	}
+
+	txClient, err := transactional.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			_ = txClient.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(&Tx{config: config, client: txClient}); err != nil {
+		_ = txClient.Rollback()
+		return err
+	}
+
+	return clients.TranslateError(txClient.Commit())
+}
+
+// WithTx returns a copy of model which is bound to `tx`, so its operations
+// run in the transaction. Operations of the model return ErrInvalidTx if
+// `tx` is nil or the model and the transaction are not on the same database.
+func (m *Model) WithTx(tx *Tx) *Model {
+	model := *m
+	model.client = nil
+	model.tx = tx
+
+	if tx == nil {
+		model.err = fmt.Errorf("%w: model could not be bound to a nil transaction", base.ErrInvalidTx)
+	} else if !sameDatabase(m.config, tx.config) {
+		model.err = fmt.Errorf("%w: model and transaction should be on the same database", base.ErrInvalidTx)
+	}
+
+	return &model
+}
+
+// sameDatabase checks whether `a` and `b` configs connect to the same database
+func sameDatabase(a base.DBConfig, b base.DBConfig) bool {
+	return a.Driver == b.Driver &&
+		a.Host == b.Host &&
+		a.Port == b.Port &&
+		a.Database == b.Database
+}
@@ -0,0 +1,178 @@
+package octopus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	. "github.com/Kamva/octopus/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// txClientMock is a transaction client based on Client mock
+type txClientMock struct {
+	*Client
+}
+
+func (txClientMock) Commit() error { return nil }
+
+func (txClientMock) Rollback() error { return nil }
+
+func initTxModel(t *testing.T) (Model, base.DBConfig) {
+	t.Helper()
+
+	config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+	clients.DropMemoryDatabase(t.Name())
+
+	model := makeModel(&account{}, config)
+	assert.Nil(t, model.Create(&account{Name: "John", Age: 20}))
+
+	return model, config
+}
+
+func countAccounts(t *testing.T, model Model) int {
+	t.Helper()
+
+	count, err := model.Where().Count()
+	assert.Nil(t, err)
+
+	return count
+}
+
+// ----------------
+//    Unit Tests
+// ----------------
+
+func TestTransaction(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		model, config := initTxModel(t)
+
+		err := Transaction(config, func(tx *Tx) error {
+			txModel := model.WithTx(tx)
+			if err := txModel.Create(&account{Name: "Jane", Age: 21}); err != nil {
+				return err
+			}
+
+			// Changes are not visible out of transaction before commit
+			assert.Equal(t, 1, countAccounts(t, model))
+
+			return txModel.Update(&account{ID: 1, Name: "John", Age: 30})
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, countAccounts(t, model))
+
+		res, err := model.Find(1)
+		assert.Nil(t, err)
+		assert.Equal(t, &account{ID: 1, Name: "John", Age: 30}, res)
+	})
+
+	t.Run("rollbackOnError", func(t *testing.T) {
+		model, config := initTxModel(t)
+
+		err := Transaction(config, func(tx *Tx) error {
+			assert.Nil(t, model.WithTx(tx).Create(&account{Name: "Jane", Age: 21}))
+
+			return errTest
+		})
+
+		assert.Equal(t, errTest, err)
+		assert.Equal(t, 1, countAccounts(t, model))
+	})
+
+	t.Run("rollbackOnPanic", func(t *testing.T) {
+		model, config := initTxModel(t)
+
+		assert.Panics(t, func() {
+			_ = Transaction(config, func(tx *Tx) error {
+				assert.Nil(t, model.WithTx(tx).Create(&account{Name: "Jane", Age: 21}))
+
+				panic("test")
+			})
+		})
+
+		assert.Equal(t, 1, countAccounts(t, model))
+	})
+
+	t.Run("unsupportedClient", func(t *testing.T) {
+		original := newMongo
+		defer func() { newMongo = original }()
+
+		// Client mock does not implement base.Transactional
+		newMongo = newMongoMock
+		called := false
+		err := Transaction(base.DBConfig{Driver: base.Mongo}, func(tx *Tx) error {
+			called = true
+			return nil
+		})
+
+		assert.True(t, errors.Is(err, ErrInvalidDriver))
+		assert.EqualError(t, err, "invalid database driver: mongo driver does not support transactions")
+		assert.False(t, called)
+	})
+}
+
+func TestTransactionContext(t *testing.T) {
+	t.Run("canceled", func(t *testing.T) {
+		model, config := initTxModel(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := TransactionContext(ctx, config, func(tx *Tx) error {
+			assert.Nil(t, model.WithTx(tx).Create(&account{Name: "Jane", Age: 21}))
+			cancel()
+
+			return ctx.Err()
+		})
+
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, 1, countAccounts(t, model))
+	})
+}
+
+func TestModel_WithTx(t *testing.T) {
+	t.Run("bound", func(t *testing.T) {
+		model, config := initTxModel(t)
+		client := new(Client)
+		tx := &Tx{config: config, client: &txClientMock{Client: client}}
+
+		txModel := model.WithTx(tx)
+		txModel.PrepareClient()
+
+		assert.Equal(t, tx.client, txModel.client)
+		assert.Nil(t, model.tx)
+
+		txModel.CloseClient()
+
+		assert.Nil(t, txModel.client)
+		client.AssertNotCalled(t, "Close")
+	})
+
+	t.Run("differentDatabase", func(t *testing.T) {
+		model, _ := initTxModel(t)
+		tx := &Tx{config: base.DBConfig{Driver: base.Memory, Database: "other"}}
+
+		txModel := model.WithTx(tx)
+		err := txModel.Create(&account{Name: "test"})
+		_, findErr := txModel.Where().All()
+
+		assert.True(t, errors.Is(err, ErrInvalidTx))
+		assert.True(t, errors.Is(findErr, ErrInvalidTx))
+	})
+	t.Run("nilTx", func(t *testing.T) {
+		model, _ := initTxModel(t)
+
+		txModel := model.WithTx(nil)
+		err := txModel.Create(&account{Name: "test"})
+		_, findErr := txModel.Where().All()
+
+		assert.True(t, errors.Is(err, ErrInvalidTx))
+		assert.True(t, errors.Is(findErr, ErrInvalidTx))
+		assert.Nil(t, model.err)
+	})
+}
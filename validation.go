@@ -0,0 +1,177 @@
+package octopus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Kamva/octopus/base"
+)
+
+// Validator is implemented by schemes that validate themselves, besides the
+// rules of `validate` tags, before they are created or updated. Fields of
+// a returned ValidationError are merged with errors of tag rules.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is the error of a scheme field failing a validation rule
+type FieldError struct {
+	// Field is the name of scheme field
+	Field string
+
+	// Rule is the failed validation rule, e.g. `max`
+	Rule string
+
+	// Message describes the error
+	Message string
+}
+
+// ValidationError is returned when a scheme is not valid, and contains the
+// errors of all invalid fields. It can be checked with errors.Is against
+// ErrValidation.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error returns messages of all field errors
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		messages = append(messages, fieldError.Message)
+	}
+
+	return base.ErrValidation.Error() + ": " + strings.Join(messages, ", ")
+}
+
+// Is reports whether `target` is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == base.ErrValidation
+}
+
+// emailPattern is a loose pattern of email addresses
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// validate checks scheme fields against rules of their `validate` tag, and
+// runs Validate method of scheme if implemented. It returns a ValidationError
+// if scheme is not valid, and ErrUnsupportedType if a rule is unknown or
+// malformed.
+func validate(scheme base.Scheme) error {
+	validationError := &ValidationError{}
+
+	for _, fieldData := range getSchemeData(scheme) {
+		rules := fieldData.Tags.Get("validate")
+		if rules == "" || !fieldData.Exported {
+			continue
+		}
+
+		value := reflect.ValueOf(fieldData.Value)
+		for _, rule := range strings.Split(rules, ",") {
+			fieldError, err := validateRule(fieldData.Name, value, rule)
+			if err != nil {
+				return err
+			}
+			if fieldError != nil {
+				validationError.Errors = append(validationError.Errors, *fieldError)
+			}
+		}
+	}
+
+	if validator, ok := scheme.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			var schemeError *ValidationError
+			if errors.As(err, &schemeError) {
+				validationError.Errors = append(validationError.Errors, schemeError.Errors...)
+			} else {
+				validationError.Errors = append(validationError.Errors, FieldError{Message: err.Error()})
+			}
+		}
+	}
+
+	if len(validationError.Errors) == 0 {
+		return nil
+	}
+
+	return validationError
+}
+
+// validateRule checks `value` of `name` field against validation `rule`, and
+// returns the field error if it fails. Rules other than `required` pass on
+// nil values. It returns an error if the rule could not be checked.
+func validateRule(name string, value reflect.Value, rule string) (*FieldError, error) {
+	rule, param := strings.TrimSpace(rule), ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		rule, param = rule[:i], rule[i+1:]
+	}
+
+	if rule == "required" {
+		if !value.IsValid() || value.IsZero() {
+			return &FieldError{Field: name, Rule: rule, Message: fmt.Sprintf("%s is required", name)}, nil
+		}
+
+		return nil, nil
+	}
+
+	for value.IsValid() && value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return nil, nil
+	}
+
+	switch rule {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s rule of %s field: %v", base.ErrUnsupportedType, rule, name, err)
+		}
+
+		size, unit, err := validationSize(value)
+		if err != nil {
+			return nil, err
+		}
+		if (rule == "min" && size < limit) || (rule == "max" && size > limit) {
+			bound := map[string]string{"min": "at least", "max": "at most"}[rule]
+			message := strings.TrimSpace(fmt.Sprintf("%s must be %s %s %s", name, bound, param, unit))
+
+			return &FieldError{Field: name, Rule: rule, Message: message}, nil
+		}
+	case "email":
+		if value.Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: email rule of %s field needs a string", base.ErrUnsupportedType, name)
+		}
+
+		if value.String() != "" && !emailPattern.MatchString(value.String()) {
+			return &FieldError{Field: name, Rule: rule, Message: fmt.Sprintf("%s must be a valid email address", name)}, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown validation rule %s of %s field", base.ErrUnsupportedType, rule, name)
+	}
+
+	return nil, nil
+}
+
+// validationSize returns the size of `value` compared by min/max rules with
+// its unit, which is the length of strings, slices and maps, and the value of
+// numbers. Values of other types have no size.
+func validationSize(value reflect.Value) (float64, string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), "characters", nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), "items", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "", nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", nil
+	}
+
+	return 0, "", fmt.Errorf("%w: min/max rules do not support %s values", base.ErrUnsupportedType, value.Type())
+}
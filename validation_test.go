@@ -0,0 +1,144 @@
+package octopus
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Kamva/octopus/base"
+	"github.com/Kamva/octopus/clients"
+	. "github.com/Kamva/octopus/internal"
+	"github.com/Kamva/octopus/term"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------
+//    Helper functions
+// ----------------------
+
+// signup is a scheme with validation rules
+type signup struct {
+	Scheme
+	ID    int
+	Name  string   `validate:"required,max=5"`
+	Email string   `validate:"required,email"`
+	Age   int      `validate:"min=18"`
+	Tags  []string `validate:"max=2"`
+	Nick  *string  `validate:"min=2"`
+}
+
+func (s signup) GetID() interface{} {
+	return s.ID
+}
+
+func (s signup) Validate() error {
+	if s.Name == "admin" {
+		return &ValidationError{Errors: []FieldError{{Field: "Name", Rule: "reserved", Message: "Name is reserved"}}}
+	}
+
+	return nil
+}
+
+// malformedRule is a scheme with a malformed validation rule
+type malformedRule struct {
+	Scheme
+	ID   int
+	Name string `validate:"max=abc"`
+}
+
+func (m malformedRule) GetID() interface{} {
+	return m.ID
+}
+
+// ----------------------
+//    Test functions
+// ----------------------
+
+func TestValidate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		nick := "jd"
+
+		assert.Nil(t, validate(&signup{Name: "John", Email: "john@doe.com", Age: 18, Nick: &nick}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		nick := "j"
+		err := validate(&signup{Name: "Johnny", Age: 17, Tags: []string{"a", "b", "c"}, Nick: &nick})
+
+		var validationError *ValidationError
+		assert.True(t, errors.As(err, &validationError))
+		assert.True(t, errors.Is(err, ErrValidation))
+		assert.Equal(t, []FieldError{
+			{Field: "Name", Rule: "max", Message: "Name must be at most 5 characters"},
+			{Field: "Email", Rule: "required", Message: "Email is required"},
+			{Field: "Age", Rule: "min", Message: "Age must be at least 18"},
+			{Field: "Tags", Rule: "max", Message: "Tags must be at most 2 items"},
+			{Field: "Nick", Rule: "min", Message: "Nick must be at least 2 characters"},
+		}, validationError.Errors)
+	})
+
+	t.Run("email", func(t *testing.T) {
+		err := validate(&signup{Name: "John", Email: "john.doe", Age: 20})
+
+		assert.Equal(t, "validation failed: Email must be a valid email address", err.Error())
+	})
+
+	t.Run("validator", func(t *testing.T) {
+		err := validate(&signup{Name: "admin", Age: 20})
+
+		var validationError *ValidationError
+		assert.True(t, errors.As(err, &validationError))
+		assert.Equal(t, []FieldError{
+			{Field: "Email", Rule: "required", Message: "Email is required"},
+			{Field: "Name", Rule: "reserved", Message: "Name is reserved"},
+		}, validationError.Errors)
+	})
+
+	t.Run("invalidRule", func(t *testing.T) {
+		for _, rule := range []string{"uuid", "max=abc", "min"} {
+			fieldError, err := validateRule("Name", reflect.ValueOf("John"), rule)
+
+			assert.Nil(t, fieldError)
+			assert.True(t, errors.Is(err, ErrUnsupportedType), rule)
+		}
+
+		_, err := validateRule("Tags", reflect.ValueOf(true), "max=3")
+		assert.True(t, errors.Is(err, ErrUnsupportedType))
+
+		err = validate(&malformedRule{Name: "John"})
+		assert.EqualError(t, err, "unsupported type: invalid max rule of Name field: strconv.ParseFloat: parsing \"abc\": invalid syntax")
+	})
+}
+
+func TestModel_validation(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		model := makeModel(&signup{}, base.DBConfig{Driver: base.PG})
+
+		client := new(Client)
+		model.client = client
+
+		err := model.Create(&signup{Name: "John"})
+
+		assert.True(t, errors.Is(err, ErrValidation))
+		client.AssertNotCalled(t, "Insert")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		config := base.DBConfig{Driver: base.Memory, Database: t.Name()}
+		defer clients.DropMemoryDatabase(t.Name())
+
+		model := makeModel(&signup{}, config)
+		s := &signup{Name: "John", Email: "john@doe.com", Age: 20}
+		assert.Nil(t, model.Create(s))
+
+		s.Age = 10
+		assert.True(t, errors.Is(model.Update(s), ErrValidation))
+
+		_, err := model.Where(term.Equal{Field: "id", Value: s.ID}).Update(&signup{Name: "Jane"})
+		assert.True(t, errors.Is(err, ErrValidation))
+
+		res, err := model.Find(s.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 20, res.(*signup).Age)
+	})
+}